@@ -0,0 +1,579 @@
+package raml
+
+import (
+	"strings"
+
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/acronis/go-raml/stacktrace"
+)
+
+// apiMethods are HTTP methods that may be declared in resources.
+var apiMethods = map[string]struct{}{
+	"get": {}, "patch": {}, "put": {}, "post": {}, "delete": {}, "options": {}, "head": {},
+}
+
+// apiDeclarations are nodes of the API root that are declared as in libraries.
+var apiDeclarations = map[string]struct{}{
+	"uses": {}, "types": {}, "annotationTypes": {},
+}
+
+// API is the RAML 1.0 API definition.
+//
+// Types, annotation types, uses and annotations of the root are kept in the embedded Library,
+// which is stored as the fragment at the API location, so that references to types are resolved as in libraries.
+//
+// NOTE: Traits, resource types, documentation and queryString are not supported yet.
+type API struct {
+	*Library
+
+	Title             string
+	Description       string
+	Version           string
+	BaseURI           string
+	BaseURIParameters *orderedmap.OrderedMap[string, Property]
+	Protocols         []string
+	// MediaType is the default media type of bodies that are declared without media type.
+	MediaType       []string
+	SecuritySchemes *orderedmap.OrderedMap[string, *SecurityScheme]
+	SecuredBy       []*SecuredBy
+	// Resources are keyed by the relative URI, e.g. "/users".
+	Resources *orderedmap.OrderedMap[string, *Resource]
+}
+
+// Resource is a resource of the API.
+type Resource struct {
+	// Path is the relative URI of the resource, e.g. "/{id}".
+	Path          string
+	DisplayName   string
+	Description   string
+	URIParameters *orderedmap.OrderedMap[string, Property]
+	SecuredBy     []*SecuredBy
+	// Methods are keyed by the lower case HTTP method.
+	Methods   *orderedmap.OrderedMap[string, *Method]
+	Resources *orderedmap.OrderedMap[string, *Resource]
+
+	Location string
+	stacktrace.Position
+}
+
+// Method is an HTTP method of the resource.
+type Method struct {
+	Method          string
+	DisplayName     string
+	Description     string
+	QueryParameters *orderedmap.OrderedMap[string, Property]
+	Headers         *orderedmap.OrderedMap[string, Property]
+	// Body is keyed by media type.
+	Body *orderedmap.OrderedMap[string, *Body]
+	// Responses are keyed by HTTP status code.
+	Responses *orderedmap.OrderedMap[string, *Response]
+	// SecuredBy overrides the security of the resource if not nil.
+	SecuredBy []*SecuredBy
+
+	Location string
+	stacktrace.Position
+}
+
+// Body is a body of the request or response in the media type.
+type Body struct {
+	MediaType string
+	Shape     *Shape
+
+	Location string
+	stacktrace.Position
+}
+
+// Response is a response of the method.
+type Response struct {
+	Code        string
+	Description string
+	Headers     *orderedmap.OrderedMap[string, Property]
+	// Body is keyed by media type.
+	Body *orderedmap.OrderedMap[string, *Body]
+
+	Location string
+	stacktrace.Position
+}
+
+// SecurityScheme is a security scheme declared in the API.
+type SecurityScheme struct {
+	Name        string
+	Type        string
+	DisplayName string
+	Description string
+	// DescribedBy holds headers and query parameters that the scheme uses.
+	DescribedBy *SecuritySchemeDescription
+	// Settings are decoded as is, e.g. authorizationUri, accessTokenUri, authorizationGrants and scopes of OAuth 2.0.
+	Settings map[string]interface{}
+
+	Location string
+	stacktrace.Position
+}
+
+// SecuritySchemeDescription describes the transport of the security scheme.
+type SecuritySchemeDescription struct {
+	Headers         *orderedmap.OrderedMap[string, Property]
+	QueryParameters *orderedmap.OrderedMap[string, Property]
+}
+
+// SecuredBy is a reference to the security scheme. Empty name stands for null, i.e. no security.
+type SecuredBy struct {
+	Name string
+	// Scopes are OAuth 2.0 scopes required by the reference.
+	Scopes []string
+
+	stacktrace.Position
+}
+
+func (r *RAML) MakeAPI(path string) *API {
+	return &API{
+		Library: r.MakeLibrary(path),
+	}
+}
+
+// UnmarshalYAML unmarshals an API from a yaml.Node, implementing the yaml.Unmarshaler interface
+func (a *API) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.MappingNode {
+		return stacktrace.New("must be map", a.Location, stacktrace.WithNodePosition(value))
+	}
+	declarations := &yaml.Node{Kind: yaml.MappingNode}
+	var resources []*yaml.Node
+	var securedBy *yaml.Node
+	for i := 0; i != len(value.Content); i += 2 {
+		node := value.Content[i]
+		valueNode := value.Content[i+1]
+		_, isDeclaration := apiDeclarations[node.Value]
+		var err error
+		switch {
+		case isDeclaration || IsCustomDomainExtensionNode(node.Value):
+			declarations.Content = append(declarations.Content, node, valueNode)
+		case strings.HasPrefix(node.Value, "/"):
+			// Bodies depend on the default media type, so resources are parsed after the root.
+			resources = append(resources, node, valueNode)
+		case node.Value == "title":
+			err = valueNode.Decode(&a.Title)
+		case node.Value == "description":
+			err = valueNode.Decode(&a.Description)
+		case node.Value == "version":
+			err = valueNode.Decode(&a.Version)
+		case node.Value == "baseUri":
+			err = valueNode.Decode(&a.BaseURI)
+		case node.Value == "baseUriParameters":
+			a.BaseURIParameters, err = a.makeParameters(valueNode)
+		case node.Value == "protocols":
+			a.Protocols, err = decodeStrings(valueNode)
+		case node.Value == "mediaType":
+			a.MediaType, err = decodeStrings(valueNode)
+		case node.Value == "securitySchemes":
+			err = a.unmarshalSecuritySchemes(valueNode)
+		case node.Value == "securedBy":
+			// References are checked after all security schemes are declared.
+			securedBy = valueNode
+		}
+		if err != nil {
+			return stacktrace.NewWrapped("parse "+node.Value, err, a.Location, stacktrace.WithNodePosition(valueNode))
+		}
+	}
+	if a.Title == "" {
+		return stacktrace.New("title is required", a.Location, stacktrace.WithNodePosition(value))
+	}
+	if securedBy != nil {
+		var err error
+		if a.SecuredBy, err = a.makeSecuredBy(securedBy); err != nil {
+			return stacktrace.NewWrapped("parse securedBy", err, a.Location, stacktrace.WithNodePosition(securedBy))
+		}
+	}
+	if err := a.Library.UnmarshalYAML(declarations); err != nil {
+		return stacktrace.NewWrapped("parse declarations", err, a.Location, stacktrace.WithNodePosition(value))
+	}
+	// Types of parameters and bodies are looked up in declarations, which are often omitted in APIs.
+	if a.Types == nil {
+		a.Types = orderedmap.New[string, *Shape]()
+	}
+	if a.Uses == nil {
+		a.Uses = orderedmap.New[string, *LibraryLink]()
+	}
+	a.Resources = orderedmap.New[string, *Resource](len(resources) / 2)
+	for i := 0; i != len(resources); i += 2 {
+		resource, err := a.makeResource(resources[i], resources[i+1])
+		if err != nil {
+			return stacktrace.NewWrapped("parse resource", err, a.Location, stacktrace.WithNodePosition(resources[i]))
+		}
+		a.Resources.Set(resource.Path, resource)
+	}
+	return nil
+}
+
+func (a *API) unmarshalSecuritySchemes(value *yaml.Node) error {
+	if value.Tag == "!!null" {
+		return nil
+	}
+	if value.Kind != yaml.MappingNode {
+		return stacktrace.New("must be map", a.Location, stacktrace.WithNodePosition(value))
+	}
+	a.SecuritySchemes = orderedmap.New[string, *SecurityScheme](len(value.Content) / 2)
+	for i := 0; i != len(value.Content); i += 2 {
+		name := value.Content[i].Value
+		data := value.Content[i+1]
+		if data.Kind != yaml.MappingNode {
+			return stacktrace.New("security scheme must be map", a.Location, stacktrace.WithNodePosition(data))
+		}
+		ss := &SecurityScheme{
+			Name:     name,
+			Location: a.Location,
+			Position: stacktrace.Position{Line: data.Line, Column: data.Column},
+		}
+		for j := 0; j != len(data.Content); j += 2 {
+			node := data.Content[j]
+			valueNode := data.Content[j+1]
+			var err error
+			switch node.Value {
+			case "type":
+				err = valueNode.Decode(&ss.Type)
+			case "displayName":
+				err = valueNode.Decode(&ss.DisplayName)
+			case "description":
+				err = valueNode.Decode(&ss.Description)
+			case "settings":
+				err = valueNode.Decode(&ss.Settings)
+			case "describedBy":
+				ss.DescribedBy, err = a.makeSecuritySchemeDescription(valueNode)
+			}
+			if err != nil {
+				return stacktrace.NewWrapped("parse "+node.Value, err, a.Location, stacktrace.WithNodePosition(valueNode))
+			}
+		}
+		if ss.Type == "" {
+			return stacktrace.New("security scheme type is required", a.Location, stacktrace.WithNodePosition(data),
+				stacktrace.WithInfo("name", name))
+		}
+		a.SecuritySchemes.Set(name, ss)
+	}
+	return nil
+}
+
+func (a *API) makeSecuritySchemeDescription(value *yaml.Node) (*SecuritySchemeDescription, error) {
+	if value.Kind != yaml.MappingNode {
+		return nil, stacktrace.New("must be map", a.Location, stacktrace.WithNodePosition(value))
+	}
+	d := &SecuritySchemeDescription{}
+	for i := 0; i != len(value.Content); i += 2 {
+		node := value.Content[i]
+		valueNode := value.Content[i+1]
+		var err error
+		switch node.Value {
+		case "headers":
+			d.Headers, err = a.makeParameters(valueNode)
+		case "queryParameters":
+			d.QueryParameters, err = a.makeParameters(valueNode)
+		}
+		if err != nil {
+			return nil, stacktrace.NewWrapped("parse "+node.Value, err, a.Location, stacktrace.WithNodePosition(valueNode))
+		}
+	}
+	return d, nil
+}
+
+// makeSecuredBy makes references to security schemes, which are names, null or maps of names to parameters.
+func (a *API) makeSecuredBy(value *yaml.Node) ([]*SecuredBy, error) {
+	if value.Kind != yaml.SequenceNode {
+		return nil, stacktrace.New("must be array", a.Location, stacktrace.WithNodePosition(value))
+	}
+	refs := make([]*SecuredBy, 0, len(value.Content))
+	for _, item := range value.Content {
+		ref := &SecuredBy{Position: stacktrace.Position{Line: item.Line, Column: item.Column}}
+		switch {
+		case item.Tag == "!!null":
+		case item.Kind == yaml.ScalarNode:
+			ref.Name = item.Value
+		case item.Kind == yaml.MappingNode && len(item.Content) == 2:
+			ref.Name = item.Content[0].Value
+			if scopes := yamlMapGet(item.Content[1], "scopes"); scopes != nil {
+				var err error
+				if ref.Scopes, err = decodeStrings(scopes); err != nil {
+					return nil, stacktrace.NewWrapped("parse scopes", err, a.Location, stacktrace.WithNodePosition(scopes))
+				}
+			}
+		default:
+			return nil, stacktrace.New("security scheme reference must be name, null or map", a.Location, stacktrace.WithNodePosition(item))
+		}
+		if ref.Name != "" && !a.hasSecurityScheme(ref.Name) {
+			return nil, stacktrace.New("security scheme is not declared", a.Location, stacktrace.WithNodePosition(item),
+				stacktrace.WithInfo("name", ref.Name))
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+func (a *API) hasSecurityScheme(name string) bool {
+	if a.SecuritySchemes == nil {
+		return false
+	}
+	_, ok := a.SecuritySchemes.Get(name)
+	return ok
+}
+
+func (a *API) makeResource(key *yaml.Node, value *yaml.Node) (*Resource, error) {
+	res := &Resource{
+		Path:     key.Value,
+		Location: a.Location,
+		Position: stacktrace.Position{Line: key.Line, Column: key.Column},
+	}
+	if value.Tag == "!!null" {
+		return res, nil
+	}
+	if value.Kind != yaml.MappingNode {
+		return nil, stacktrace.New("resource must be map", a.Location, stacktrace.WithNodePosition(value))
+	}
+	// Security of the resource applies to its methods, so it is parsed first.
+	if v := yamlMapGet(value, "securedBy"); v != nil {
+		var err error
+		if res.SecuredBy, err = a.makeSecuredBy(v); err != nil {
+			return nil, stacktrace.NewWrapped("parse securedBy", err, a.Location, stacktrace.WithNodePosition(v))
+		}
+	}
+	for i := 0; i != len(value.Content); i += 2 {
+		node := value.Content[i]
+		valueNode := value.Content[i+1]
+		var err error
+		if strings.HasPrefix(node.Value, "/") {
+			var nested *Resource
+			if nested, err = a.makeResource(node, valueNode); err != nil {
+				return nil, stacktrace.NewWrapped("parse resource", err, a.Location, stacktrace.WithNodePosition(node))
+			}
+			if res.Resources == nil {
+				res.Resources = orderedmap.New[string, *Resource]()
+			}
+			res.Resources.Set(nested.Path, nested)
+			continue
+		}
+		if _, ok := apiMethods[node.Value]; ok {
+			var method *Method
+			if method, err = a.makeMethod(node, valueNode, res.SecuredBy); err != nil {
+				return nil, stacktrace.NewWrapped("parse method", err, a.Location, stacktrace.WithNodePosition(node))
+			}
+			if res.Methods == nil {
+				res.Methods = orderedmap.New[string, *Method]()
+			}
+			res.Methods.Set(method.Method, method)
+			continue
+		}
+		switch node.Value {
+		case "displayName":
+			err = valueNode.Decode(&res.DisplayName)
+		case "description":
+			err = valueNode.Decode(&res.Description)
+		case "uriParameters":
+			res.URIParameters, err = a.makeParameters(valueNode)
+		}
+		if err != nil {
+			return nil, stacktrace.NewWrapped("parse "+node.Value, err, a.Location, stacktrace.WithNodePosition(valueNode))
+		}
+	}
+	return res, nil
+}
+
+func (a *API) makeMethod(key *yaml.Node, value *yaml.Node, securedBy []*SecuredBy) (*Method, error) {
+	m := &Method{
+		Method:    key.Value,
+		SecuredBy: securedBy,
+		Location:  a.Location,
+		Position:  stacktrace.Position{Line: key.Line, Column: key.Column},
+	}
+	if value.Tag == "!!null" {
+		return m, nil
+	}
+	if value.Kind != yaml.MappingNode {
+		return nil, stacktrace.New("method must be map", a.Location, stacktrace.WithNodePosition(value))
+	}
+	for i := 0; i != len(value.Content); i += 2 {
+		node := value.Content[i]
+		valueNode := value.Content[i+1]
+		var err error
+		switch node.Value {
+		case "displayName":
+			err = valueNode.Decode(&m.DisplayName)
+		case "description":
+			err = valueNode.Decode(&m.Description)
+		case "queryParameters":
+			m.QueryParameters, err = a.makeParameters(valueNode)
+		case "headers":
+			m.Headers, err = a.makeParameters(valueNode)
+		case "body":
+			m.Body, err = a.makeBody(valueNode)
+		case "securedBy":
+			m.SecuredBy, err = a.makeSecuredBy(valueNode)
+		case "responses":
+			m.Responses, err = a.makeResponses(valueNode)
+		}
+		if err != nil {
+			return nil, stacktrace.NewWrapped("parse "+node.Value, err, a.Location, stacktrace.WithNodePosition(valueNode))
+		}
+	}
+	return m, nil
+}
+
+func (a *API) makeResponses(value *yaml.Node) (*orderedmap.OrderedMap[string, *Response], error) {
+	if value.Kind != yaml.MappingNode {
+		return nil, stacktrace.New("must be map", a.Location, stacktrace.WithNodePosition(value))
+	}
+	responses := orderedmap.New[string, *Response](len(value.Content) / 2)
+	for i := 0; i != len(value.Content); i += 2 {
+		code := value.Content[i]
+		data := value.Content[i+1]
+		resp := &Response{
+			Code:     code.Value,
+			Location: a.Location,
+			Position: stacktrace.Position{Line: code.Line, Column: code.Column},
+		}
+		if data.Kind == yaml.MappingNode {
+			for j := 0; j != len(data.Content); j += 2 {
+				node := data.Content[j]
+				valueNode := data.Content[j+1]
+				var err error
+				switch node.Value {
+				case "description":
+					err = valueNode.Decode(&resp.Description)
+				case "headers":
+					resp.Headers, err = a.makeParameters(valueNode)
+				case "body":
+					resp.Body, err = a.makeBody(valueNode)
+				}
+				if err != nil {
+					return nil, stacktrace.NewWrapped("parse "+node.Value, err, a.Location, stacktrace.WithNodePosition(valueNode))
+				}
+			}
+		} else if data.Tag != "!!null" {
+			return nil, stacktrace.New("response must be map", a.Location, stacktrace.WithNodePosition(data))
+		}
+		responses.Set(resp.Code, resp)
+	}
+	return responses, nil
+}
+
+// makeBody makes bodies keyed by media type. A body without media types is a type declaration
+// of the default media types.
+func (a *API) makeBody(value *yaml.Node) (*orderedmap.OrderedMap[string, *Body], error) {
+	body := orderedmap.New[string, *Body]()
+	if value.Kind == yaml.MappingNode && len(value.Content) > 0 && isMediaType(value.Content[0].Value) {
+		for i := 0; i != len(value.Content); i += 2 {
+			mediaType := value.Content[i].Value
+			b, err := a.makeBodyOf(mediaType, value.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			body.Set(mediaType, b)
+		}
+		return body, nil
+	}
+	if len(a.MediaType) == 0 {
+		return nil, stacktrace.New("body must declare media type since API has no default media type", a.Location,
+			stacktrace.WithNodePosition(value))
+	}
+	for _, mediaType := range a.MediaType {
+		b, err := a.makeBodyOf(mediaType, value)
+		if err != nil {
+			return nil, err
+		}
+		body.Set(mediaType, b)
+	}
+	return body, nil
+}
+
+func (a *API) makeBodyOf(mediaType string, value *yaml.Node) (*Body, error) {
+	shape, err := a.raml.makeShape(value, mediaType, a.Location)
+	if err != nil {
+		return nil, stacktrace.NewWrapped("make shape", err, a.Location, stacktrace.WithNodePosition(value))
+	}
+	a.raml.PutShapePtr(shape)
+	return &Body{
+		MediaType: mediaType,
+		Shape:     shape,
+		Location:  a.Location,
+		Position:  stacktrace.Position{Line: value.Line, Column: value.Column},
+	}, nil
+}
+
+// makeParameters makes parameters that are declared as properties of objects.
+func (a *API) makeParameters(value *yaml.Node) (*orderedmap.OrderedMap[string, Property], error) {
+	if value.Tag == "!!null" {
+		return nil, nil
+	}
+	if value.Kind != yaml.MappingNode {
+		return nil, stacktrace.New("must be map", a.Location, stacktrace.WithNodePosition(value))
+	}
+	params := orderedmap.New[string, Property](len(value.Content) / 2)
+	for i := 0; i != len(value.Content); i += 2 {
+		nodeName := value.Content[i].Value
+		data := value.Content[i+1]
+		name, hasImplicitOptional := a.raml.chompImplicitOptional(nodeName)
+		property, err := a.raml.makeProperty(nodeName, name, data, a.Location, hasImplicitOptional)
+		if err != nil {
+			return nil, stacktrace.NewWrapped("make parameter", err, a.Location, stacktrace.WithNodePosition(data))
+		}
+		params.Set(property.Name, property)
+		a.raml.PutShapePtr(property.Shape)
+	}
+	return params, nil
+}
+
+// shapes returns pointers to shapes of parameters and bodies of the API.
+func (a *API) shapes() []*Shape {
+	var shapes []*Shape
+	addParams := func(params *orderedmap.OrderedMap[string, Property]) {
+		for pair := params.Oldest(); pair != nil; pair = pair.Next() {
+			shapes = append(shapes, pair.Value.Shape)
+		}
+	}
+	addBody := func(body *orderedmap.OrderedMap[string, *Body]) {
+		for pair := body.Oldest(); pair != nil; pair = pair.Next() {
+			shapes = append(shapes, pair.Value.Shape)
+		}
+	}
+	addParams(a.BaseURIParameters)
+	for pair := a.SecuritySchemes.Oldest(); pair != nil; pair = pair.Next() {
+		if d := pair.Value.DescribedBy; d != nil {
+			addParams(d.Headers)
+			addParams(d.QueryParameters)
+		}
+	}
+	var addResources func(resources *orderedmap.OrderedMap[string, *Resource])
+	addResources = func(resources *orderedmap.OrderedMap[string, *Resource]) {
+		for pair := resources.Oldest(); pair != nil; pair = pair.Next() {
+			res := pair.Value
+			addParams(res.URIParameters)
+			for mp := res.Methods.Oldest(); mp != nil; mp = mp.Next() {
+				m := mp.Value
+				addParams(m.QueryParameters)
+				addParams(m.Headers)
+				addBody(m.Body)
+				for rp := m.Responses.Oldest(); rp != nil; rp = rp.Next() {
+					addParams(rp.Value.Headers)
+					addBody(rp.Value.Body)
+				}
+			}
+			addResources(res.Resources)
+		}
+	}
+	addResources(a.Resources)
+	return shapes
+}
+
+func isMediaType(s string) bool {
+	return strings.Contains(s, "/")
+}
+
+// decodeStrings decodes a string or an array of strings.
+func decodeStrings(value *yaml.Node) ([]string, error) {
+	if value.Kind == yaml.ScalarNode {
+		return []string{value.Value}, nil
+	}
+	var items []string
+	if err := value.Decode(&items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,176 @@
+package raml
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testAPI = `#%RAML 1.0
+title: Pets
+version: v1
+baseUri: https://api.example.com/{version}
+mediaType: application/json
+securitySchemes:
+  oauth:
+    type: OAuth 2.0
+    settings:
+      authorizationUri: https://auth.example.com/authorize
+      accessTokenUri: https://auth.example.com/token
+      authorizationGrants: [authorization_code, client_credentials]
+      scopes: [read, write]
+  key:
+    type: Pass Through
+    describedBy:
+      headers:
+        X-Api-Key: string
+securedBy: [oauth]
+types:
+  Pet:
+    type: object
+    properties:
+      id: integer
+      name: string
+/pets:
+  displayName: Pets
+  get:
+    description: Lists pets.
+    queryParameters:
+      limit?:
+        type: integer
+        maximum: 100
+    responses:
+      200:
+        body:
+          type: array
+          items: Pet
+  post:
+    securedBy: [{oauth: {scopes: [write]}}, key]
+    body: Pet
+    responses:
+      201:
+        description: Created.
+        headers:
+          Location: string
+  /{id}:
+    uriParameters:
+      id: integer
+    get:
+      securedBy: [null]
+      responses:
+        200:
+          body:
+            application/json: Pet
+            application/xml: Pet
+`
+
+func TestParseAPI(t *testing.T) {
+	rml := parseLibrary(t, testAPI)
+	api, ok := rml.EntryPoint().(*API)
+	require.True(t, ok, "entry point must be an API")
+	require.Equal(t, "Pets", api.Title)
+	require.Equal(t, "v1", api.Version)
+	require.Equal(t, []string{"application/json"}, api.MediaType)
+	_, ok = api.Types.Get("Pet")
+	require.True(t, ok)
+
+	oauth, ok := api.SecuritySchemes.Get("oauth")
+	require.True(t, ok)
+	require.Equal(t, "OAuth 2.0", oauth.Type)
+	require.Equal(t, "https://auth.example.com/token", oauth.Settings["accessTokenUri"])
+	key, _ := api.SecuritySchemes.Get("key")
+	_, ok = key.DescribedBy.Headers.Get("X-Api-Key")
+	require.True(t, ok)
+	require.Equal(t, []*SecuredBy{{Name: "oauth", Position: api.SecuredBy[0].Position}}, api.SecuredBy)
+
+	pets, ok := api.Resources.Get("/pets")
+	require.True(t, ok)
+	require.Equal(t, "Pets", pets.DisplayName)
+	list, ok := pets.Methods.Get("get")
+	require.True(t, ok)
+	require.Equal(t, "Lists pets.", list.Description)
+	limit, ok := list.QueryParameters.Get("limit")
+	require.True(t, ok)
+	require.False(t, limit.Required)
+	require.IsType(t, &IntegerShape{}, *limit.Shape)
+	ok200, _ := list.Responses.Get("200")
+	body, ok := ok200.Body.Get("application/json")
+	require.True(t, ok)
+	require.IsType(t, &ArrayShape{}, *body.Shape)
+
+	create, _ := pets.Methods.Get("post")
+	require.Len(t, create.SecuredBy, 2)
+	require.Equal(t, []string{"write"}, create.SecuredBy[0].Scopes)
+	require.Equal(t, "key", create.SecuredBy[1].Name)
+	reqBody, ok := create.Body.Get("application/json")
+	require.True(t, ok)
+	require.IsType(t, &ObjectShape{}, *reqBody.Shape)
+	created, _ := create.Responses.Get("201")
+	require.Equal(t, "Created.", created.Description)
+	_, ok = created.Headers.Get("Location")
+	require.True(t, ok)
+
+	pet, ok := pets.Resources.Get("/{id}")
+	require.True(t, ok)
+	id, _ := pet.URIParameters.Get("id")
+	require.True(t, id.Required)
+	get, _ := pet.Methods.Get("get")
+	require.Equal(t, "", get.SecuredBy[0].Name)
+	ok200, _ = get.Responses.Get("200")
+	require.Equal(t, 2, ok200.Body.Len())
+}
+
+func TestParseAPI_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "missing title", content: "#%RAML 1.0\nversion: v1\n", wantErr: "title is required"},
+		{name: "undeclared security scheme", content: "#%RAML 1.0\ntitle: A\nsecuredBy: [oauth]\n", wantErr: "security scheme is not declared"},
+		{
+			name:    "body without media type",
+			content: "#%RAML 1.0\ntitle: A\n/a:\n  post:\n    body: string\n",
+			wantErr: "body must declare media type",
+		},
+		{
+			name:    "unknown type",
+			content: "#%RAML 1.0\ntitle: A\n/a:\n  get:\n    queryParameters:\n      q: Missing\n",
+			wantErr: `reference "Missing" not found`,
+		},
+		{
+			name:    "invalid facet",
+			content: "#%RAML 1.0\ntitle: A\n/a:\n  get:\n    headers:\n      h:\n        type: string\n        minLength: 3\n        maxLength: 1\n",
+			wantErr: "minLength must be less than or equal to maxLength",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseFromString(tt.content, "api.raml", t.TempDir(), OptWithUnwrap(), OptWithValidate())
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestParseAPI_Uses(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "common.raml"), []byte("#%RAML 1.0 Library\ntypes:\n  Id: string\n"), 0o600))
+	rml, err := ParseFromString(`#%RAML 1.0
+title: A
+uses:
+  common: common.raml
+/items/{id}:
+  uriParameters:
+    id: common.Id
+  get:
+`, "api.raml", dir, OptWithUnwrap(), OptWithValidate())
+	require.NoError(t, err)
+	api := rml.EntryPoint().(*API)
+	res, _ := api.Resources.Get("/items/{id}")
+	id, _ := res.URIParameters.Get("id")
+	require.IsType(t, &StringShape{}, *id.Shape)
+	_, ok := res.Methods.Get("get")
+	require.True(t, ok)
+}
@@ -0,0 +1,593 @@
+package raml
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+
+	"github.com/acronis/go-raml/stacktrace"
+)
+
+// avroPrimitiveTypes are names of Avro primitive types.
+var avroPrimitiveTypes = map[string]struct{}{
+	"null": {}, "boolean": {}, "int": {}, "long": {}, "float": {}, "double": {}, "bytes": {}, "string": {},
+}
+
+var avroNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// AvroSchema represents a complex Avro schema: record, enum, array, map or a primitive with logical type.
+// Primitive schemas and references to named schemas are represented by strings, unions by slices.
+//
+// https://avro.apache.org/docs/1.11.1/specification/
+type AvroSchema struct {
+	Type        string          `json:"type"`
+	Name        string          `json:"name,omitempty"`
+	Namespace   string          `json:"namespace,omitempty"`
+	Doc         string          `json:"doc,omitempty"`
+	Aliases     []string        `json:"aliases,omitempty"`
+	Fields      []*AvroField    `json:"fields,omitempty"`
+	Symbols     []string        `json:"symbols,omitempty"`
+	Items       any             `json:"items,omitempty"`
+	Values      any             `json:"values,omitempty"`
+	LogicalType string          `json:"logicalType,omitempty"`
+	Default     json.RawMessage `json:"default,omitempty"`
+}
+
+// AvroField represents a field of Avro record.
+type AvroField struct {
+	Name    string          `json:"name"`
+	Doc     string          `json:"doc,omitempty"`
+	Type    any             `json:"type"`
+	Default json.RawMessage `json:"default,omitempty"`
+}
+
+type AvroConverterOpt interface {
+	Apply(*AvroConverterOptions)
+}
+
+type optAvroNamespace struct {
+	namespace string
+}
+
+func (o optAvroNamespace) Apply(e *AvroConverterOptions) {
+	e.namespace = o.namespace
+}
+
+// WithAvroNamespace sets the namespace prepended to namespaces derived from library paths.
+func WithAvroNamespace(namespace string) AvroConverterOpt {
+	return optAvroNamespace{namespace: namespace}
+}
+
+type AvroConverterOptions struct {
+	namespace string
+}
+
+// avroDeclaration is a library type declared as a named Avro schema.
+type avroDeclaration struct {
+	namespace string
+	name      string
+}
+
+func (d avroDeclaration) fullName() string {
+	if d.namespace == "" {
+		return d.name
+	}
+	return d.namespace + "." + d.name
+}
+
+// AvroConverter converts unwrapped shapes to Avro schemas.
+// Visit methods return schemas in the context of the current top-level schema,
+// so that named schemas are defined once and referenced by the full name afterwards.
+type AvroConverter struct {
+	opts AvroConverterOptions
+
+	baseDir      string
+	declarations map[string]avroDeclaration
+	// defined holds full names of named schemas defined in the current top-level schema.
+	defined map[string]struct{}
+	// names holds names of anonymous records and enums of the current top-level schema.
+	names     map[string]avroDeclaration
+	namespace string
+	nameHint  string
+	warnings  []*stacktrace.StackTrace
+}
+
+var _ ShapeVisitor[any] = (*AvroConverter)(nil)
+
+func NewAvroConverter(opts ...AvroConverterOpt) *AvroConverter {
+	c := &AvroConverter{}
+	for _, opt := range opts {
+		opt.Apply(&c.opts)
+	}
+	return c
+}
+
+// ConvertLibrary converts types of the library and the libraries it uses into self-contained Avro schemas
+// keyed by the full name. Namespaces are derived from library paths relative to the library directory.
+// Constructs that cannot be represented in Avro are reported as warnings.
+func (c *AvroConverter) ConvertLibrary(lib *Library) (*orderedmap.OrderedMap[string, any], []*stacktrace.StackTrace) {
+	c.baseDir = filepath.Dir(lib.Location)
+	c.declarations = make(map[string]avroDeclaration)
+	c.warnings = nil
+
+	types := orderedmap.New[string, Shape]()
+	c.collectLibrary(lib, types, make(map[string]struct{}))
+
+	result := orderedmap.New[string, any](types.Len())
+	for pair := types.Oldest(); pair != nil; pair = pair.Next() {
+		s := pair.Value
+		c.defined = make(map[string]struct{})
+		c.names = make(map[string]avroDeclaration)
+		c.namespace = c.libraryNamespace(s.Base().Location)
+		c.nameHint = ""
+		result.Set(pair.Key, c.Visit(s))
+	}
+	return result, c.warnings
+}
+
+func (c *AvroConverter) collectLibrary(lib *Library, types *orderedmap.OrderedMap[string, Shape], visited map[string]struct{}) {
+	if _, ok := visited[lib.Location]; ok {
+		return
+	}
+	visited[lib.Location] = struct{}{}
+	namespace := c.libraryNamespace(lib.Location)
+	for pair := lib.Types.Oldest(); pair != nil; pair = pair.Next() {
+		s := *pair.Value
+		if !s.Base().IsUnwrapped() {
+			us, err := lib.raml.UnwrapShape(pair.Value, make([]Shape, 0))
+			if err != nil {
+				c.warn(s.Base(), "type cannot be unwrapped and is skipped", stacktrace.WithInfo("error", err))
+				continue
+			}
+			s = us
+		}
+		d := avroDeclaration{namespace: namespace, name: c.avroName(s.Base(), pair.Key)}
+		types.Set(d.fullName(), s)
+		if _, ok := c.declarations[s.Base().Id]; !ok {
+			c.declarations[s.Base().Id] = d
+		}
+	}
+	for pair := lib.Uses.Oldest(); pair != nil; pair = pair.Next() {
+		if pair.Value.Link != nil {
+			c.collectLibrary(pair.Value.Link, types, visited)
+		}
+	}
+}
+
+// libraryNamespace derives namespace from the library path, e.g. "sub/common.raml" becomes "sub.common".
+func (c *AvroConverter) libraryNamespace(location string) string {
+	p, err := filepath.Rel(c.baseDir, location)
+	if err != nil || strings.HasPrefix(p, "..") {
+		p = filepath.Base(location)
+	}
+	p = strings.TrimSuffix(filepath.ToSlash(p), filepath.Ext(p))
+	var parts []string
+	if c.opts.namespace != "" {
+		parts = append(parts, c.opts.namespace)
+	}
+	for _, part := range strings.Split(p, "/") {
+		parts = append(parts, avroIdentifier(part))
+	}
+	return strings.Join(parts, ".")
+}
+
+// Visit returns the Avro schema of the shape. Named schemas that are already defined are referenced by the full name.
+func (c *AvroConverter) Visit(s Shape) any {
+	id := s.Base().Id
+	d, declared := c.declarations[id]
+	if !declared {
+		d, declared = c.names[id]
+	}
+	if declared {
+		if _, ok := c.defined[d.fullName()]; ok {
+			return d.fullName()
+		}
+	}
+	return c.visit(s)
+}
+
+func (c *AvroConverter) visit(s Shape) any {
+	switch s := s.(type) {
+	case *ObjectShape:
+		return c.VisitObjectShape(s)
+	case *ArrayShape:
+		return c.VisitArrayShape(s)
+	case *StringShape:
+		return c.VisitStringShape(s)
+	case *NumberShape:
+		return c.VisitNumberShape(s)
+	case *IntegerShape:
+		return c.VisitIntegerShape(s)
+	case *BooleanShape:
+		return c.VisitBooleanShape(s)
+	case *FileShape:
+		return c.VisitFileShape(s)
+	case *UnionShape:
+		return c.VisitUnionShape(s)
+	case *DateTimeShape:
+		return c.VisitDateTimeShape(s)
+	case *DateTimeOnlyShape:
+		return c.VisitDateTimeOnlyShape(s)
+	case *DateOnlyShape:
+		return c.VisitDateOnlyShape(s)
+	case *TimeOnlyShape:
+		return c.VisitTimeOnlyShape(s)
+	case *RecursiveShape:
+		return c.VisitRecursiveShape(s)
+	case *JSONShape:
+		return c.VisitJSONShape(s)
+	case *XMLShape:
+		return c.VisitXMLShape(s)
+	case *AnyShape:
+		return c.VisitAnyShape(s)
+	case *NilShape:
+		return c.VisitNilShape(s)
+	default:
+		c.warn(s.Base(), "unsupported type is replaced with string")
+		return "string"
+	}
+}
+
+// define returns the declaration of the named schema and marks it as defined.
+// Anonymous schemas are named after the hint in the namespace of the enclosing schema.
+func (c *AvroConverter) define(s Shape) avroDeclaration {
+	d, ok := c.declarations[s.Base().Id]
+	if !ok {
+		name := c.nameHint
+		if name == "" {
+			name = "Type"
+		}
+		d = avroDeclaration{namespace: c.namespace, name: name}
+		for n := 1; ; n++ {
+			if _, ok := c.defined[d.fullName()]; !ok {
+				break
+			}
+			d.name = fmt.Sprintf("%s%d", name, n)
+		}
+		c.names[s.Base().Id] = d
+	}
+	c.defined[d.fullName()] = struct{}{}
+	return d
+}
+
+func (c *AvroConverter) VisitObjectShape(s *ObjectShape) any {
+	hasProperties := s.Properties != nil && s.Properties.Len() > 0
+	if s.PatternProperties != nil && s.PatternProperties.Len() > 0 {
+		if hasProperties {
+			c.warn(s.Base(), "pattern properties of record are dropped")
+		} else {
+			var values []any
+			hint := c.nameHint
+			for pair := s.PatternProperties.Oldest(); pair != nil; pair = pair.Next() {
+				c.nameHint = hint + "Value"
+				values = append(values, c.Visit(*pair.Value.Shape))
+			}
+			return &AvroSchema{Type: "map", Values: c.union(s.Base(), values)}
+		}
+	}
+
+	d := c.define(s)
+	namespace := c.namespace
+	c.namespace = d.namespace
+	defer func() { c.namespace = namespace }()
+
+	schema := &AvroSchema{Type: "record", Name: d.name, Namespace: d.namespace, Doc: avroDoc(s.Base()), Fields: []*AvroField{}}
+	if !hasProperties {
+		return schema
+	}
+	for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+		prop := pair.Value
+		ps := *prop.Shape
+		c.nameHint = d.name + goIdentifier(prop.Name)
+		field := &AvroField{Name: c.avroName(ps.Base(), prop.Name), Type: c.Visit(ps)}
+		if _, ok := c.declarations[ps.Base().Id]; !ok {
+			field.Doc = avroDoc(ps.Base())
+		}
+		if def := ps.Base().Default; def != nil {
+			value, err := avroDefault(ps, def.Value)
+			if err != nil {
+				c.warn(ps.Base(), "default cannot be converted and is dropped", stacktrace.WithInfo("error", err))
+			} else {
+				if !prop.Required {
+					// Default must match the first member of union.
+					field.Type = avroNullable(field.Type, def.Value == nil)
+				}
+				field.Default = value
+			}
+		} else if !prop.Required {
+			field.Type = avroNullable(field.Type, true)
+			field.Default = json.RawMessage("null")
+		}
+		schema.Fields = append(schema.Fields, field)
+	}
+	return schema
+}
+
+// avroNullable returns union with null. Null must be the first member when it is the default.
+func avroNullable(t any, nullFirst bool) any {
+	members := []any{t}
+	if branches, ok := t.([]any); ok {
+		members = make([]any, 0, len(branches))
+		for _, b := range branches {
+			if b != "null" {
+				members = append(members, b)
+			}
+		}
+	}
+	if nullFirst {
+		return append([]any{"null"}, members...)
+	}
+	return append(members, "null")
+}
+
+func (c *AvroConverter) VisitArrayShape(s *ArrayShape) any {
+	if s.Items == nil {
+		c.warn(s.Base(), "array without items is converted to array of strings")
+		return &AvroSchema{Type: "array", Items: "string"}
+	}
+	c.nameHint += "Item"
+	return &AvroSchema{Type: "array", Items: c.Visit(*s.Items)}
+}
+
+func (c *AvroConverter) VisitStringShape(s *StringShape) any {
+	if s.Enum == nil {
+		return "string"
+	}
+	d := c.define(s)
+	schema := &AvroSchema{Type: "enum", Name: d.name, Namespace: d.namespace, Doc: avroDoc(s.Base())}
+	schema.Symbols = avroEnumSymbols(s.Enum)
+	for i, e := range s.Enum {
+		if value := fmt.Sprint(e.Value); value != schema.Symbols[i] {
+			c.warn(s.Base(), "enum symbol is not a valid Avro name and is renamed",
+				stacktrace.WithInfo("symbol", value), stacktrace.WithInfo("renamed", schema.Symbols[i]))
+		}
+	}
+	return schema
+}
+
+// avroEnumSymbols returns Avro symbols of the enum values in order. Values that are valid Avro names are kept,
+// other values are renamed to identifiers that get numeric suffixes if they collide with other symbols.
+func avroEnumSymbols(enum Nodes) []string {
+	symbols := make([]string, len(enum))
+	taken := make(map[string]struct{}, len(enum))
+	for i, e := range enum {
+		if value := fmt.Sprint(e.Value); avroNameRegexp.MatchString(value) {
+			symbols[i] = value
+			taken[value] = struct{}{}
+		}
+	}
+	for i, e := range enum {
+		if symbols[i] != "" {
+			continue
+		}
+		id := avroIdentifier(fmt.Sprint(e.Value))
+		symbol := id
+		for n := 1; ; n++ {
+			if _, ok := taken[symbol]; !ok {
+				break
+			}
+			symbol = id + "_" + strconv.Itoa(n)
+		}
+		symbols[i] = symbol
+		taken[symbol] = struct{}{}
+	}
+	return symbols
+}
+
+func (c *AvroConverter) VisitNumberShape(s *NumberShape) any {
+	if s.Enum != nil {
+		c.warn(s.Base(), "number enum is replaced with number type")
+	}
+	if s.Format != nil && *s.Format == "float" {
+		return "float"
+	}
+	return "double"
+}
+
+func (c *AvroConverter) VisitIntegerShape(s *IntegerShape) any {
+	if s.Enum != nil {
+		c.warn(s.Base(), "integer enum is replaced with integer type")
+	}
+	if s.Format != nil {
+		switch *s.Format {
+		case "int8", "int16", "int", "int32":
+			return "int"
+		}
+	}
+	return "long"
+}
+
+func (c *AvroConverter) VisitBooleanShape(s *BooleanShape) any {
+	return "boolean"
+}
+
+func (c *AvroConverter) VisitFileShape(s *FileShape) any {
+	return "bytes"
+}
+
+func (c *AvroConverter) VisitUnionShape(s *UnionShape) any {
+	hint := c.nameHint
+	members := make([]any, 0, len(s.AnyOf))
+	for i, item := range s.AnyOf {
+		c.nameHint = fmt.Sprintf("%sMember%d", hint, i)
+		members = append(members, c.Visit(*item))
+	}
+	return c.union(s.Base(), members)
+}
+
+// union flattens nested unions and removes duplicates since Avro does not allow them.
+// Unnamed members are duplicates if they have the same type, regardless of their logical types.
+func (c *AvroConverter) union(base *BaseShape, members []any) any {
+	result := make([]any, 0, len(members))
+	seen := make(map[string]any)
+	var add func(m any)
+	add = func(m any) {
+		var key string
+		named := false
+		switch m := m.(type) {
+		case []any:
+			for _, item := range m {
+				add(item)
+			}
+			return
+		case string:
+			key = m
+			_, primitive := avroPrimitiveTypes[m]
+			named = !primitive
+		case *AvroSchema:
+			switch m.Type {
+			case "record", "enum":
+				key = m.Namespace + "." + m.Name
+				named = true
+			default:
+				key = m.Type
+			}
+		}
+		if first, ok := seen[key]; ok {
+			// Named types are either defined or referenced by name, both of which are the same schema.
+			if !named && !reflect.DeepEqual(first, m) {
+				c.warn(base, "union cannot contain several "+key+" schemas, only the first one is preserved")
+			}
+			return
+		}
+		seen[key] = m
+		result = append(result, m)
+	}
+	add(members)
+	if len(result) == 1 {
+		return result[0]
+	}
+	return result
+}
+
+func (c *AvroConverter) VisitDateTimeShape(s *DateTimeShape) any {
+	return &AvroSchema{Type: "long", LogicalType: "timestamp-millis"}
+}
+
+func (c *AvroConverter) VisitDateTimeOnlyShape(s *DateTimeOnlyShape) any {
+	return &AvroSchema{Type: "long", LogicalType: "local-timestamp-millis"}
+}
+
+func (c *AvroConverter) VisitDateOnlyShape(s *DateOnlyShape) any {
+	return &AvroSchema{Type: "int", LogicalType: "date"}
+}
+
+func (c *AvroConverter) VisitTimeOnlyShape(s *TimeOnlyShape) any {
+	return &AvroSchema{Type: "int", LogicalType: "time-millis"}
+}
+
+func (c *AvroConverter) VisitRecursiveShape(s *RecursiveShape) any {
+	head := (*s.Head).Base().Id
+	if d, ok := c.declarations[head]; ok {
+		return d.fullName()
+	}
+	if d, ok := c.names[head]; ok {
+		return d.fullName()
+	}
+	c.warn(s.Base(), "recursion to unnamed type is replaced with string")
+	return "string"
+}
+
+func (c *AvroConverter) VisitJSONShape(s *JSONShape) any {
+	c.warn(s.Base(), "JSON schema is replaced with string")
+	return "string"
+}
+
+func (c *AvroConverter) VisitXMLShape(s *XMLShape) any {
+	return "string"
+}
+
+func (c *AvroConverter) VisitAnyShape(s *AnyShape) any {
+	c.warn(s.Base(), "any type is replaced with string")
+	return "string"
+}
+
+func (c *AvroConverter) VisitNilShape(s *NilShape) any {
+	return "null"
+}
+
+// avroDefault converts the default value to the Avro representation of the shape.
+func avroDefault(s Shape, value any) (json.RawMessage, error) {
+	if str, ok := value.(string); ok {
+		switch s := s.(type) {
+		case *DateOnlyShape:
+			t, err := parseDateValue(s, str)
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(t.Unix() / (24 * 60 * 60))
+		case *TimeOnlyShape:
+			t, err := parseDateValue(s, str)
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal((time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+				time.Duration(t.Second())*time.Second + time.Duration(t.Nanosecond())).Milliseconds())
+		case *DateTimeOnlyShape, *DateTimeShape:
+			t, err := parseDateValue(s, str)
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(t.UnixMilli())
+		case *StringShape:
+			if s.Enum != nil {
+				symbols := avroEnumSymbols(s.Enum)
+				for i, e := range s.Enum {
+					if e.Value == str {
+						return json.Marshal(symbols[i])
+					}
+				}
+			}
+		}
+	}
+	return json.Marshal(value)
+}
+
+func (c *AvroConverter) avroName(base *BaseShape, name string) string {
+	if avroNameRegexp.MatchString(name) {
+		return name
+	}
+	id := avroIdentifier(name)
+	c.warn(base, "name is not a valid Avro name and is renamed", stacktrace.WithInfo("name", name), stacktrace.WithInfo("avro", id))
+	return id
+}
+
+func avroIdentifier(name string) string {
+	id := strings.Map(func(r rune) rune {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, name)
+	if !avroNameRegexp.MatchString(id) {
+		id = "_" + id
+	}
+	return id
+}
+
+func avroDoc(base *BaseShape) string {
+	if base.Description != nil {
+		return *base.Description
+	}
+	if base.DisplayName != nil {
+		return *base.DisplayName
+	}
+	return ""
+}
+
+func (c *AvroConverter) warn(base *BaseShape, message string, opts ...stacktrace.Option) {
+	opts = append(opts,
+		stacktrace.WithPosition(&base.Position),
+		stacktrace.WithSeverity(stacktrace.SeverityWarning),
+		stacktrace.WithType(stacktrace.TypeConverting),
+		stacktrace.WithInfo("shape", base.Name),
+	)
+	c.warnings = append(c.warnings, stacktrace.New(message, base.Location, opts...))
+}
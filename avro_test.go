@@ -0,0 +1,155 @@
+package raml
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAvroConverter_ConvertLibrary(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0 Library
+types:
+  Color:
+    enum: [red, green]
+  Point:
+    type: object
+    properties:
+      x: integer
+      y:
+        type: number
+        format: float
+      color?: Color
+      at: datetime
+      tags:
+        type: string[]
+        default: [a]
+`)
+	schemas, warnings := NewAvroConverter(WithAvroNamespace("com.example")).ConvertLibrary(rml.EntryPoint().(*Library))
+	require.Empty(t, warnings)
+
+	tests := []struct {
+		name     string
+		typeName string
+		want     string
+	}{
+		{
+			name:     "enum",
+			typeName: "Color",
+			want:     `{"type":"enum","name":"Color","namespace":"com.example.library","symbols":["red","green"]}`,
+		},
+		{
+			name:     "record",
+			typeName: "Point",
+			want: `{"type":"record","name":"Point","namespace":"com.example.library","fields":[
+				{"name":"x","type":"long"},
+				{"name":"y","type":"float"},
+				{"name":"color","type":["null",{"type":"enum","name":"Color","namespace":"com.example.library","symbols":["red","green"]}],"default":null},
+				{"name":"at","type":{"type":"long","logicalType":"timestamp-millis"}},
+				{"name":"tags","type":{"type":"array","items":"string"},"default":["a"]}
+			]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, ok := schemas.Get("com.example.library." + tt.typeName)
+			require.True(t, ok)
+			b, err := json.Marshal(schema)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(b))
+		})
+	}
+}
+
+func Test_avroDefault(t *testing.T) {
+	rfc2616 := "rfc2616"
+	tests := []struct {
+		name    string
+		shape   Shape
+		value   any
+		want    string
+		wantErr bool
+	}{
+		{name: "date-only", shape: &DateOnlyShape{}, value: "1970-01-03", want: "2"},
+		{name: "invalid date-only", shape: &DateOnlyShape{}, value: "1970-02-30", wantErr: true},
+		{name: "time-only with fraction", shape: &TimeOnlyShape{}, value: "00:00:01.5", want: "1500"},
+		{name: "datetime-only", shape: &DateTimeOnlyShape{}, value: "1970-01-01T00:00:01", want: "1000"},
+		{name: "datetime", shape: &DateTimeShape{}, value: "1970-01-01T01:00:00+01:00", want: "0"},
+		{name: "datetime without offset", shape: &DateTimeShape{}, value: "1970-01-01T00:00:00", wantErr: true},
+		{
+			name:  "rfc2616",
+			shape: &DateTimeShape{FormatFacets: FormatFacets{Format: &rfc2616}},
+			value: "Thu, 01 Jan 1970 00:00:01 GMT",
+			want:  "1000",
+		},
+		{
+			name:    "rfc2616 wrong day name",
+			shape:   &DateTimeShape{FormatFacets: FormatFacets{Format: &rfc2616}},
+			value:   "Fri, 01 Jan 1970 00:00:01 GMT",
+			wantErr: true,
+		},
+		{name: "other value", shape: &StringShape{}, value: "x", want: `"x"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := avroDefault(tt.shape, tt.value)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestAvroConverter_Collisions(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0 Library
+types:
+  Code:
+    enum: [a-b, a_b, a b, ok]
+  Holder:
+    type: object
+    properties:
+      code:
+        type: Code
+        default: a b
+  Stamp: integer | datetime
+  Moment: date-only | time-only | integer
+  Tagged: Code | Code
+`)
+	schemas, warnings := NewAvroConverter(WithAvroNamespace("com.example")).ConvertLibrary(rml.EntryPoint().(*Library))
+	tests := []struct {
+		typeName string
+		want     string
+	}{
+		{
+			typeName: "Code",
+			want:     `{"type":"enum","name":"Code","namespace":"com.example.library","symbols":["a_b_1","a_b","a_b_2","ok"]}`,
+		},
+		{
+			typeName: "Holder",
+			want: `{"type":"record","name":"Holder","namespace":"com.example.library","fields":[
+				{"name":"code","type":{"type":"enum","name":"HolderCode","namespace":"com.example.library","symbols":["a_b_1","a_b","a_b_2","ok"]},"default":"a_b_2"}
+			]}`,
+		},
+		{typeName: "Stamp", want: `"long"`},
+		{typeName: "Moment", want: `[{"type":"int","logicalType":"date"},"long"]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typeName, func(t *testing.T) {
+			schema, ok := schemas.Get("com.example.library." + tt.typeName)
+			require.True(t, ok)
+			b, err := json.Marshal(schema)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(b))
+		})
+	}
+	var messages []string
+	for _, w := range warnings {
+		messages = append(messages, w.Message)
+	}
+	require.Contains(t, messages, "union cannot contain several long schemas, only the first one is preserved")
+	require.Contains(t, messages, "union cannot contain several int schemas, only the first one is preserved")
+	require.Contains(t, messages, "enum symbol is not a valid Avro name and is renamed")
+}
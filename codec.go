@@ -0,0 +1,443 @@
+package raml
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Layouts of canonical encoding of date types. Fractional seconds are written only if present.
+const (
+	codecDateTimeOnly = "2006-01-02T15:04:05.999999999"
+	codecTimeOnly     = "15:04:05.999999999"
+)
+
+// DecodeJSON decodes a JSON document into a value tree typed by the shape:
+//   - integers as int64 if the shape has an integer format and *big.Int otherwise;
+//   - numbers as json.Number;
+//   - date types as time.Time;
+//   - files as []byte decoded from base64;
+//   - objects as map[string]interface{}, arrays as []interface{}, other scalars as in encoding/json.
+//
+// Union values are decoded with the member selected by discriminator or validation,
+// or else with the first member the value can be decoded with.
+// Values of any type and undeclared properties keep JSON numbers as json.Number.
+// DecodeJSON checks types only, facets are validated by Validate.
+func DecodeJSON(s Shape, data []byte) (interface{}, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, fmt.Errorf("unmarshal json: %w", err)
+	}
+	if d.More() {
+		return nil, fmt.Errorf("unexpected data after top-level value at offset %d", d.InputOffset())
+	}
+	return decodeValue(s, v, "$")
+}
+
+func decodeValue(s Shape, v interface{}, path string) (interface{}, error) {
+	switch s := s.(type) {
+	case *IntegerShape:
+		n, ok := v.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("%s: invalid type, got %T, expected number", path, v)
+		}
+		i, err := jsonInteger(n)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		lo, hi := integerFormatBounds(s.Format)
+		if lo == nil {
+			return i, nil
+		}
+		if i.Cmp(lo) < 0 || i.Cmp(hi) > 0 {
+			return nil, fmt.Errorf("%s: integer %s is out of range of format %s", path, n, *s.Format)
+		}
+		return i.Int64(), nil
+	case *NumberShape:
+		if _, ok := v.(json.Number); !ok {
+			return nil, fmt.Errorf("%s: invalid type, got %T, expected number", path, v)
+		}
+		return v, nil
+	case *StringShape:
+		if _, ok := v.(string); !ok {
+			return nil, fmt.Errorf("%s: invalid type, got %T, expected string", path, v)
+		}
+		return v, nil
+	case *BooleanShape:
+		if _, ok := v.(bool); !ok {
+			return nil, fmt.Errorf("%s: invalid type, got %T, expected boolean", path, v)
+		}
+		return v, nil
+	case *NilShape:
+		if v != nil {
+			return nil, fmt.Errorf("%s: invalid type, got %T, expected null", path, v)
+		}
+		return nil, nil
+	case *FileShape:
+		str, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: invalid type, got %T, expected base64 string", path, v)
+		}
+		b, err := base64.StdEncoding.DecodeString(str)
+		if err != nil {
+			return nil, fmt.Errorf("%s: decode base64: %w", path, err)
+		}
+		return b, nil
+	case *DateTimeShape, *DateTimeOnlyShape, *DateOnlyShape, *TimeOnlyShape:
+		str, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: invalid type, got %T, expected string", path, v)
+		}
+		t, err := parseDateValue(s, str)
+		if err != nil {
+			return nil, fmt.Errorf("%s: value must match format %s: %w", path, dateLayout(s), err)
+		}
+		return t, nil
+	case *ArrayShape:
+		items, ok := v.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: invalid type, got %T, expected array", path, v)
+		}
+		res := make([]interface{}, len(items))
+		for i, item := range items {
+			if s.Items == nil {
+				res[i] = item
+				continue
+			}
+			decoded, err := decodeValue(*s.Items, item, path+"["+strconv.Itoa(i)+"]")
+			if err != nil {
+				return nil, err
+			}
+			res[i] = decoded
+		}
+		return res, nil
+	case *ObjectShape:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: invalid type, got %T, expected object", path, v)
+		}
+		res := make(map[string]interface{}, len(m))
+		for k, item := range m {
+			ps := propertyShape(s, k)
+			if ps == nil {
+				res[k] = item
+				continue
+			}
+			decoded, err := decodeValue(ps, item, path+"."+k)
+			if err != nil {
+				return nil, err
+			}
+			res[k] = decoded
+		}
+		return res, nil
+	case *UnionShape:
+		if member := unionMemberOf(s, jsonGenericValue(v)); member != nil {
+			if decoded, err := decodeValue(member, v, path); err == nil {
+				return decoded, nil
+			}
+		}
+		for _, member := range s.AnyOf {
+			if decoded, err := decodeValue(*member, v, path); err == nil {
+				return decoded, nil
+			}
+		}
+		return nil, fmt.Errorf("%s: value does not match any type", path)
+	case *RecursiveShape:
+		return decodeValue(*s.Head, v, path)
+	}
+	return v, nil
+}
+
+// EncodeJSON encodes a value tree typed by the shape to canonical JSON:
+// declared properties are written in the order of declaration followed by other properties in lexical order,
+// date types are written in the layouts of their formats with normalized offsets and fractional seconds
+// written only if present, files are written in base64.
+// Values produced by DecodeJSON and values of types that Validate accepts are supported,
+// date types also accept strings that are reformatted.
+func EncodeJSON(s Shape, v interface{}) ([]byte, error) {
+	var b bytes.Buffer
+	if err := encodeValue(&b, s, v, "$"); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func encodeValue(b *bytes.Buffer, s Shape, v interface{}, path string) error {
+	switch s := s.(type) {
+	case *IntegerShape:
+		i, err := integerValue(v)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		b.WriteString(i.String())
+		return nil
+	case *NumberShape:
+		switch n := v.(type) {
+		case json.Number:
+			if _, err := strconv.ParseFloat(string(n), 64); err != nil {
+				return fmt.Errorf("%s: invalid number %q", path, n)
+			}
+			b.WriteString(string(n))
+			return nil
+		case float64, float32:
+			return encodeAny(b, n, path)
+		}
+		i, err := integerValue(v)
+		if err != nil {
+			return fmt.Errorf("%s: invalid type, got %T, expected number", path, v)
+		}
+		b.WriteString(i.String())
+		return nil
+	case *StringShape:
+		if _, ok := v.(string); !ok {
+			return fmt.Errorf("%s: invalid type, got %T, expected string", path, v)
+		}
+	case *BooleanShape:
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s: invalid type, got %T, expected bool", path, v)
+		}
+	case *NilShape:
+		if v != nil {
+			return fmt.Errorf("%s: invalid type, got %T, expected nil", path, v)
+		}
+	case *FileShape:
+		switch f := v.(type) {
+		case []byte:
+			return encodeAny(b, base64.StdEncoding.EncodeToString(f), path)
+		case string:
+			return encodeAny(b, f, path)
+		}
+		return fmt.Errorf("%s: invalid type, got %T, expected []byte", path, v)
+	case *DateTimeShape, *DateTimeOnlyShape, *DateOnlyShape, *TimeOnlyShape:
+		t, ok := v.(time.Time)
+		if !ok {
+			str, isString := v.(string)
+			if !isString {
+				return fmt.Errorf("%s: invalid type, got %T, expected time.Time", path, v)
+			}
+			var err error
+			if t, err = parseDateValue(s, str); err != nil {
+				return fmt.Errorf("%s: value must match format %s: %w", path, dateLayout(s), err)
+			}
+		}
+		return encodeAny(b, formatDate(s, t), path)
+	case *ArrayShape:
+		items, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: invalid type, got %T, expected []interface{}", path, v)
+		}
+		b.WriteByte('[')
+		for i, item := range items {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			ctxPath := path + "[" + strconv.Itoa(i) + "]"
+			var err error
+			if s.Items == nil {
+				err = encodeAny(b, item, ctxPath)
+			} else {
+				err = encodeValue(b, *s.Items, item, ctxPath)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		b.WriteByte(']')
+		return nil
+	case *ObjectShape:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: invalid type, got %T, expected map[string]interface{}", path, v)
+		}
+		return encodeObject(b, s, m, path)
+	case *UnionShape:
+		for _, member := range s.AnyOf {
+			var mb bytes.Buffer
+			if err := encodeValue(&mb, *member, v, path); err == nil {
+				b.Write(mb.Bytes())
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: value does not match any type", path)
+	case *RecursiveShape:
+		return encodeValue(b, *s.Head, v, path)
+	}
+	return encodeAny(b, v, path)
+}
+
+func encodeObject(b *bytes.Buffer, s *ObjectShape, m map[string]interface{}, path string) error {
+	keys := make([]string, 0, len(m))
+	if s.Properties != nil {
+		for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+			if _, ok := m[pair.Key]; ok {
+				keys = append(keys, pair.Key)
+			}
+		}
+	}
+	declared := len(keys)
+	for k := range m {
+		if s.Properties != nil {
+			if _, ok := s.Properties.Get(k); ok {
+				continue
+			}
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys[declared:])
+
+	b.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		if err := encodeAny(b, k, path); err != nil {
+			return err
+		}
+		b.WriteByte(':')
+		ctxPath := path + "." + k
+		var err error
+		if ps := propertyShape(s, k); ps != nil {
+			err = encodeValue(b, ps, m[k], ctxPath)
+		} else {
+			err = encodeAny(b, m[k], ctxPath)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	b.WriteByte('}')
+	return nil
+}
+
+// encodeAny encodes the value with encoding/json without escaping of HTML characters.
+func encodeAny(b *bytes.Buffer, v interface{}, path string) error {
+	var eb bytes.Buffer
+	e := json.NewEncoder(&eb)
+	e.SetEscapeHTML(false)
+	if err := e.Encode(v); err != nil {
+		return fmt.Errorf("%s: marshal json: %w", path, err)
+	}
+	b.Write(bytes.TrimSuffix(eb.Bytes(), []byte("\n")))
+	return nil
+}
+
+// propertyShape returns the shape of the property or the first matching pattern property.
+func propertyShape(s *ObjectShape, name string) Shape {
+	if s.Properties != nil {
+		if p, ok := s.Properties.Get(name); ok {
+			return *p.Shape
+		}
+	}
+	if s.PatternProperties != nil {
+		for pair := s.PatternProperties.Oldest(); pair != nil; pair = pair.Next() {
+			if pair.Value.Pattern.MatchString(name) {
+				return *pair.Value.Shape
+			}
+		}
+	}
+	return nil
+}
+
+// dateLayout returns the layout of the date type shape.
+func dateLayout(s Shape) string {
+	switch s := s.(type) {
+	case *DateTimeShape:
+		if s.Format != nil && *s.Format == "rfc2616" {
+			return RFC2616
+		}
+		return time.RFC3339
+	case *DateTimeOnlyShape:
+		return DateTime
+	case *DateOnlyShape:
+		return time.DateOnly
+	}
+	return time.TimeOnly
+}
+
+// formatDate formats the time in the canonical layout of the date type shape.
+func formatDate(s Shape, t time.Time) string {
+	switch s := s.(type) {
+	case *DateTimeShape:
+		if s.Format != nil && *s.Format == "rfc2616" {
+			return t.UTC().Format(RFC2616)
+		}
+		return t.Format(time.RFC3339Nano)
+	case *DateTimeOnlyShape:
+		return t.Format(codecDateTimeOnly)
+	case *DateOnlyShape:
+		return t.Format(time.DateOnly)
+	}
+	return t.Format(codecTimeOnly)
+}
+
+// jsonInteger parses an integral JSON number, including numbers written with fraction or exponent.
+func jsonInteger(n json.Number) (*big.Int, error) {
+	if i, ok := new(big.Int).SetString(string(n), 10); ok {
+		return i, nil
+	}
+	if err := checkNumberExponent(string(n)); err != nil {
+		return nil, err
+	}
+	r, ok := new(big.Rat).SetString(string(n))
+	if !ok {
+		return nil, fmt.Errorf("invalid number %q", n)
+	}
+	if !r.IsInt() {
+		return nil, fmt.Errorf("number %s is not an integer", n)
+	}
+	return new(big.Int).Set(r.Num()), nil
+}
+
+// integerValue converts integer values of supported types to big.Int.
+func integerValue(v interface{}) (*big.Int, error) {
+	switch n := v.(type) {
+	case *big.Int:
+		return n, nil
+	case int64:
+		return big.NewInt(n), nil
+	case int:
+		return big.NewInt(int64(n)), nil
+	case uint:
+		return new(big.Int).SetUint64(uint64(n)), nil
+	case json.Number:
+		return jsonInteger(n)
+	case float64:
+		f := big.NewFloat(n)
+		i, acc := f.Int(nil)
+		if acc != big.Exact {
+			return nil, fmt.Errorf("number %v is not an integer", n)
+		}
+		return i, nil
+	}
+	return nil, fmt.Errorf("invalid type, got %T, expected integer", v)
+}
+
+// jsonGenericValue converts json.Number values to the types that Validate accepts.
+func jsonGenericValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if n, err := jsonNumberValue(v); err == nil {
+			return n
+		}
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			m[k] = jsonGenericValue(item)
+		}
+		return m
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = jsonGenericValue(item)
+		}
+		return items
+	}
+	return v
+}
@@ -0,0 +1,172 @@
+package raml
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeJSON_EncodeJSON(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0 Library
+types:
+  Event:
+    type: object
+    properties:
+      id: integer
+      seq:
+        type: integer
+        format: int32
+      score?: number
+      at: datetime
+      day?: date-only
+      local?: datetime-only
+      clock?: time-only
+      modified?:
+        type: datetime
+        format: rfc2616
+      blob?: file
+      tags?: string[]
+      value?: integer | string
+`)
+	s := libraryType(t, rml, "Event")
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "canonical order and big integer",
+			input: `{"extra": 1.50, "at": "2024-01-02T03:04:05+00:00", "seq": 7, "id": 123456789012345678901234567890}`,
+			want:  `{"id":123456789012345678901234567890,"seq":7,"at":"2024-01-02T03:04:05Z","extra":1.50}`,
+		},
+		{
+			name:  "integers written with exponent and fraction",
+			input: `{"id": 1.5e3, "seq": 20.0, "at": "2024-01-02T03:04:05Z"}`,
+			want:  `{"id":1500,"seq":20,"at":"2024-01-02T03:04:05Z"}`,
+		},
+		{
+			name:  "large integer with fraction is exact",
+			input: `{"id": 123456789012345678901234.0, "seq": 1, "at": "2024-01-02T03:04:05Z"}`,
+			want:  `{"id":123456789012345678901234,"seq":1,"at":"2024-01-02T03:04:05Z"}`,
+		},
+		{
+			name:  "number keeps representation",
+			input: `{"id": 1, "seq": 1, "score": 1.10, "at": "2024-01-02T03:04:05Z"}`,
+			want:  `{"id":1,"seq":1,"score":1.10,"at":"2024-01-02T03:04:05Z"}`,
+		},
+		{
+			name:  "date types",
+			input: `{"id": 1, "seq": 1, "at": "2024-01-02T03:04:05.500+02:00", "day": "2024-01-02", "local": "2024-01-02T03:04:05.000", "clock": "03:04:05", "modified": "Tue, 02 Jan 2024 03:04:05 GMT"}`,
+			want:  `{"id":1,"seq":1,"at":"2024-01-02T03:04:05.5+02:00","day":"2024-01-02","local":"2024-01-02T03:04:05","clock":"03:04:05","modified":"Tue, 02 Jan 2024 03:04:05 GMT"}`,
+		},
+		{
+			name:  "file and array",
+			input: `{"id": 1, "seq": 1, "at": "2024-01-02T03:04:05Z", "blob": "aGk=", "tags": ["<a>"]}`,
+			want:  `{"id":1,"seq":1,"at":"2024-01-02T03:04:05Z","blob":"aGk=","tags":["<a>"]}`,
+		},
+		{
+			name:  "union members",
+			input: `{"id": 1, "seq": 1, "at": "2024-01-02T03:04:05Z", "value": "x"}`,
+			want:  `{"id":1,"seq":1,"at":"2024-01-02T03:04:05Z","value":"x"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := DecodeJSON(s, []byte(tt.input))
+			require.NoError(t, err)
+			out, err := EncodeJSON(s, v)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(out))
+
+			// Canonical output is stable.
+			v, err = DecodeJSON(s, out)
+			require.NoError(t, err)
+			again, err := EncodeJSON(s, v)
+			require.NoError(t, err)
+			require.Equal(t, string(out), string(again))
+		})
+	}
+}
+
+func TestDecodeJSON_Types(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0 Library
+types:
+  Event:
+    type: object
+    properties:
+      seq:
+        type: integer
+        format: int32
+      at?: date-only
+      blob?: file
+`)
+	v, err := DecodeJSON(libraryType(t, rml, "Event"), []byte(`{"seq": 5, "at": "2024-01-02", "blob": "aGk="}`))
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"seq":  int64(5),
+		"at":   time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		"blob": []byte("hi"),
+	}, v)
+}
+
+func TestDecodeJSON_Errors(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0 Library
+types:
+  Event:
+    type: object
+    properties:
+      id: integer
+      seq?:
+        type: integer
+        format: int8
+      at?: date-only
+`)
+	s := libraryType(t, rml, "Event")
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "malformed", input: `{"id": }`, wantErr: "unmarshal json"},
+		{name: "trailing data", input: `{"id": 1} {}`, wantErr: "unexpected data after top-level value"},
+		{name: "not an integer", input: `{"id": 1.5}`, wantErr: "$.id: number 1.5 is not an integer"},
+		{name: "out of format range", input: `{"id": 1, "seq": 1e3}`, wantErr: "$.seq: integer 1e3 is out of range of format int8"},
+		{name: "huge exponent", input: `{"id": 1e9999999}`, wantErr: "$.id: exponent of number must not exceed 10000"},
+		{name: "huge negative exponent", input: `{"id": 1e-9999999}`, wantErr: "$.id: exponent of number must not exceed 10000"},
+		{name: "invalid date", input: `{"id": 1, "at": "2024-02-30"}`, wantErr: "$.at: value must match format"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeJSON(s, []byte(tt.input))
+			require.ErrorContains(t, err, tt.wantErr)
+			require.Less(t, len(err.Error()), 200)
+		})
+	}
+}
+
+func Test_checkNumberExponent(t *testing.T) {
+	tests := []struct {
+		n       string
+		wantErr bool
+	}{
+		{n: "1"},
+		{n: "1.5e0"},
+		{n: "1e+10000"},
+		{n: "1E-00010000"},
+		{n: "1e10001", wantErr: true},
+		{n: "1e-99999999999999999999", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.n, func(t *testing.T) {
+			err := checkNumberExponent(tt.n)
+			if tt.wantErr {
+				require.Error(t, err)
+				require.False(t, strings.Contains(err.Error(), tt.n))
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,311 @@
+package raml
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CoercionError describes a value that cannot be converted to the type of the shape.
+type CoercionError struct {
+	// Path is the path of the value in the format used by Validate, e.g. "$.a[0]".
+	Path  string
+	Value interface{}
+	Err   error
+}
+
+func (e *CoercionError) Error() string {
+	return fmt.Sprintf("coerce %s: %v", e.Path, e.Err)
+}
+
+func (e *CoercionError) Unwrap() error {
+	return e.Err
+}
+
+// CoercionErrors is a list of errors that occurred during coercion.
+type CoercionErrors []*CoercionError
+
+func (e CoercionErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Coerce converts string inputs such as query parameters, headers, URI parameters and form fields
+// to typed values expected by Validate of the shape:
+//   - integers to int (or uint if exceeds int64), checked against the format range;
+//   - numbers to int, uint or float64, integers that exceed uint to *big.Int;
+//   - "true" and "false" to bool;
+//   - "null" and empty string to nil for nil type;
+//   - date types are checked against their formats, time.Time is formatted to string.
+//
+// Arrays accept a slice of strings (repeated parameters) or a single string, objects accept
+// map[string]string, map[string][]string and url.Values. Unions take the first member the value
+// is coerced to; members coerced to the same Go type are chosen among by Validate.
+// Values that are already typed are kept as is. The returned error is CoercionErrors,
+// the returned value contains the original values in place of those that cannot be converted.
+func Coerce(s Shape, v interface{}, ctxPath string) (interface{}, error) {
+	c := &coercer{}
+	res := c.coerce(s, v, ctxPath)
+	if len(c.errs) > 0 {
+		return res, c.errs
+	}
+	return res, nil
+}
+
+type coercer struct {
+	errs CoercionErrors
+}
+
+func (c *coercer) fail(path string, v interface{}, format string, args ...interface{}) interface{} {
+	c.errs = append(c.errs, &CoercionError{Path: path, Value: v, Err: fmt.Errorf(format, args...)})
+	return v
+}
+
+func (c *coercer) coerce(s Shape, v interface{}, path string) interface{} {
+	// Single values of repeated parameters are unwrapped for non-array shapes.
+	if vs, ok := v.([]string); ok {
+		if _, isArray := s.(*ArrayShape); !isArray {
+			if _, isUnion := s.(*UnionShape); !isUnion {
+				if len(vs) != 1 {
+					return c.fail(path, v, "expected single value, got %d", len(vs))
+				}
+				v = vs[0]
+			}
+		}
+	}
+	switch s := s.(type) {
+	case *IntegerShape:
+		return c.coerceInteger(s, v, path)
+	case *NumberShape:
+		return c.coerceNumber(s, v, path)
+	case *BooleanShape:
+		if str, ok := v.(string); ok {
+			switch str {
+			case "true":
+				return true
+			case "false":
+				return false
+			}
+			return c.fail(path, v, "invalid boolean %q", str)
+		}
+	case *NilShape:
+		if str, ok := v.(string); ok {
+			if str == "" || str == "null" {
+				return nil
+			}
+			return c.fail(path, v, "invalid nil %q", str)
+		}
+	case *DateTimeShape:
+		layout := time.RFC3339
+		if s.Format != nil && *s.Format == "rfc2616" {
+			layout = RFC2616
+		}
+		return c.coerceTime(s, v, layout, path)
+	case *DateTimeOnlyShape:
+		return c.coerceTime(s, v, DateTime, path)
+	case *DateOnlyShape:
+		return c.coerceTime(s, v, time.DateOnly, path)
+	case *TimeOnlyShape:
+		return c.coerceTime(s, v, time.TimeOnly, path)
+	case *ArrayShape:
+		return c.coerceArray(s, v, path)
+	case *ObjectShape:
+		return c.coerceObject(s, v, path)
+	case *UnionShape:
+		return c.coerceUnion(s, v, path)
+	case *RecursiveShape:
+		return c.coerce(*s.Head, v, path)
+	}
+	return v
+}
+
+func (c *coercer) coerceInteger(s *IntegerShape, v interface{}, path string) interface{} {
+	str, ok := v.(string)
+	if !ok {
+		return v
+	}
+	n, ok := new(big.Int).SetString(str, 10)
+	if !ok {
+		return c.fail(path, v, "invalid integer %q", str)
+	}
+	if lo, hi := integerFormatBounds(s.Format); lo != nil && (n.Cmp(lo) < 0 || n.Cmp(hi) > 0) {
+		return c.fail(path, v, "integer %s is out of range of format %s", str, *s.Format)
+	}
+	switch {
+	case n.IsInt64() && n.Int64() >= math.MinInt && n.Int64() <= math.MaxInt:
+		return int(n.Int64())
+	case n.IsUint64() && n.Uint64() <= math.MaxUint:
+		return uint(n.Uint64())
+	}
+	return c.fail(path, v, "integer %s is out of range", str)
+}
+
+func (c *coercer) coerceNumber(s *NumberShape, v interface{}, path string) interface{} {
+	str, ok := v.(string)
+	if !ok {
+		return v
+	}
+	// Integers are parsed exactly, so that those that exceed int do not lose precision as float64.
+	if n, ok := new(big.Int).SetString(str, 10); ok {
+		if lo, hi := integerFormatBounds(s.Format); lo != nil && (n.Cmp(lo) < 0 || n.Cmp(hi) > 0) {
+			return c.fail(path, v, "number %s is out of range of format %s", str, *s.Format)
+		}
+		switch {
+		case n.IsInt64() && n.Int64() >= math.MinInt && n.Int64() <= math.MaxInt:
+			return int(n.Int64())
+		case n.IsUint64() && n.Uint64() <= math.MaxUint:
+			return uint(n.Uint64())
+		}
+		return n
+	}
+	f, err := strconv.ParseFloat(str, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return c.fail(path, v, "invalid number %q", str)
+	}
+	if s.Format != nil {
+		switch *s.Format {
+		case "float":
+			if math.Abs(f) > math.MaxFloat32 {
+				return c.fail(path, v, "number %s is out of range of format %s", str, *s.Format)
+			}
+		case "double":
+		default:
+			if _, ok := SetOfIntegerFormats[*s.Format]; ok {
+				return c.fail(path, v, "number %s is not an integer of format %s", str, *s.Format)
+			}
+		}
+	}
+	return f
+}
+
+// coerceTime formats time.Time values with the layout and checks that strings match the grammar of the shape.
+func (c *coercer) coerceTime(s Shape, v interface{}, layout string, path string) interface{} {
+	switch v := v.(type) {
+	case time.Time:
+		if layout == RFC2616 {
+			// HTTP dates are always in GMT.
+			v = v.UTC()
+		}
+		return v.Format(layout)
+	case string:
+		if _, err := parseDateValue(s, v); err != nil {
+			return c.fail(path, v, "value must match format %s: %v", layout, err)
+		}
+	}
+	return v
+}
+
+func (c *coercer) coerceArray(s *ArrayShape, v interface{}, path string) interface{} {
+	var items []interface{}
+	switch v := v.(type) {
+	case []interface{}:
+		items = make([]interface{}, len(v))
+		copy(items, v)
+	case []string:
+		items = make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = item
+		}
+	case string:
+		// Repeated parameter that occurs once.
+		items = []interface{}{v}
+	default:
+		return v
+	}
+	if s.Items == nil {
+		return items
+	}
+	for i, item := range items {
+		items[i] = c.coerce(*s.Items, item, path+"["+strconv.Itoa(i)+"]")
+	}
+	return items
+}
+
+func (c *coercer) coerceObject(s *ObjectShape, v interface{}, path string) interface{} {
+	var m map[string]interface{}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m = make(map[string]interface{}, len(v))
+		for k, item := range v {
+			m[k] = item
+		}
+	case map[string]string:
+		m = make(map[string]interface{}, len(v))
+		for k, item := range v {
+			m[k] = item
+		}
+	case map[string][]string:
+		m = make(map[string]interface{}, len(v))
+		for k, item := range v {
+			m[k] = item
+		}
+	case url.Values:
+		return c.coerceObject(s, map[string][]string(v), path)
+	default:
+		return v
+	}
+	// Keys are sorted to report errors in a stable order.
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		item := m[k]
+		ctxPath := path + "." + k
+		// Explicitly defined properties have priority over pattern properties.
+		if s.Properties != nil {
+			if p, ok := s.Properties.Get(k); ok {
+				m[k] = c.coerce(*p.Shape, item, ctxPath)
+				continue
+			}
+		}
+		if s.PatternProperties != nil {
+			for pair := s.PatternProperties.Oldest(); pair != nil; pair = pair.Next() {
+				if pair.Value.Pattern.MatchString(k) {
+					m[k] = c.coerce(*pair.Value.Shape, item, ctxPath)
+					break
+				}
+			}
+		}
+	}
+	return m
+}
+
+func (c *coercer) coerceUnion(s *UnionShape, v interface{}, path string) interface{} {
+	var first interface{}
+	var kind reflect.Type
+	found := false
+	for _, member := range s.AnyOf {
+		mc := &coercer{}
+		res := mc.coerce(*member, v, path)
+		if len(mc.errs) > 0 {
+			continue
+		}
+		// The first member the value is coerced to determines the type, so that a facet mismatch
+		// does not turn the value into a later member of a different type.
+		if !found {
+			first, kind, found = res, reflect.TypeOf(res), true
+		} else if reflect.TypeOf(res) != kind {
+			continue
+		}
+		if (*member).Validate(res, path) == nil {
+			return res
+		}
+	}
+	if found {
+		// Value is left for Validate to report the facet mismatch.
+		return first
+	}
+	// Value is left for Validate to report the mismatch.
+	return v
+}
@@ -0,0 +1,122 @@
+package raml
+
+import (
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoerce(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0 Library
+types:
+  Int32:
+    type: integer
+    format: int32
+  Num: number
+  Float:
+    type: number
+    format: float
+  Flag: boolean
+  Day: date-only
+  Clock: time-only
+  Modified:
+    type: datetime
+    format: rfc2616
+  Color:
+    type: integer
+    enum: [1, 2]
+  ColorOrName: Color | string
+  Short:
+    type: string
+    maxLength: 2
+  ShortOrLong: Short | string
+  Query:
+    type: object
+    properties:
+      page: integer
+      tags: string[]
+`)
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	tests := []struct {
+		name    string
+		typ     string
+		value   interface{}
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "integer", typ: "Int32", value: "42", want: 42},
+		{name: "integer out of format range", typ: "Int32", value: "2147483648", want: "2147483648", wantErr: true},
+		{name: "invalid integer", typ: "Int32", value: "4.2", want: "4.2", wantErr: true},
+		{name: "number as integer", typ: "Num", value: "-7", want: -7},
+		{name: "number as uint", typ: "Num", value: "18446744073709551615", want: uint(18446744073709551615)},
+		{name: "number exceeding uint", typ: "Num", value: "123456789012345678901234567890", want: huge},
+		{name: "number as float", typ: "Num", value: "2.5", want: 2.5},
+		{name: "invalid number", typ: "Num", value: "NaN", want: "NaN", wantErr: true},
+		{name: "float out of format range", typ: "Float", value: "1e39", want: "1e39", wantErr: true},
+		{name: "boolean", typ: "Flag", value: "true", want: true},
+		{name: "invalid boolean", typ: "Flag", value: "yes", want: "yes", wantErr: true},
+		{name: "date", typ: "Day", value: "2024-01-02", want: "2024-01-02"},
+		{name: "invalid date", typ: "Day", value: "2024-1-2", want: "2024-1-2", wantErr: true},
+		{name: "invalid day of month", typ: "Day", value: "2024-02-30", want: "2024-02-30", wantErr: true},
+		{name: "time with fraction", typ: "Clock", value: "03:04:05.5", want: "03:04:05.5"},
+		{name: "leap second not at end of day", typ: "Clock", value: "03:04:60", want: "03:04:60", wantErr: true},
+		{name: "rfc2616 date", typ: "Modified", value: "Tue, 02 Jan 2024 03:04:05 GMT", want: "Tue, 02 Jan 2024 03:04:05 GMT"},
+		{name: "rfc2616 wrong day name", typ: "Modified", value: "Mon, 02 Jan 2024 03:04:05 GMT", want: "Mon, 02 Jan 2024 03:04:05 GMT", wantErr: true},
+		{
+			name:  "rfc2616 time in other location",
+			typ:   "Modified",
+			value: time.Date(2020, 1, 1, 12, 0, 0, 0, time.FixedZone("", 3*60*60)),
+			want:  "Wed, 01 Jan 2020 09:00:00 GMT",
+		},
+		{name: "date from time", typ: "Day", value: time.Date(2020, 1, 1, 23, 0, 0, 0, time.UTC), want: "2020-01-01"},
+		{name: "repeated value", typ: "Int32", value: []string{"1"}, want: 1},
+		{name: "repeated values", typ: "Int32", value: []string{"1", "2"}, want: []string{"1", "2"}, wantErr: true},
+		{name: "typed value", typ: "Int32", value: 5, want: 5},
+		{name: "union member", typ: "ColorOrName", value: "2", want: 2},
+		{name: "union enum mismatch keeps type", typ: "ColorOrName", value: "3", want: 3},
+		{name: "union string member", typ: "ColorOrName", value: "red", want: "red"},
+		{name: "union chosen by validation", typ: "ShortOrLong", value: "long", want: "long"},
+		{
+			name:  "object",
+			typ:   "Query",
+			value: url.Values{"page": {"2"}, "tags": {"a", "b"}},
+			want:  map[string]interface{}{"page": 2, "tags": []interface{}{"a", "b"}},
+		},
+		{
+			name:    "object with invalid property",
+			typ:     "Query",
+			value:   map[string]string{"page": "x", "tags": "a"},
+			want:    map[string]interface{}{"page": "x", "tags": []interface{}{"a"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Coerce(libraryType(t, rml, tt.typ), tt.value, "$")
+			if tt.wantErr {
+				var errs CoercionErrors
+				require.ErrorAs(t, err, &errs)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCoerce_UnionFacetMismatch(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0 Library
+types:
+  Color:
+    type: integer
+    enum: [1, 2]
+  ColorOrName: Color | string
+`)
+	s := libraryType(t, rml, "ColorOrName")
+	got, err := Coerce(s, "3", "$")
+	require.NoError(t, err)
+	require.Error(t, s.Validate(got, "$"))
+}
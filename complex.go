@@ -2,7 +2,6 @@ package raml
 
 import (
 	"fmt"
-	"regexp"
 	"strconv"
 
 	orderedmap "github.com/wk8/go-ordered-map/v2"
@@ -66,7 +65,7 @@ func (s *ArrayShape) Validate(v interface{}, ctxPath string) error {
 		return fmt.Errorf("array must have not more than %d items", *s.MaxItems)
 	}
 	validateUniqueItems := s.UniqueItems != nil && *s.UniqueItems
-	uniqueItems := make(map[interface{}]struct{})
+	uniqueItems := make(map[string]struct{})
 	for ii, item := range i {
 		ctxPath := ctxPath + "[" + strconv.Itoa(ii) + "]"
 		if s.Items != nil {
@@ -75,7 +74,7 @@ func (s *ArrayShape) Validate(v interface{}, ctxPath string) error {
 			}
 		}
 		if validateUniqueItems {
-			uniqueItems[item] = struct{}{}
+			uniqueItems[valueKey(item)] = struct{}{}
 		}
 	}
 	if validateUniqueItems && len(uniqueItems) != len(i) {
@@ -192,6 +191,10 @@ type ObjectShape struct {
 	BaseShape
 
 	ObjectFacets
+
+	// typeName is the name of the declared type the shape originates from.
+	// It is set during unwrapping since unwrapped aliases keep Id of the declared type but not its name.
+	typeName string
 }
 
 // UnmarshalYAMLNodes unmarshals the object shape from YAML nodes.
@@ -333,7 +336,11 @@ func (s *ObjectShape) Validate(v interface{}, ctxPath string) error {
 				pp := pair.Value
 				// NOTE: We validate only those keys that match the pattern.
 				// The keys that do not match are considered as additional properties and are not validated.
-				if pp.Pattern.MatchString(k) {
+				matched, err := matchRegexp(pp.Pattern, k)
+				if err != nil {
+					return fmt.Errorf("match pattern property %s: %w", ctxPath, err)
+				}
+				if matched {
 					ps := *pp.Shape
 					// NOTE: The first defined pattern property to validate prevails.
 					if err := ps.Validate(item, ctxPath); err == nil {
@@ -351,10 +358,29 @@ func (s *ObjectShape) Validate(v interface{}, ctxPath string) error {
 			return fmt.Errorf("unexpected additional property \"%s\"", k)
 		}
 	}
+	if s.Properties != nil {
+		for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+			if _, ok := i[pair.Key]; !ok && pair.Value.Required {
+				return fmt.Errorf("required property \"%s\" is missing", pair.Key)
+			}
+		}
+	}
 
 	return nil
 }
 
+// discriminatorValue returns the value of discriminator property that identifies the shape.
+// It is either the discriminatorValue facet or the name of the declared type the shape originates from.
+func (s *ObjectShape) discriminatorValue() any {
+	if s.DiscriminatorValue != nil {
+		return s.DiscriminatorValue
+	}
+	if s.typeName != "" {
+		return s.typeName
+	}
+	return s.Name
+}
+
 // Inherit merges the source shape into the target shape.
 func (s *ObjectShape) Inherit(source Shape) (Shape, error) {
 	ss, ok := source.(*ObjectShape)
@@ -489,7 +515,7 @@ func (r *RAML) makePatternProperty(nodeName string, propertyName string, v *yaml
 	if (*shape).Base().Required != nil || hasImplicitOptional {
 		return PatternProperty{}, stacktrace.New("'required' facet is not supported on pattern property", location, stacktrace.WithNodePosition(v))
 	}
-	re, err := regexp.Compile(propertyName[1 : len(propertyName)-1])
+	re, err := r.compileRegexp(propertyName[1 : len(propertyName)-1])
 	if err != nil {
 		return PatternProperty{}, stacktrace.NewWrapped("compile pattern", err, location, stacktrace.WithNodePosition(v))
 	}
@@ -546,7 +572,7 @@ type Property struct {
 
 // Property represents a pattern property of an object shape.
 type PatternProperty struct {
-	Pattern *regexp.Regexp
+	Pattern Regexp
 	Shape   *Shape
 	// Pattern properties are always optional.
 	raml *RAML
@@ -599,7 +625,14 @@ func (s *UnionShape) clone(history []Shape) Shape {
 
 func (s *UnionShape) Validate(v interface{}, ctxPath string) error {
 	// TODO: Collect errors
+	m, isMap := v.(map[string]interface{})
 	for _, item := range s.AnyOf {
+		// Discriminated objects accept only values with their discriminator value.
+		if obj, ok := (*item).(*ObjectShape); ok && isMap && obj.Discriminator != nil {
+			if dv, ok := m[*obj.Discriminator]; ok && !valuesEqual(dv, obj.discriminatorValue()) {
+				continue
+			}
+		}
 		if err := (*item).Validate(v, ctxPath); err == nil {
 			return nil
 		}
@@ -706,6 +739,71 @@ func (s *JSONShape) Check() error {
 	return nil
 }
 
+// XMLShape is a shape defined by an XML Schema, either inline or included from an XSD file.
+type XMLShape struct {
+	BaseShape
+
+	Schema *XSDSchema
+	Raw    string
+}
+
+func (s *XMLShape) Base() *BaseShape {
+	return &s.BaseShape
+}
+
+func (s *XMLShape) Clone() Shape {
+	c := *s
+	return &c
+}
+
+func (s *XMLShape) clone(history []Shape) Shape {
+	return s.Clone()
+}
+
+// Validate validates the XML document given as string or byte slice against the schema.
+func (s *XMLShape) Validate(v interface{}, ctxPath string) error {
+	var doc string
+	switch val := v.(type) {
+	case string:
+		doc = val
+	case []byte:
+		doc = string(val)
+	default:
+		return fmt.Errorf("invalid type, got %T, expected XML document", v)
+	}
+	if s.Schema == nil {
+		return nil
+	}
+	return s.Schema.Validate(doc)
+}
+
+func (s *XMLShape) unmarshalYAMLNodes(v []*yaml.Node) error {
+	return nil
+}
+
+func (s *XMLShape) Inherit(source Shape) (Shape, error) {
+	ss, ok := source.(*XMLShape)
+	if !ok {
+		return nil, stacktrace.New("cannot inherit from different type", s.Location,
+			stacktrace.WithPosition(&s.Position), stacktrace.WithInfo("source", source.Base().Type),
+			stacktrace.WithInfo("target", s.Base().Type))
+	}
+	if s.Raw != "" && ss.Raw != "" && s.Raw != ss.Raw {
+		return nil, stacktrace.New("cannot inherit from different XML schema", s.Location,
+			stacktrace.WithPosition(&s.Position))
+	}
+	s.Schema = ss.Schema
+	s.Raw = ss.Raw
+	return s, nil
+}
+
+func (s *XMLShape) Check() error {
+	if s.Schema == nil {
+		return stacktrace.New("xml schema is not defined", s.Location, stacktrace.WithPosition(&s.Position))
+	}
+	return nil
+}
+
 type UnknownShape struct {
 	BaseShape
 
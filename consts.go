@@ -61,5 +61,6 @@ const (
 const (
 	TypeUnion     = "union"     // Can be used in RAML
 	TypeJSON      = "json"      // Cannot be used in RAML
+	TypeXML       = "xml"       // Cannot be used in RAML
 	TypeComposite = "composite" // Cannot be used in RAML
 )
@@ -0,0 +1,486 @@
+package raml
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Grammar-based parsers of RAML date types. The grammar of date-only, time-only, datetime-only and datetime
+// in rfc3339 format is full-date, partial-time, full-date "T" partial-time and date-time of RFC 3339.
+// Datetime in rfc2616 format is HTTP-date of RFC 7231 section 7.1.1.1: IMF-fixdate, rfc850-date or asctime-date.
+// time.Time cannot represent leap seconds, so they are parsed as the first second of the next minute.
+
+var (
+	httpDayNames     = []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+	httpLongDayNames = []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+	httpMonthNames   = []string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"}
+)
+
+// dateScanner reads components of a date string and reports errors with their positions.
+type dateScanner struct {
+	s   string
+	pos int
+}
+
+func (d *dateScanner) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("at position %d: %s", d.pos, fmt.Sprintf(format, args...))
+}
+
+// number reads exactly n digits of the component.
+func (d *dateScanner) number(n int, component string) (int, error) {
+	if len(d.s)-d.pos < n {
+		return 0, d.errorf("%s must have %d digits", component, n)
+	}
+	v := 0
+	for i := 0; i < n; i++ {
+		c := d.s[d.pos+i]
+		if c < '0' || c > '9' {
+			return 0, d.errorf("%s must have %d digits", component, n)
+		}
+		v = v*10 + int(c-'0')
+	}
+	d.pos += n
+	return v, nil
+}
+
+// ranged reads exactly n digits of the component that must be in [lo, hi].
+func (d *dateScanner) ranged(n int, component string, lo int, hi int) (int, error) {
+	start := d.pos
+	v, err := d.number(n, component)
+	if err != nil {
+		return 0, err
+	}
+	if v < lo || v > hi {
+		d.pos = start
+		return 0, d.errorf("%s %0*d is out of range %0*d-%0*d", component, n, v, n, lo, n, hi)
+	}
+	return v, nil
+}
+
+func (d *dateScanner) expect(sep string) error {
+	if !strings.HasPrefix(d.s[d.pos:], sep) {
+		return d.errorf("expected %q", sep)
+	}
+	d.pos += len(sep)
+	return nil
+}
+
+// oneOf reads one of the names and returns its index.
+func (d *dateScanner) oneOf(names []string, component string) (int, error) {
+	for i, name := range names {
+		if strings.HasPrefix(d.s[d.pos:], name) {
+			d.pos += len(name)
+			return i, nil
+		}
+	}
+	return 0, d.errorf("invalid %s", component)
+}
+
+func (d *dateScanner) end() error {
+	if d.pos != len(d.s) {
+		return d.errorf("unexpected trailing characters %q", d.s[d.pos:])
+	}
+	return nil
+}
+
+// day reads the day of month that must exist in the month of the year.
+func (d *dateScanner) day(year int, month int) (int, error) {
+	start := d.pos
+	v, err := d.number(2, "day")
+	if err != nil {
+		return 0, err
+	}
+	if err := d.checkDay(start, year, month, v); err != nil {
+		return 0, err
+	}
+	return v, nil
+}
+
+// checkDay checks that the day that starts at the position exists in the month of the year.
+func (d *dateScanner) checkDay(pos int, year int, month int, day int) error {
+	last := time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if day < 1 || day > last {
+		return fmt.Errorf("at position %d: day %02d is out of range 01-%02d of %04d-%02d", pos, day, last, year, month)
+	}
+	return nil
+}
+
+// fullDate reads date-fullyear "-" date-month "-" date-mday.
+func (d *dateScanner) fullDate() (year int, month int, day int, err error) {
+	if year, err = d.number(4, "year"); err != nil {
+		return
+	}
+	if err = d.expect("-"); err != nil {
+		return
+	}
+	if month, err = d.ranged(2, "month", 1, 12); err != nil {
+		return
+	}
+	if err = d.expect("-"); err != nil {
+		return
+	}
+	day, err = d.day(year, month)
+	return
+}
+
+// clock reads time-hour ":" time-minute ":" time-second, where the second may be a leap second.
+func (d *dateScanner) clock() (hour int, minute int, second int, err error) {
+	if hour, err = d.ranged(2, "hour", 0, 23); err != nil {
+		return
+	}
+	if err = d.expect(":"); err != nil {
+		return
+	}
+	if minute, err = d.ranged(2, "minute", 0, 59); err != nil {
+		return
+	}
+	if err = d.expect(":"); err != nil {
+		return
+	}
+	second, err = d.ranged(2, "second", 0, 60)
+	return
+}
+
+// partialTime reads clock [time-secfrac]. Fractional seconds of any precision are accepted
+// and truncated to nanoseconds.
+func (d *dateScanner) partialTime() (hour int, minute int, second int, nsec int, err error) {
+	if hour, minute, second, err = d.clock(); err != nil {
+		return
+	}
+	if d.pos == len(d.s) || d.s[d.pos] != '.' {
+		return
+	}
+	d.pos++
+	digits := 0
+	for ; d.pos < len(d.s) && d.s[d.pos] >= '0' && d.s[d.pos] <= '9'; d.pos++ {
+		if digits < 9 {
+			nsec = nsec*10 + int(d.s[d.pos]-'0')
+		}
+		digits++
+	}
+	if digits == 0 {
+		err = d.errorf("fractional seconds must have at least one digit")
+		return
+	}
+	for i := digits; i < 9; i++ {
+		nsec *= 10
+	}
+	return
+}
+
+// timeOffset reads "Z" or time-numoffset and returns its location and the offset in minutes.
+func (d *dateScanner) timeOffset() (*time.Location, int, error) {
+	if d.pos == len(d.s) {
+		return nil, 0, d.errorf("time offset is missing")
+	}
+	switch d.s[d.pos] {
+	case 'Z', 'z':
+		d.pos++
+		return time.UTC, 0, nil
+	case '+', '-':
+	default:
+		return nil, 0, d.errorf("time offset must be \"Z\" or start with \"+\" or \"-\"")
+	}
+	sign := 1
+	if d.s[d.pos] == '-' {
+		sign = -1
+	}
+	d.pos++
+	hour, err := d.ranged(2, "offset hour", 0, 23)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := d.expect(":"); err != nil {
+		return nil, 0, err
+	}
+	minute, err := d.ranged(2, "offset minute", 0, 59)
+	if err != nil {
+		return nil, 0, err
+	}
+	offset := sign * (hour*60 + minute)
+	return time.FixedZone("", offset*60), offset, nil
+}
+
+// checkLeapSecond checks that a leap second is at the end of a UTC day.
+// The offset is in minutes and the time is local to it.
+func checkLeapSecond(hour int, minute int, second int, offset int) error {
+	if second != 60 {
+		return nil
+	}
+	utc := ((hour*60+minute-offset)%1440 + 1440) % 1440
+	if utc != 23*60+59 {
+		return fmt.Errorf("leap second is allowed only at 23:59:60 UTC")
+	}
+	return nil
+}
+
+// parseRFC3339 parses date-time of RFC 3339.
+func parseRFC3339(s string) (time.Time, error) {
+	d := &dateScanner{s: s}
+	year, month, day, err := d.fullDate()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if d.pos == len(s) || (s[d.pos] != 'T' && s[d.pos] != 't') {
+		return time.Time{}, d.errorf("expected \"T\"")
+	}
+	d.pos++
+	hour, minute, second, nsec, err := d.partialTime()
+	if err != nil {
+		return time.Time{}, err
+	}
+	loc, offset, err := d.timeOffset()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := d.end(); err != nil {
+		return time.Time{}, err
+	}
+	if err := checkLeapSecond(hour, minute, second, offset); err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(year, time.Month(month), day, hour, minute, second, nsec, loc), nil
+}
+
+// parseDateTimeOnly parses full-date "T" partial-time.
+func parseDateTimeOnly(s string) (time.Time, error) {
+	d := &dateScanner{s: s}
+	year, month, day, err := d.fullDate()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := d.expect("T"); err != nil {
+		return time.Time{}, err
+	}
+	hour, minute, second, nsec, err := d.partialTime()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := d.end(); err != nil {
+		return time.Time{}, err
+	}
+	if err := checkLeapSecond(hour, minute, second, 0); err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(year, time.Month(month), day, hour, minute, second, nsec, time.UTC), nil
+}
+
+// parseDateOnly parses full-date.
+func parseDateOnly(s string) (time.Time, error) {
+	d := &dateScanner{s: s}
+	year, month, day, err := d.fullDate()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := d.end(); err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC), nil
+}
+
+// parseTimeOnly parses partial-time. The date of the result is January 1, year 0 as in time.Parse.
+func parseTimeOnly(s string) (time.Time, error) {
+	d := &dateScanner{s: s}
+	hour, minute, second, nsec, err := d.partialTime()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := d.end(); err != nil {
+		return time.Time{}, err
+	}
+	if err := checkLeapSecond(hour, minute, second, 0); err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(0, time.January, 1, hour, minute, second, nsec, time.UTC), nil
+}
+
+// parseHTTPDate parses HTTP-date in any of the three formats that recipients must accept.
+func parseHTTPDate(s string) (time.Time, error) {
+	switch {
+	case len(s) > 3 && s[3] == ',':
+		return parseIMFFixdate(s)
+	case len(s) > 3 && s[3] == ' ':
+		return parseASCTimeDate(s)
+	}
+	return parseRFC850Date(s)
+}
+
+// parseIMFFixdate parses day-name "," SP date1 SP time-of-day SP GMT, e.g. "Sun, 06 Nov 1994 08:49:37 GMT".
+func parseIMFFixdate(s string) (time.Time, error) {
+	d := &dateScanner{s: s}
+	weekday, err := d.oneOf(httpDayNames, "day name")
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := d.expect(", "); err != nil {
+		return time.Time{}, err
+	}
+	dayStart := d.pos
+	day, err := d.number(2, "day")
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := d.expect(" "); err != nil {
+		return time.Time{}, err
+	}
+	month, err := d.oneOf(httpMonthNames, "month")
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := d.expect(" "); err != nil {
+		return time.Time{}, err
+	}
+	year, err := d.number(4, "year")
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := d.checkDay(dayStart, year, month+1, day); err != nil {
+		return time.Time{}, err
+	}
+	return d.httpDateEnd(year, month+1, day, weekday)
+}
+
+// parseRFC850Date parses day-name-l "," SP date2 SP time-of-day SP GMT, e.g. "Sunday, 06-Nov-94 08:49:37 GMT".
+// Two-digit years that appear to be more than 50 years in the future denote the most recent past year
+// with the same last two digits.
+func parseRFC850Date(s string) (time.Time, error) {
+	d := &dateScanner{s: s}
+	weekday, err := d.oneOf(httpLongDayNames, "day name")
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := d.expect(", "); err != nil {
+		return time.Time{}, err
+	}
+	dayStart := d.pos
+	day, err := d.number(2, "day")
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := d.expect("-"); err != nil {
+		return time.Time{}, err
+	}
+	month, err := d.oneOf(httpMonthNames, "month")
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := d.expect("-"); err != nil {
+		return time.Time{}, err
+	}
+	yy, err := d.number(2, "year")
+	if err != nil {
+		return time.Time{}, err
+	}
+	now := time.Now().UTC().Year()
+	year := now/100*100 + yy
+	if year > now+50 {
+		year -= 100
+	}
+	if err := d.checkDay(dayStart, year, month+1, day); err != nil {
+		return time.Time{}, err
+	}
+	return d.httpDateEnd(year, month+1, day, weekday)
+}
+
+// parseASCTimeDate parses day-name SP month SP ( 2DIGIT / ( SP DIGIT )) SP time-of-day SP year,
+// e.g. "Sun Nov  6 08:49:37 1994".
+func parseASCTimeDate(s string) (time.Time, error) {
+	d := &dateScanner{s: s}
+	weekday, err := d.oneOf(httpDayNames, "day name")
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := d.expect(" "); err != nil {
+		return time.Time{}, err
+	}
+	month, err := d.oneOf(httpMonthNames, "month")
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := d.expect(" "); err != nil {
+		return time.Time{}, err
+	}
+	// The day is either two digits or a space and a digit.
+	if d.pos < len(s) && s[d.pos] == ' ' {
+		d.pos++
+	}
+	dayStart := d.pos
+	var day int
+	if d.pos+1 < len(s) && s[d.pos+1] == ' ' {
+		day, err = d.number(1, "day")
+	} else {
+		day, err = d.number(2, "day")
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := d.expect(" "); err != nil {
+		return time.Time{}, err
+	}
+	hour, minute, second, err := d.clock()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := d.expect(" "); err != nil {
+		return time.Time{}, err
+	}
+	year, err := d.number(4, "year")
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := d.end(); err != nil {
+		return time.Time{}, err
+	}
+	if err := d.checkDay(dayStart, year, month+1, day); err != nil {
+		return time.Time{}, err
+	}
+	return httpDate(year, month+1, day, hour, minute, second, weekday)
+}
+
+// httpDateEnd reads SP time-of-day SP "GMT" that ends IMF-fixdate and rfc850-date.
+func (d *dateScanner) httpDateEnd(year int, month int, day int, weekday int) (time.Time, error) {
+	if err := d.expect(" "); err != nil {
+		return time.Time{}, err
+	}
+	hour, minute, second, err := d.clock()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := d.expect(" GMT"); err != nil {
+		return time.Time{}, err
+	}
+	if err := d.end(); err != nil {
+		return time.Time{}, err
+	}
+	return httpDate(year, month, day, hour, minute, second, weekday)
+}
+
+// httpDate returns the time of HTTP-date and checks that the day name matches the date.
+func httpDate(year int, month int, day int, hour int, minute int, second int, weekday int) (time.Time, error) {
+	if err := checkLeapSecond(hour, minute, second, 0); err != nil {
+		return time.Time{}, err
+	}
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if int(date.Weekday()) != weekday {
+		return time.Time{}, fmt.Errorf("day name %s does not match %04d-%02d-%02d, which is %s",
+			httpDayNames[weekday], year, month, day, httpDayNames[date.Weekday()])
+	}
+	return time.Date(year, time.Month(month), day, hour, minute, second, 0, time.UTC), nil
+}
+
+// parseDateValue parses the string as a value of the date type shape.
+func parseDateValue(s Shape, v string) (time.Time, error) {
+	switch s := s.(type) {
+	case *DateTimeShape:
+		if s.Format != nil && *s.Format == "rfc2616" {
+			return parseHTTPDate(v)
+		}
+		return parseRFC3339(v)
+	case *DateTimeOnlyShape:
+		return parseDateTimeOnly(v)
+	case *DateOnlyShape:
+		return parseDateOnly(v)
+	}
+	return parseTimeOnly(v)
+}
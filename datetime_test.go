@@ -0,0 +1,103 @@
+package raml
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseDateValue(t *testing.T) {
+	rfc2616 := "rfc2616"
+	var (
+		datetime     = &DateTimeShape{}
+		httpDatetime = &DateTimeShape{FormatFacets: FormatFacets{Format: &rfc2616}}
+		datetimeOnly = &DateTimeOnlyShape{}
+		dateOnly     = &DateOnlyShape{}
+		timeOnly     = &TimeOnlyShape{}
+	)
+	plus2 := time.FixedZone("", 2*60*60)
+	tests := []struct {
+		name    string
+		shape   Shape
+		value   string
+		want    time.Time
+		wantErr string
+	}{
+		{name: "rfc3339", shape: datetime, value: "2024-01-02T03:04:05Z", want: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{name: "rfc3339 lowercase", shape: datetime, value: "2024-01-02t03:04:05z", want: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{
+			name:  "rfc3339 offset and long fraction",
+			shape: datetime,
+			value: "2024-01-02T03:04:05.1234567891+02:00",
+			want:  time.Date(2024, 1, 2, 3, 4, 5, 123456789, plus2),
+		},
+		{name: "rfc3339 leap second", shape: datetime, value: "2016-12-31T23:59:60Z", want: time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{
+			name:  "rfc3339 leap second in offset",
+			shape: datetime,
+			value: "2017-01-01T01:59:60+02:00",
+			want:  time.Date(2017, 1, 1, 2, 0, 0, 0, plus2),
+		},
+		{name: "rfc3339 leap second not at end of day", shape: datetime, value: "2016-12-31T12:59:60Z", wantErr: "leap second is allowed only at 23:59:60 UTC"},
+		{name: "rfc3339 missing offset", shape: datetime, value: "2024-01-02T03:04:05", wantErr: "at position 19: time offset is missing"},
+		{name: "rfc3339 invalid offset", shape: datetime, value: "2024-01-02T03:04:05 00:00", wantErr: "at position 19: time offset must be"},
+		{name: "rfc3339 empty fraction", shape: datetime, value: "2024-01-02T03:04:05.Z", wantErr: "fractional seconds must have at least one digit"},
+		{name: "rfc3339 single digit hour", shape: datetime, value: "2024-01-02T3:04:05Z", wantErr: "at position 11: hour must have 2 digits"},
+		{name: "rfc3339 space separator", shape: datetime, value: "2024-01-02 03:04:05Z", wantErr: `at position 10: expected "T"`},
+		{name: "datetime-only", shape: datetimeOnly, value: "2024-01-02T03:04:05.5", want: time.Date(2024, 1, 2, 3, 4, 5, 500000000, time.UTC)},
+		{name: "datetime-only with offset", shape: datetimeOnly, value: "2024-01-02T03:04:05Z", wantErr: `unexpected trailing characters "Z"`},
+		{name: "datetime-only lowercase separator", shape: datetimeOnly, value: "2024-01-02t03:04:05", wantErr: `expected "T"`},
+		{name: "date-only", shape: dateOnly, value: "2024-02-29", want: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{name: "date-only not a leap year", shape: dateOnly, value: "2023-02-29", wantErr: "at position 8: day 29 is out of range 01-28 of 2023-02"},
+		{name: "date-only month out of range", shape: dateOnly, value: "2024-13-01", wantErr: "at position 5: month 13 is out of range 01-12"},
+		{name: "date-only short", shape: dateOnly, value: "2024-1-2", wantErr: "month must have 2 digits"},
+		{name: "time-only", shape: timeOnly, value: "23:59:59.999", want: time.Date(0, 1, 1, 23, 59, 59, 999000000, time.UTC)},
+		{name: "time-only hour out of range", shape: timeOnly, value: "24:00:00", wantErr: "hour 24 is out of range 00-23"},
+		{name: "time-only trailing", shape: timeOnly, value: "03:04:05 ", wantErr: "unexpected trailing characters"},
+		{name: "imf-fixdate", shape: httpDatetime, value: "Sun, 06 Nov 1994 08:49:37 GMT", want: time.Date(1994, 11, 6, 8, 49, 37, 0, time.UTC)},
+		{name: "imf-fixdate wrong day name", shape: httpDatetime, value: "Mon, 06 Nov 1994 08:49:37 GMT", wantErr: "day name Mon does not match 1994-11-06, which is Sun"},
+		{name: "imf-fixdate invalid day", shape: httpDatetime, value: "Thu, 31 Nov 1994 08:49:37 GMT", wantErr: "day 31 is out of range 01-30"},
+		{name: "imf-fixdate other zone", shape: httpDatetime, value: "Sun, 06 Nov 1994 08:49:37 UTC", wantErr: `expected " GMT"`},
+		{name: "rfc850-date", shape: httpDatetime, value: "Sunday, 06-Nov-94 08:49:37 GMT", want: time.Date(1994, 11, 6, 8, 49, 37, 0, time.UTC)},
+		{name: "rfc850-date short day name", shape: httpDatetime, value: "Sun, 06-Nov-94 08:49:37 GMT", wantErr: `at position 7: expected " "`},
+		{name: "asctime-date", shape: httpDatetime, value: "Sun Nov  6 08:49:37 1994", want: time.Date(1994, 11, 6, 8, 49, 37, 0, time.UTC)},
+		{name: "asctime-date two digit day", shape: httpDatetime, value: "Thu Nov 10 08:49:37 1994", want: time.Date(1994, 11, 10, 8, 49, 37, 0, time.UTC)},
+		{name: "asctime-date wrong day name", shape: httpDatetime, value: "Mon Nov  6 08:49:37 1994", wantErr: "does not match"},
+		{name: "rfc3339 is not rfc2616", shape: httpDatetime, value: "1994-11-06T08:49:37Z", wantErr: "invalid day name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDateValue(tt.shape, tt.value)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, tt.want.Equal(got), "got %v, want %v", got, tt.want)
+			_, wantOffset := tt.want.Zone()
+			_, gotOffset := got.Zone()
+			require.Equal(t, wantOffset, gotOffset)
+		})
+	}
+}
+
+func Test_parseRFC850Date_Century(t *testing.T) {
+	year := time.Now().UTC().Year()
+	tests := []struct {
+		yy   int
+		want int
+	}{
+		{yy: year % 100, want: year},
+		{yy: (year + 50) % 100, want: year + 50},
+		{yy: (year + 51) % 100, want: year + 51 - 100},
+	}
+	for _, tt := range tests {
+		date := time.Date(tt.want, time.March, 1, 0, 0, 0, 0, time.UTC)
+		s := date.Format("Monday, 02-Jan-") + fmt.Sprintf("%02d", tt.yy) + " 00:00:00 GMT"
+		got, err := parseRFC850Date(s)
+		require.NoError(t, err, s)
+		require.Equal(t, tt.want, got.Year(), s)
+	}
+}
@@ -0,0 +1,438 @@
+package raml
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/acronis/go-raml/stacktrace"
+)
+
+// Formats of documents accepted by ValidateDocument.
+const (
+	DocumentFormatJSON = "json"
+	DocumentFormatYAML = "yaml"
+)
+
+type ValidateDocumentOpt interface {
+	Apply(*ValidateDocumentOptions)
+}
+
+type optDocumentLocation struct {
+	location string
+}
+
+func (o optDocumentLocation) Apply(e *ValidateDocumentOptions) {
+	e.location = o.location
+}
+
+// WithDocumentLocation sets the location of the document that is reported in errors.
+func WithDocumentLocation(location string) ValidateDocumentOpt {
+	return optDocumentLocation{location: location}
+}
+
+type ValidateDocumentOptions struct {
+	location string
+}
+
+// ValidateDocument validates a JSON or YAML document against the shape.
+// Unlike Validate, every violation is reported at the line and column of the offending value.
+// JSON documents are read with encoding/json, so that numbers, strings and positions follow JSON rules.
+// The returned error is a stacktrace.StackTrace with the first violation and the rest in its List.
+func ValidateDocument(s Shape, data []byte, format string, opts ...ValidateDocumentOpt) error {
+	v := &documentValidator{}
+	for _, opt := range opts {
+		opt.Apply(&v.opts)
+	}
+	var root *documentNode
+	switch format {
+	case DocumentFormatJSON:
+		if !json.Valid(data) {
+			// Decoded again to get the syntax error.
+			var val interface{}
+			err := json.Unmarshal(data, &val)
+			return stacktrace.NewWrapped("unmarshal document", err, v.opts.location, stacktrace.WithType(stacktrace.TypeParsing))
+		}
+		p := newJSONDocumentParser(data)
+		n, err := p.node()
+		if err != nil {
+			return stacktrace.NewWrapped("unmarshal document", err, v.opts.location, stacktrace.WithType(stacktrace.TypeParsing))
+		}
+		root = n
+	case DocumentFormatYAML:
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return stacktrace.NewWrapped("unmarshal document", err, v.opts.location, stacktrace.WithType(stacktrace.TypeParsing))
+		}
+		if len(doc.Content) == 0 {
+			return stacktrace.New("document is empty", v.opts.location, stacktrace.WithType(stacktrace.TypeParsing))
+		}
+		root = yamlDocumentNode(doc.Content[0])
+	default:
+		return stacktrace.New("unsupported document format", v.opts.location, stacktrace.WithType(stacktrace.TypeParsing),
+			stacktrace.WithInfo("format", format))
+	}
+	v.validate(s, root, "$")
+	if v.st != nil {
+		return v.st
+	}
+	return nil
+}
+
+type documentNodeKind int
+
+const (
+	documentScalar documentNodeKind = iota
+	documentObject
+	documentArray
+)
+
+// documentNode is a value of the document with its position.
+type documentNode struct {
+	kind documentNodeKind
+	// keys and items hold keys and values of objects, items of arrays.
+	keys  []*documentNode
+	items []*documentNode
+	// value is the decoded scalar, or the decoding error in err.
+	value interface{}
+	err   error
+
+	stacktrace.Position
+}
+
+// yamlDocumentNode converts the YAML node to the document node.
+func yamlDocumentNode(node *yaml.Node) *documentNode {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	n := &documentNode{Position: stacktrace.Position{Line: node.Line, Column: node.Column}}
+	switch node.Kind {
+	case yaml.MappingNode:
+		n.kind = documentObject
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			n.keys = append(n.keys, yamlDocumentNode(node.Content[i]))
+			n.items = append(n.items, yamlDocumentNode(node.Content[i+1]))
+		}
+		return n
+	case yaml.SequenceNode:
+		n.kind = documentArray
+		for _, item := range node.Content {
+			n.items = append(n.items, yamlDocumentNode(item))
+		}
+		return n
+	}
+	if err := node.Decode(&n.value); err != nil {
+		n.err = fmt.Errorf("decode: %w", err)
+	}
+	return n
+}
+
+// key returns the key of the object as a string.
+func (n *documentNode) key() string {
+	if s, ok := n.value.(string); ok {
+		return s
+	}
+	return fmt.Sprint(n.value)
+}
+
+// jsonDocumentParser reads the JSON document by tokens of encoding/json and converts
+// byte offsets of the tokens to lines and columns.
+type jsonDocumentParser struct {
+	data []byte
+	d    *json.Decoder
+	// lines holds offsets of the line starts.
+	lines []int
+}
+
+func newJSONDocumentParser(data []byte) *jsonDocumentParser {
+	p := &jsonDocumentParser{data: data, d: json.NewDecoder(bytes.NewReader(data)), lines: []int{0}}
+	p.d.UseNumber()
+	for i, c := range data {
+		if c == '\n' {
+			p.lines = append(p.lines, i+1)
+		}
+	}
+	return p
+}
+
+// position returns the line and column of the offset. Columns are counted in characters.
+func (p *jsonDocumentParser) position(offset int64) stacktrace.Position {
+	line := sort.Search(len(p.lines), func(i int) bool { return int64(p.lines[i]) > offset }) - 1
+	return stacktrace.Position{
+		Line:   line + 1,
+		Column: utf8.RuneCount(p.data[p.lines[line]:offset]) + 1,
+	}
+}
+
+// token reads the next token and returns the position of its start.
+func (p *jsonDocumentParser) token() (json.Token, stacktrace.Position, error) {
+	prev := p.d.InputOffset()
+	tok, err := p.d.Token()
+	if err != nil {
+		return nil, stacktrace.Position{}, fmt.Errorf("read token: %w", err)
+	}
+	return tok, p.position(jsonTokenStart(p.data, prev)), nil
+}
+
+// node reads the next value.
+func (p *jsonDocumentParser) node() (*documentNode, error) {
+	tok, pos, err := p.token()
+	if err != nil {
+		return nil, err
+	}
+	n := &documentNode{Position: pos}
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '{' {
+			n.kind = documentObject
+		} else {
+			n.kind = documentArray
+		}
+		for p.d.More() {
+			if n.kind == documentObject {
+				k, kpos, err := p.token()
+				if err != nil {
+					return nil, err
+				}
+				n.keys = append(n.keys, &documentNode{value: k, Position: kpos})
+			}
+			item, err := p.node()
+			if err != nil {
+				return nil, err
+			}
+			n.items = append(n.items, item)
+		}
+		// Closing delimiter.
+		if _, err := p.d.Token(); err != nil {
+			return nil, fmt.Errorf("read token: %w", err)
+		}
+	default:
+		// Numbers are kept as json.Number, so that Validate checks them exactly.
+		n.value = tok
+	}
+	return n, nil
+}
+
+type documentValidator struct {
+	opts ValidateDocumentOptions
+	st   *stacktrace.StackTrace
+}
+
+func (v *documentValidator) fail(node *documentNode, path string, err error) {
+	se := stacktrace.NewWrapped("validate value", err, v.opts.location, stacktrace.WithPosition(&node.Position),
+		stacktrace.WithType(stacktrace.TypeValidating), stacktrace.WithInfo("path", path))
+	if v.st == nil {
+		v.st = se
+	} else {
+		v.st = v.st.Append(se)
+	}
+}
+
+func (v *documentValidator) validate(s Shape, node *documentNode, path string) {
+	switch s := s.(type) {
+	case *ObjectShape:
+		if node.kind == documentObject {
+			v.validateObject(s, node, path)
+			return
+		}
+	case *ArrayShape:
+		if node.kind == documentArray {
+			v.validateArray(s, node, path)
+			return
+		}
+	case *RecursiveShape:
+		v.validate(*s.Head, node, path)
+		return
+	case *UnionShape:
+		v.validateUnion(s, node, path)
+		return
+	case *AnyShape:
+		return
+	}
+	val, err := node.decode()
+	if err != nil {
+		v.fail(node, path, err)
+		return
+	}
+	if err := s.Validate(val, path); err != nil {
+		v.fail(node, path, err)
+	}
+}
+
+// validateUnion validates the value against the union member it belongs to, so that violations
+// are reported at the positions of nested values.
+func (v *documentValidator) validateUnion(s *UnionShape, node *documentNode, path string) {
+	val, err := node.decode()
+	if err != nil {
+		v.fail(node, path, err)
+		return
+	}
+	member := unionMemberOf(s, val)
+	if member == nil {
+		// The value is invalid, the only member of the same kind is the one it is meant for.
+		member = documentMemberOfKind(s, node.kind)
+	}
+	if member == nil {
+		v.fail(node, path, s.Validate(val, path))
+		return
+	}
+	v.validate(member, node, path)
+}
+
+// documentMemberOfKind returns the only object or array member of the union for the node of that kind.
+func documentMemberOfKind(s *UnionShape, kind documentNodeKind) Shape {
+	if kind != documentObject && kind != documentArray {
+		return nil
+	}
+	var found Shape
+	for _, member := range s.AnyOf {
+		m := *member
+		if r, ok := m.(*RecursiveShape); ok {
+			m = *r.Head
+		}
+		switch m.(type) {
+		case *ObjectShape:
+			if kind != documentObject {
+				continue
+			}
+		case *ArrayShape:
+			if kind != documentArray {
+				continue
+			}
+		default:
+			continue
+		}
+		if found != nil {
+			return nil
+		}
+		found = m
+	}
+	return found
+}
+
+func (v *documentValidator) validateObject(s *ObjectShape, node *documentNode, path string) {
+	count := uint64(len(node.items))
+	if s.MinProperties != nil && count < *s.MinProperties {
+		v.fail(node, path, fmt.Errorf("object must have at least %d properties", *s.MinProperties))
+	}
+	if s.MaxProperties != nil && count > *s.MaxProperties {
+		v.fail(node, path, fmt.Errorf("object must have not more than %d properties", *s.MaxProperties))
+	}
+	restrictedAdditionalProperties := s.AdditionalProperties != nil && !*s.AdditionalProperties
+	present := make(map[string]struct{})
+	for i, keyNode := range node.keys {
+		valueNode := node.items[i]
+		k := keyNode.key()
+		ctxPath := path + "." + k
+		// Explicitly defined properties have priority over pattern properties.
+		if s.Properties != nil {
+			if p, ok := s.Properties.Get(k); ok {
+				present[k] = struct{}{}
+				v.validate(*p.Shape, valueNode, ctxPath)
+				continue
+			}
+		}
+		var patterns []Shape
+		if s.PatternProperties != nil {
+			for pair := s.PatternProperties.Oldest(); pair != nil; pair = pair.Next() {
+				matched, err := matchRegexp(pair.Value.Pattern, k)
+				if err != nil {
+					v.fail(keyNode, ctxPath, err)
+				}
+				if matched {
+					patterns = append(patterns, *pair.Value.Shape)
+				}
+			}
+		}
+		if len(patterns) > 0 {
+			// The first pattern property the value is valid against prevails,
+			// a value that is valid against none of them is an additional property as in ObjectShape.Validate.
+			val, err := valueNode.decode()
+			if err != nil {
+				v.fail(valueNode, ctxPath, err)
+				continue
+			}
+			found := false
+			for _, ps := range patterns {
+				if ps.Validate(val, ctxPath) == nil {
+					found = true
+					break
+				}
+			}
+			if found {
+				continue
+			}
+		}
+		if restrictedAdditionalProperties {
+			v.fail(keyNode, ctxPath, fmt.Errorf("unexpected additional property \"%s\"", k))
+		}
+	}
+	if s.Properties != nil {
+		for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+			if _, ok := present[pair.Key]; !ok && pair.Value.Required {
+				v.fail(node, path, fmt.Errorf("required property \"%s\" is missing", pair.Key))
+			}
+		}
+	}
+}
+
+func (v *documentValidator) validateArray(s *ArrayShape, node *documentNode, path string) {
+	arrayLen := uint64(len(node.items))
+	if s.MinItems != nil && arrayLen < *s.MinItems {
+		v.fail(node, path, fmt.Errorf("array must have at least %d items", *s.MinItems))
+	}
+	if s.MaxItems != nil && arrayLen > *s.MaxItems {
+		v.fail(node, path, fmt.Errorf("array must have not more than %d items", *s.MaxItems))
+	}
+	validateUniqueItems := s.UniqueItems != nil && *s.UniqueItems
+	uniqueItems := make(map[string]struct{})
+	for i, item := range node.items {
+		ctxPath := path + "[" + strconv.Itoa(i) + "]"
+		if s.Items != nil {
+			v.validate(*s.Items, item, ctxPath)
+		}
+		if validateUniqueItems {
+			val, err := item.decode()
+			if err != nil {
+				continue
+			}
+			key := valueKey(val)
+			if _, ok := uniqueItems[key]; ok {
+				v.fail(item, ctxPath, fmt.Errorf("array contains duplicate items"))
+			}
+			uniqueItems[key] = struct{}{}
+		}
+	}
+}
+
+// decode returns the node as a value of types that Validate accepts.
+func (n *documentNode) decode() (interface{}, error) {
+	switch n.kind {
+	case documentObject:
+		m := make(map[string]interface{}, len(n.items))
+		for i, item := range n.items {
+			val, err := item.decode()
+			if err != nil {
+				return nil, err
+			}
+			m[n.keys[i].key()] = val
+		}
+		return m, nil
+	case documentArray:
+		items := make([]interface{}, len(n.items))
+		for i, item := range n.items {
+			val, err := item.decode()
+			if err != nil {
+				return nil, err
+			}
+			items[i] = val
+		}
+		return items, nil
+	}
+	return n.value, n.err
+}
@@ -0,0 +1,216 @@
+package raml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/acronis/go-raml/stacktrace"
+)
+
+func TestValidateDocument(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0 Library
+types:
+  Config:
+    type: object
+    additionalProperties: false
+    properties:
+      name:
+        type: string
+        maxLength: 5
+      port:
+        type: integer
+        maximum: 65535
+      hosts:
+        type: array
+        items: string
+        uniqueItems: true
+      ratio?: number
+`)
+	s := libraryType(t, rml, "Config")
+	type violation struct {
+		path   string
+		line   int
+		column int
+	}
+	tests := []struct {
+		name   string
+		data   string
+		format string
+		want   []violation
+	}{
+		{
+			name:   "valid json",
+			data:   `{"name": "a\/b", "port": 80, "hosts": ["x"]}`,
+			format: DocumentFormatJSON,
+		},
+		{
+			name:   "json positions after escapes",
+			data:   "{\"name\": \"a\\/b\\u00e9\\\"\", \"port\": 70000,\n  \"hosts\": [\"x\", \"x\"], \"extra\": 1}",
+			format: DocumentFormatJSON,
+			want: []violation{
+				{path: "$.name", line: 1, column: 10},
+				{path: "$.port", line: 1, column: 34},
+				{path: "$.hosts[1]", line: 2, column: 18},
+				{path: "$.extra", line: 2, column: 24},
+			},
+		},
+		{
+			name:   "json columns in characters",
+			data:   "{\"hosts\": [\"é\"], \"name\": \"ok\", \"port\": \"80\"}",
+			format: DocumentFormatJSON,
+			want:   []violation{{path: "$.port", line: 1, column: 40}},
+		},
+		{
+			name:   "json numbers",
+			data:   `{"name": "a", "port": 1.5, "hosts": [], "ratio": 1e2}`,
+			format: DocumentFormatJSON,
+			want:   []violation{{path: "$.port", line: 1, column: 23}},
+		},
+		{
+			name:   "json required property",
+			data:   "\n  {\"name\": \"a\", \"hosts\": []}",
+			format: DocumentFormatJSON,
+			want:   []violation{{path: "$", line: 2, column: 3}},
+		},
+		{
+			name:   "yaml positions",
+			data:   "name: abcdefg\nport: 80\nhosts:\n  - x\n  - 1\n",
+			format: DocumentFormatYAML,
+			want: []violation{
+				{path: "$.name", line: 1, column: 7},
+				{path: "$.hosts[1]", line: 5, column: 5},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDocument(s, []byte(tt.data), tt.format)
+			if len(tt.want) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			st, ok := stacktrace.Unwrap(err)
+			require.True(t, ok)
+			traces := append([]*stacktrace.StackTrace{st}, st.List...)
+			got := make([]violation, len(traces))
+			for i, tr := range traces {
+				got[i] = violation{path: tr.Info.StringBy("path"), line: tr.Position.Line, column: tr.Position.Column}
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidateDocument_Errors(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0 Library
+types:
+  Config: object
+`)
+	s := libraryType(t, rml, "Config")
+	tests := []struct {
+		name   string
+		data   string
+		format string
+	}{
+		{name: "malformed json", data: `{"a": }`, format: DocumentFormatJSON},
+		{name: "trailing json", data: `{} {}`, format: DocumentFormatJSON},
+		{name: "empty yaml", data: ``, format: DocumentFormatYAML},
+		{name: "unsupported format", data: `{}`, format: "toml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDocument(s, []byte(tt.data), tt.format)
+			st, ok := stacktrace.Unwrap(err)
+			require.True(t, ok)
+			require.Equal(t, stacktrace.TypeParsing, st.Type)
+		})
+	}
+}
+
+func TestValidateDocument_Unions(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0 Library
+types:
+  D:
+    type: object
+    properties:
+      n: integer
+  Cat:
+    type: object
+    discriminator: kind
+    properties:
+      kind: string
+      lives: integer
+  Dog:
+    type: object
+    discriminator: kind
+    properties:
+      kind: string
+      name: string
+  Holder:
+    type: object
+    properties:
+      u: D | nil
+      pet?: Cat | Dog
+      even?:
+        type: integer
+        multipleOf: 2
+`)
+	s := libraryType(t, rml, "Holder")
+	type violation struct {
+		path   string
+		line   int
+		column int
+	}
+	tests := []struct {
+		name   string
+		data   string
+		format string
+		want   []violation
+	}{
+		{name: "valid member", data: "u:\n  n: 1\n", format: DocumentFormatYAML},
+		{name: "nil member", data: "u: null\n", format: DocumentFormatYAML},
+		{
+			name:   "nested violation of the only object member",
+			data:   "# holder\nu:\n  # value\n  n: x\n",
+			format: DocumentFormatYAML,
+			want:   []violation{{path: "$.u.n", line: 4, column: 6}},
+		},
+		{
+			name:   "scalar matches no member",
+			data:   "u: 1\n",
+			format: DocumentFormatYAML,
+			want:   []violation{{path: "$.u", line: 1, column: 4}},
+		},
+		{
+			name:   "member by discriminator",
+			data:   `{"u": null, "pet": {"kind": "Dog", "name": 1}}`,
+			format: DocumentFormatJSON,
+			want:   []violation{{path: "$.pet.name", line: 1, column: 44}},
+		},
+		{
+			name:   "big odd integer",
+			data:   `{"u": null, "even": 18446744073709551617}`,
+			format: DocumentFormatJSON,
+			want:   []violation{{path: "$.even", line: 1, column: 21}},
+		},
+		{name: "integer with exponent", data: `{"u": null, "even": 1e400}`, format: DocumentFormatJSON},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDocument(s, []byte(tt.data), tt.format)
+			if len(tt.want) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			st, ok := stacktrace.Unwrap(err)
+			require.True(t, ok)
+			traces := append([]*stacktrace.StackTrace{st}, st.List...)
+			got := make([]violation, len(traces))
+			for i, tr := range traces {
+				got[i] = violation{path: tr.Info.StringBy("path"), line: tr.Position.Line, column: tr.Position.Column}
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
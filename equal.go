@@ -0,0 +1,186 @@
+package raml
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// valuesEqual reports whether the data values are deeply equal.
+// Numbers are equal if they have the same value regardless of their Go types, so 1, uint(1), 1.0 and
+// json.Number("1e0") are equal. Objects are equal if they have equal values of the same keys
+// and arrays are equal if they have equal items in the same order.
+func valuesEqual(a interface{}, b interface{}) bool {
+	return valueKey(a) == valueKey(b)
+}
+
+// valueKey returns the canonical key of the data value. Values have equal keys if and only if they are equal
+// by valuesEqual, so the key may be used to hash values of any type, including maps and slices.
+// Numbers are keyed without expanding their exponents, so keys are not much longer than the input.
+func valueKey(v interface{}) string {
+	var b strings.Builder
+	writeValueKey(&b, v)
+	return b.String()
+}
+
+func writeValueKey(b *strings.Builder, v interface{}) {
+	if n, ok := v.(json.Number); ok {
+		if key, ok := decimalKey(string(n)); ok {
+			b.WriteString(key)
+			return
+		}
+		if checkNumberExponent(string(n)) != nil {
+			// Numbers with exponents beyond the limit are keyed by their text instead of their value.
+			b.WriteString("n!")
+			b.WriteString(string(n))
+			return
+		}
+	}
+	if r, err := numericValue(v); err == nil {
+		b.WriteString(ratKey(r))
+		return
+	}
+	switch v := v.(type) {
+	case nil:
+		b.WriteString("null")
+		return
+	case bool:
+		b.WriteString(strconv.FormatBool(v))
+		return
+	case string:
+		b.WriteString(strconv.Quote(v))
+		return
+	case []byte:
+		b.WriteString("b:")
+		b.WriteString(base64.StdEncoding.EncodeToString(v))
+		return
+	case time.Time:
+		b.WriteString("t:")
+		b.WriteString(v.UTC().Format(time.RFC3339Nano))
+		return
+	case []interface{}:
+		b.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeValueKey(b, item)
+		}
+		b.WriteByte(']')
+		return
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(strconv.Quote(k))
+			b.WriteByte(':')
+			writeValueKey(b, v[k])
+		}
+		b.WriteByte('}')
+		return
+	}
+	// Typed slices and maps are compared like their generic counterparts.
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		items := make([]interface{}, rv.Len())
+		for i := range items {
+			items[i] = rv.Index(i).Interface()
+		}
+		writeValueKey(b, items)
+		return
+	case reflect.Map:
+		m := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			m[fmt.Sprint(iter.Key().Interface())] = iter.Value().Interface()
+		}
+		writeValueKey(b, m)
+		return
+	}
+	fmt.Fprintf(b, "%#v", v)
+}
+
+// decimalKey returns the key of the decimal number literal as its significant digits and the exponent
+// of the last digit, so that the key is not longer than the literal whatever its exponent is.
+// It reports false if the literal is invalid or its exponent exceeds maxNumberExponent.
+func decimalKey(n string) (string, bool) {
+	if checkNumberExponent(n) != nil {
+		return "", false
+	}
+	mantissa, exp := n, "0"
+	if i := strings.IndexAny(n, "eE"); i >= 0 {
+		mantissa, exp = n[:i], n[i+1:]
+	}
+	e, err := strconv.Atoi(exp)
+	if err != nil {
+		return "", false
+	}
+	sign := ""
+	if rest, ok := strings.CutPrefix(mantissa, "-"); ok {
+		sign, mantissa = "-", rest
+	}
+	intPart, frac, _ := strings.Cut(mantissa, ".")
+	digits := intPart + frac
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return "", false
+	}
+	digits = strings.TrimLeft(digits, "0")
+	if digits == "" {
+		return "n:0", true
+	}
+	significant := strings.TrimRight(digits, "0")
+	e += len(digits) - len(significant) - len(frac)
+	return "n:" + sign + significant + "e" + strconv.Itoa(e), true
+}
+
+// ratKey returns the key of the number. Numbers with terminating decimal expansion are keyed
+// as by decimalKey, others by their fraction.
+func ratKey(r *big.Rat) string {
+	if r.IsInt() {
+		key, _ := decimalKey(r.Num().String())
+		return key
+	}
+	den := new(big.Int).Set(r.Denom())
+	twos := den.TrailingZeroBits()
+	den.Rsh(den, twos)
+	fives := uint(0)
+	five := big.NewInt(5)
+	q, m := new(big.Int), new(big.Int)
+	for {
+		q.DivMod(den, five, m)
+		if m.Sign() != 0 {
+			break
+		}
+		den.Set(q)
+		fives++
+	}
+	if den.Cmp(big.NewInt(1)) != 0 {
+		return "n:" + r.RatString()
+	}
+	key, _ := decimalKey(r.FloatString(int(max(twos, fives))))
+	return key
+}
+
+// enumContains reports whether the enum contains a value equal to v.
+func enumContains(enum Nodes, v interface{}) bool {
+	for _, e := range enum {
+		if valuesEqual(e.Value, v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package raml
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_valuesEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a    interface{}
+		b    interface{}
+		want bool
+	}{
+		{name: "int and uint", a: 1, b: uint(1), want: true},
+		{name: "int and float", a: 1, b: 1.0, want: true},
+		{name: "int and json number with exponent", a: 1000, b: json.Number("1e3"), want: true},
+		{name: "json numbers with fraction and exponent", a: json.Number("1.50e2"), b: json.Number("150.0"), want: true},
+		{name: "float and json number", a: 0.1, b: json.Number("1e-1"), want: true},
+		{name: "negative json numbers", a: json.Number("-0.25"), b: -0.25, want: true},
+		{name: "zeros", a: json.Number("-0.0e5"), b: 0, want: true},
+		{name: "big int and json number", a: big.NewInt(1200), b: json.Number("12e2"), want: true},
+		{name: "big rat and float", a: big.NewRat(1, 8), b: 0.125, want: true},
+		{name: "non-terminating fractions", a: big.NewRat(1, 3), b: big.NewRat(2, 6), want: true},
+		{name: "non-terminating fraction and decimal", a: big.NewRat(1, 3), b: json.Number("0.3333333333"), want: false},
+		{name: "different numbers", a: json.Number("1e3"), b: json.Number("1e4"), want: false},
+		{name: "huge exponents", a: json.Number("1e9999999"), b: json.Number("1e9999999"), want: true},
+		{name: "huge exponent and number", a: json.Number("1e9999999"), b: 1, want: false},
+		{name: "huge exponent and string", a: json.Number("1e9999999"), b: "1e9999999", want: false},
+		{name: "number and string", a: 1, b: "1", want: false},
+		{name: "strings", a: "a", b: "a", want: true},
+		{name: "bytes", a: []byte("a"), b: []byte("a"), want: true},
+		{
+			name: "times in different zones",
+			a:    time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC),
+			b:    time.Date(2024, 1, 2, 5, 0, 0, 0, time.FixedZone("", 2*60*60)),
+			want: true,
+		},
+		{name: "nil and false", a: nil, b: false, want: false},
+		{name: "arrays", a: []interface{}{1, "a"}, b: []interface{}{json.Number("1"), "a"}, want: true},
+		{name: "array order", a: []interface{}{1, 2}, b: []interface{}{2, 1}, want: false},
+		{name: "typed slice", a: []int{1, 2}, b: []interface{}{1.0, 2.0}, want: true},
+		{
+			name: "objects in any key order",
+			a:    map[string]interface{}{"a": 1, "b": []interface{}{true}},
+			b:    map[string]interface{}{"b": []interface{}{true}, "a": json.Number("1.0")},
+			want: true,
+		},
+		{name: "typed map", a: map[string]int{"a": 1}, b: map[string]interface{}{"a": 1}, want: true},
+		{name: "objects with different keys", a: map[string]interface{}{"a": 1}, b: map[string]interface{}{"b": 1}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, valuesEqual(tt.a, tt.b))
+			require.Equal(t, tt.want, valuesEqual(tt.b, tt.a))
+		})
+	}
+}
+
+func Test_valueKey_Length(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+	}{
+		{name: "exponent at limit", value: json.Number("1e10000")},
+		{name: "negative exponent at limit", value: json.Number("1.5e-10000")},
+		{name: "exponent above limit", value: json.Number("1e9999999")},
+		{name: "array of large numbers", value: []interface{}{json.Number("9e9999"), json.Number("-9e-9999")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.value)
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(valueKey(tt.value)), 2*len(b)+8)
+		})
+	}
+}
+
+func Test_valueKey_HugeExponent(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{value: json.Number("1e9999999"), want: "n!1e9999999"},
+		{value: json.Number("-2.5E-10001"), want: "n!-2.5E-10001"},
+		{value: []interface{}{json.Number("1e10001")}, want: "[n!1e10001]"},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, valueKey(tt.value))
+	}
+}
+
+func Test_decimalKey(t *testing.T) {
+	tests := []struct {
+		n    string
+		want string
+		ok   bool
+	}{
+		{n: "0", want: "n:0", ok: true},
+		{n: "-0.000", want: "n:0", ok: true},
+		{n: "120", want: "n:12e1", ok: true},
+		{n: "0.0120", want: "n:12e-3", ok: true},
+		{n: "-1.5E+3", want: "n:-15e2", ok: true},
+		{n: "1e-10000", want: "n:1e-10000", ok: true},
+		{n: "1e10001"},
+		{n: "1x"},
+		{n: "."},
+		{n: "1e"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.n, func(t *testing.T) {
+			got, ok := decimalKey(tt.n)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
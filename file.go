@@ -0,0 +1,136 @@
+package raml
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// fileSniffLen is the number of bytes that are considered to detect the media type of file content.
+const fileSniffLen = 512
+
+// fileSignatures are magic numbers of media types that http.DetectContentType does not recognize.
+var fileSignatures = []struct {
+	prefix    string
+	mediaType string
+}{
+	{"II*\x00", "image/tiff"},
+	{"MM\x00*", "image/tiff"},
+	{"BZh", "application/x-bzip2"},
+	{"\xfd7zXZ\x00", "application/x-xz"},
+	{"\x28\xb5\x2f\xfd", "application/zstd"},
+}
+
+// fileContent returns the content of a file value given as []byte, base64 encoded string or io.Reader.
+// At most limit bytes are read from readers, a negative limit means no limit.
+// Readers that implement io.Seeker are read from the current position and rewound to it,
+// other readers that implement io.ReaderAt are read from the start without being consumed.
+// Other readers, such as network streams, are consumed by the bytes read, so callers that need
+// the content afterwards must buffer it themselves.
+func fileContent(v interface{}, limit int64) ([]byte, error) {
+	switch v := v.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		data, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 content: %w", err)
+		}
+		return data, nil
+	case io.Reader:
+		r := v
+		if s, ok := v.(io.Seeker); ok {
+			pos, err := s.Seek(0, io.SeekCurrent)
+			if err == nil {
+				defer s.Seek(pos, io.SeekStart) //nolint:errcheck
+			}
+		} else if ra, ok := v.(io.ReaderAt); ok {
+			r = io.NewSectionReader(ra, 0, math.MaxInt64)
+		}
+		if limit >= 0 {
+			r = io.LimitReader(r, limit)
+		}
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("read content: %w", err)
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf("invalid type, got %T, expected []byte, io.Reader or base64 string", v)
+}
+
+// detectFileType returns the media type of the content by its magic numbers as http.DetectContentType does,
+// without parameters.
+func detectFileType(data []byte) string {
+	for _, sig := range fileSignatures {
+		if bytes.HasPrefix(data, []byte(sig.prefix)) {
+			return sig.mediaType
+		}
+	}
+	mediaType, _, _ := strings.Cut(http.DetectContentType(data), ";")
+	return mediaType
+}
+
+// matchFileType reports whether the detected media type satisfies the declared media type.
+// Declared types may be wildcards such as "image/*" or "*/*". Detected plain text and XML satisfy
+// textual types that cannot be told apart by content, such as application/json or types with +json suffix.
+// Content of unrecognized type satisfies declared types that have no known magic numbers.
+func matchFileType(declared string, detected string) bool {
+	if mt, _, err := mime.ParseMediaType(declared); err == nil {
+		declared = mt
+	}
+	if declared == "*/*" || declared == detected || declared == "application/octet-stream" {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(declared, "/*"); ok {
+		return strings.HasPrefix(detected, prefix+"/")
+	}
+	switch detected {
+	case "application/octet-stream":
+		return !isSniffableFileType(declared)
+	case "text/plain":
+		return isTextualFileType(declared)
+	case "text/xml":
+		return declared == "application/xml" || strings.HasSuffix(declared, "+xml")
+	}
+	return false
+}
+
+// isTextualFileType reports whether the media type denotes text content.
+func isTextualFileType(mediaType string) bool {
+	if strings.HasPrefix(mediaType, "text/") {
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/xml", "application/yaml", "application/x-yaml",
+		"application/javascript", "application/x-www-form-urlencoded", "application/raml+yaml":
+		return true
+	}
+	return strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml") ||
+		strings.HasSuffix(mediaType, "+yaml")
+}
+
+// isSniffableFileType reports whether content of the media type is recognized by its magic numbers.
+func isSniffableFileType(mediaType string) bool {
+	top, _, _ := strings.Cut(mediaType, "/")
+	switch top {
+	case "image", "audio", "video", "font":
+		return true
+	}
+	for _, sig := range fileSignatures {
+		if sig.mediaType == mediaType {
+			return true
+		}
+	}
+	switch mediaType {
+	case "application/pdf", "application/postscript", "application/zip", "application/x-gzip", "application/gzip",
+		"application/x-rar-compressed", "application/wasm", "application/ogg", "application/vnd.ms-fontobject":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package raml
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// readAtOnly hides all methods of the underlying reader but ReadAt and Read.
+type readAtOnly struct {
+	r *bytes.Reader
+}
+
+func (r *readAtOnly) Read(p []byte) (int, error)              { return r.r.Read(p) }
+func (r *readAtOnly) ReadAt(p []byte, off int64) (int, error) { return r.r.ReadAt(p, off) }
+
+// countingReader counts the bytes read from the underlying reader.
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += n
+	return n, err
+}
+
+func TestFileShape_Validate(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0 Library
+types:
+  Any: file
+  Sized:
+    type: file
+    minLength: 2
+    maxLength: 4
+  Image:
+    type: file
+    fileTypes: [image/*]
+  Document:
+    type: file
+    fileTypes: [application/json, application/pdf]
+`)
+	png := "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+	tests := []struct {
+		name    string
+		typ     string
+		value   interface{}
+		wantErr string
+	}{
+		{name: "bytes", typ: "Any", value: []byte{0, 1}},
+		{name: "reader", typ: "Any", value: strings.NewReader("content")},
+		{name: "plain string", typ: "Any", value: "plain text example", wantErr: "invalid base64 content"},
+		{name: "plain string of base64 alphabet", typ: "Sized", value: "abcd"},
+		{name: "base64 string", typ: "Sized", value: base64.StdEncoding.EncodeToString([]byte("abc"))},
+		{name: "base64 string too long", typ: "Sized", value: "YWJjZGU=", wantErr: "length must be less than 4"},
+		{name: "too short", typ: "Sized", value: []byte("a"), wantErr: "length must be greater than 2"},
+		{name: "too long", typ: "Sized", value: strings.NewReader("abcde"), wantErr: "length must be less than 4"},
+		{name: "detected image", typ: "Image", value: []byte(png)},
+		{name: "not an image", typ: "Image", value: base64.StdEncoding.EncodeToString([]byte("plain text")), wantErr: "content type text/plain must be one of"},
+		{name: "textual type", typ: "Document", value: []byte(`{"a": 1}`)},
+		{name: "pdf", typ: "Document", value: []byte("%PDF-1.7")},
+		{name: "image is not a document", typ: "Document", value: []byte(png), wantErr: "content type image/png"},
+		{name: "invalid type", typ: "Any", value: 1, wantErr: "invalid type, got int"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := libraryType(t, rml, tt.typ).Validate(tt.value, "$")
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestFileShape_Validate_Readers(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0 Library
+types:
+  Min:
+    type: file
+    minLength: 1000
+  Max:
+    type: file
+    maxLength: 600
+  Any: file
+`)
+	content := strings.Repeat("a", 10000)
+	tests := []struct {
+		name     string
+		typ      string
+		wantRead int
+	}{
+		{name: "min length is read", typ: "Min", wantRead: 1000},
+		{name: "one byte over max length is read", typ: "Max", wantRead: 601},
+		{name: "sniff length is read", typ: "Any", wantRead: fileSniffLen},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := libraryType(t, rml, tt.typ)
+
+			r := &countingReader{r: strings.NewReader(content)}
+			err := s.Validate(r, "$")
+			if tt.typ == "Max" {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantRead, r.n)
+
+			seeker := strings.NewReader(content)
+			_, err = seeker.Seek(5, io.SeekStart)
+			require.NoError(t, err)
+			_ = s.Validate(seeker, "$")
+			pos, err := seeker.Seek(0, io.SeekCurrent)
+			require.NoError(t, err)
+			require.Equal(t, int64(5), pos)
+
+			ra := &readAtOnly{r: bytes.NewReader([]byte(content))}
+			_ = s.Validate(ra, "$")
+			rest, err := io.ReadAll(ra)
+			require.NoError(t, err)
+			require.Len(t, rest, len(content))
+		})
+	}
+}
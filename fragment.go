@@ -1,6 +1,7 @@
 package raml
 
 import (
+	"bytes"
 	"path/filepath"
 
 	orderedmap "github.com/wk8/go-ordered-map/v2"
@@ -16,6 +17,7 @@ const (
 	FragmentLibrary
 	FragmentDataType
 	FragmentNamedExample
+	FragmentAPI
 )
 
 type LocationGetter interface {
@@ -205,6 +207,11 @@ func (r *RAML) MakeDataType(path string) *DataType {
 }
 
 func (r *RAML) MakeJsonDataType(value []byte, path string) (*DataType, error) {
+	return r.makeSchemaDataType(value, path)
+}
+
+// makeSchemaDataType creates a data type fragment whose type is the external schema.
+func (r *RAML) makeSchemaDataType(value []byte, path string) (*DataType, error) {
 	dt := r.MakeDataType(path)
 	// Convert to yaml node to reuse the same data node creation interface
 	node := &yaml.Node{
@@ -228,6 +235,15 @@ func (r *RAML) MakeJsonDataType(value []byte, path string) (*DataType, error) {
 	return dt, nil
 }
 
+// MakeXSDDataType creates a data type fragment from the XML Schema document.
+func (r *RAML) MakeXSDDataType(value []byte, path string) (*DataType, error) {
+	value = bytes.TrimSpace(value)
+	if len(value) == 0 || value[0] != '<' {
+		return nil, stacktrace.New("xml schema must start with '<'", path)
+	}
+	return r.makeSchemaDataType(value, path)
+}
+
 // NamedExample is the RAML 1.0 NamedExample
 type NamedExample struct {
 	Id  string
@@ -0,0 +1,757 @@
+package raml
+
+import (
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+
+	"github.com/acronis/go-raml/stacktrace"
+)
+
+// goInitialisms are name parts that are written in upper case in Go identifiers.
+var goInitialisms = map[string]struct{}{
+	"ID": {}, "URL": {}, "URI": {}, "HTTP": {}, "JSON": {}, "API": {}, "UUID": {}, "XML": {}, "IP": {},
+}
+
+// goTimeWrappers are generated wrappers of time.Time for RAML date types.
+// Values of the wrappers are formatted by the layout, after conversion to UTC if utc is set.
+var goTimeWrappers = map[string]struct {
+	layout string
+	utc    bool
+	doc    string
+}{
+	"DateTime":        {"time.RFC3339Nano", false, "is a RAML datetime in RFC3339 format."},
+	"DateTimeRFC2616": {`"Mon, 02 Jan 2006 15:04:05 GMT"`, true, "is a RAML datetime in RFC2616 format."},
+	"DateTimeOnly":    {`"2006-01-02T15:04:05.999999999"`, false, "is a RAML datetime-only."},
+	"DateOnly":        {"time.DateOnly", false, "is a RAML date-only."},
+	"TimeOnly":        {`"15:04:05.999999999"`, false, "is a RAML time-only."},
+}
+
+// goHelperStrict is the generated function that unmarshals union members rejecting unknown fields.
+const goHelperStrict = "unmarshalStrict"
+
+type GoGeneratorOpt interface {
+	Apply(*GoGeneratorOptions)
+}
+
+type optGoPackage struct {
+	name string
+}
+
+func (o optGoPackage) Apply(e *GoGeneratorOptions) {
+	e.packageName = o.name
+}
+
+// WithGoPackage sets the package name of the generated file.
+func WithGoPackage(name string) GoGeneratorOpt {
+	return optGoPackage{name: name}
+}
+
+type GoGeneratorOptions struct {
+	packageName string
+}
+
+// GoGenerator generates Go types from unwrapped shapes.
+// Visit methods return Go type expressions and declare named types when necessary.
+type GoGenerator struct {
+	opts GoGeneratorOptions
+
+	decls   *orderedmap.OrderedMap[string, string]
+	names   map[string]string
+	aliases map[string]string
+	imports map[string]struct{}
+	// helpers maps generated helpers to their names that are unique among declared types.
+	helpers map[string]string
+	// unionMembers holds marker methods of union members to avoid duplicates.
+	unionMembers map[string]struct{}
+	// nameHint is the name of the next anonymous type that requires declaration.
+	nameHint string
+}
+
+var _ ShapeVisitor[string] = (*GoGenerator)(nil)
+
+func NewGoGenerator(opts ...GoGeneratorOpt) *GoGenerator {
+	g := &GoGenerator{
+		opts: GoGeneratorOptions{packageName: "types"},
+	}
+	for _, opt := range opts {
+		opt.Apply(&g.opts)
+	}
+	return g
+}
+
+// GenerateLibrary generates a Go source file with types of the library and the libraries it uses.
+// Types of used libraries are prefixed with the "uses" alias, e.g. "CommonType".
+func (g *GoGenerator) GenerateLibrary(lib *Library) ([]byte, error) {
+	g.decls = orderedmap.New[string, string]()
+	g.names = make(map[string]string)
+	g.aliases = make(map[string]string)
+	g.imports = make(map[string]struct{})
+	g.helpers = make(map[string]string)
+	g.unionMembers = make(map[string]struct{})
+
+	types := orderedmap.New[string, Shape]()
+	if err := g.collectLibrary(lib, "", types, make(map[string]struct{})); err != nil {
+		return nil, err
+	}
+	// Names are registered first so that types can reference each other regardless of the order.
+	for pair := types.Oldest(); pair != nil; pair = pair.Next() {
+		id := pair.Value.Base().Id
+		if target, ok := g.names[id]; ok {
+			g.aliases[pair.Key] = target
+			continue
+		}
+		g.names[id] = pair.Key
+		g.decls.Set(pair.Key, "")
+	}
+	for pair := types.Oldest(); pair != nil; pair = pair.Next() {
+		if target, ok := g.aliases[pair.Key]; ok {
+			g.decls.Set(pair.Key, fmt.Sprintf("type %s = %s\n", pair.Key, target))
+			continue
+		}
+		g.declare(pair.Value, pair.Key)
+	}
+	return g.render()
+}
+
+func (g *GoGenerator) collectLibrary(lib *Library, prefix string, types *orderedmap.OrderedMap[string, Shape], visited map[string]struct{}) error {
+	if _, ok := visited[lib.Location]; ok {
+		return nil
+	}
+	visited[lib.Location] = struct{}{}
+	for pair := lib.Types.Oldest(); pair != nil; pair = pair.Next() {
+		s := *pair.Value
+		if !s.Base().IsUnwrapped() {
+			us, err := lib.raml.UnwrapShape(pair.Value, make([]Shape, 0))
+			if err != nil {
+				return stacktrace.NewWrapped("unwrap shape", err, lib.Location, stacktrace.WithPosition(&s.Base().Position),
+					stacktrace.WithType(stacktrace.TypeConverting))
+			}
+			s = us
+		}
+		types.Set(g.uniqueName(prefix+goIdentifier(pair.Key)), s)
+	}
+	for pair := lib.Uses.Oldest(); pair != nil; pair = pair.Next() {
+		if pair.Value.Link == nil {
+			continue
+		}
+		if err := g.collectLibrary(pair.Value.Link, prefix+goIdentifier(pair.Key), types, visited); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// declare declares a named type for the shape.
+func (g *GoGenerator) declare(s Shape, name string) {
+	g.nameHint = name
+	expr := g.visit(s)
+	if expr != name {
+		decl := fmt.Sprintf("type %s = %s\n", name, expr)
+		g.decls.Set(name, g.docComment(s.Base())+decl)
+	}
+}
+
+func (g *GoGenerator) render() ([]byte, error) {
+	var b strings.Builder
+	b.WriteString("// Code generated by go-raml. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", g.opts.packageName)
+	if len(g.imports) > 0 {
+		imports := make([]string, 0, len(g.imports))
+		for k := range g.imports {
+			imports = append(imports, strconv.Quote(k))
+		}
+		sort.Strings(imports)
+		fmt.Fprintf(&b, "import (\n%s\n)\n\n", strings.Join(imports, "\n"))
+	}
+	for pair := g.decls.Oldest(); pair != nil; pair = pair.Next() {
+		b.WriteString(pair.Value)
+		b.WriteString("\n")
+	}
+	helpers := make([]string, 0, len(g.helpers))
+	for k := range g.helpers {
+		helpers = append(helpers, k)
+	}
+	sort.Strings(helpers)
+	for _, h := range helpers {
+		b.WriteString(g.renderHelper(h, g.helpers[h]))
+		b.WriteString("\n")
+	}
+	src, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return nil, fmt.Errorf("format source: %w", err)
+	}
+	return src, nil
+}
+
+func (g *GoGenerator) renderHelper(helper string, name string) string {
+	if w, ok := goTimeWrappers[helper]; ok {
+		value := "t.Time"
+		if w.utc {
+			value = "t.UTC()"
+		}
+		return fmt.Sprintf(`// %[1]s %[3]s
+type %[1]s struct {
+	time.Time
+}
+
+func (t %[1]s) MarshalJSON() ([]byte, error) {
+	return json.Marshal(%[4]s.Format(%[2]s))
+}
+
+func (t *%[1]s) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	v, err := time.Parse(%[2]s, s)
+	if err != nil {
+		return err
+	}
+	t.Time = v
+	return nil
+}
+`, name, w.layout, w.doc, value)
+	}
+	return fmt.Sprintf(`// %[1]s unmarshals data rejecting unknown fields.
+func %[1]s(data []byte, v any) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.DisallowUnknownFields()
+	return d.Decode(v)
+}
+`, name)
+}
+
+// useHelper returns the name of the generated helper, which is reserved on first use.
+func (g *GoGenerator) useHelper(helper string) string {
+	if name, ok := g.helpers[helper]; ok {
+		return name
+	}
+	name := g.uniqueName(helper)
+	g.helpers[helper] = name
+	return name
+}
+
+// isTimeWrapper reports whether the type is a generated wrapper of time.Time.
+func (g *GoGenerator) isTimeWrapper(typ string) bool {
+	for helper, name := range g.helpers {
+		if _, ok := goTimeWrappers[helper]; ok && name == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// Visit returns the Go type expression of the shape.
+func (g *GoGenerator) Visit(s Shape) string {
+	if name, ok := g.names[s.Base().Id]; ok {
+		return name
+	}
+	return g.visit(s)
+}
+
+func (g *GoGenerator) visit(s Shape) string {
+	switch s := s.(type) {
+	case *ObjectShape:
+		return g.VisitObjectShape(s)
+	case *ArrayShape:
+		return g.VisitArrayShape(s)
+	case *StringShape:
+		return g.VisitStringShape(s)
+	case *NumberShape:
+		return g.VisitNumberShape(s)
+	case *IntegerShape:
+		return g.VisitIntegerShape(s)
+	case *BooleanShape:
+		return g.VisitBooleanShape(s)
+	case *FileShape:
+		return g.VisitFileShape(s)
+	case *UnionShape:
+		return g.VisitUnionShape(s)
+	case *DateTimeShape:
+		return g.VisitDateTimeShape(s)
+	case *DateTimeOnlyShape:
+		return g.VisitDateTimeOnlyShape(s)
+	case *DateOnlyShape:
+		return g.VisitDateOnlyShape(s)
+	case *TimeOnlyShape:
+		return g.VisitTimeOnlyShape(s)
+	case *RecursiveShape:
+		return g.VisitRecursiveShape(s)
+	case *JSONShape:
+		return g.VisitJSONShape(s)
+	case *XMLShape:
+		return g.VisitXMLShape(s)
+	case *AnyShape:
+		return g.VisitAnyShape(s)
+	case *NilShape:
+		return g.VisitNilShape(s)
+	default:
+		return "any"
+	}
+}
+
+// takeName returns the name for the declared shape and registers it.
+func (g *GoGenerator) takeName(s Shape) string {
+	if name, ok := g.names[s.Base().Id]; ok {
+		return name
+	}
+	name := g.uniqueName(g.nameHint)
+	g.names[s.Base().Id] = name
+	g.decls.Set(name, "")
+	return name
+}
+
+func (g *GoGenerator) uniqueName(name string) string {
+	if name == "" {
+		name = "Type"
+	}
+	candidate := name
+	for n := 1; ; n++ {
+		_, declared := g.decls.Get(candidate)
+		_, aliased := g.aliases[candidate]
+		if !declared && !aliased && !g.isHelperName(candidate) {
+			return candidate
+		}
+		candidate = name + strconv.Itoa(n)
+	}
+}
+
+func (g *GoGenerator) isHelperName(name string) bool {
+	for _, helperName := range g.helpers {
+		if helperName == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *GoGenerator) VisitObjectShape(s *ObjectShape) string {
+	hasProperties := s.Properties != nil && s.Properties.Len() > 0
+	var patternType string
+	if s.PatternProperties != nil && s.PatternProperties.Len() > 0 {
+		hint := g.nameHint
+		if s.PatternProperties.Len() == 1 {
+			g.nameHint = hint + "Value"
+			patternType = g.Visit(*s.PatternProperties.Oldest().Value.Shape)
+		} else {
+			patternType = "any"
+		}
+		g.nameHint = hint
+		if !hasProperties {
+			return "map[string]" + patternType
+		}
+	}
+	if !hasProperties {
+		return "map[string]any"
+	}
+
+	name := g.takeName(s)
+	g.imports["encoding/json"] = struct{}{}
+	var b strings.Builder
+	b.WriteString(g.docComment(s.Base()))
+	fmt.Fprintf(&b, "type %s struct {\n", name)
+	fields := make(map[string]struct{})
+	var propNames []string
+	for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+		prop := pair.Value
+		propNames = append(propNames, strconv.Quote(prop.Name))
+		g.nameHint = name + goIdentifier(prop.Name)
+		typ := g.Visit(*prop.Shape)
+		if _, ok := (*prop.Shape).(*RecursiveShape); ok && !strings.HasPrefix(typ, "*") {
+			typ = "*" + typ
+		}
+		tag := prop.Name
+		if !prop.Required {
+			tag += ",omitempty"
+			if isGoPointerable(typ) {
+				typ = "*" + typ
+			}
+		}
+		field := goIdentifier(prop.Name)
+		for n := 1; ; n++ {
+			if _, ok := fields[field]; !ok {
+				break
+			}
+			field = goIdentifier(prop.Name) + strconv.Itoa(n)
+		}
+		fields[field] = struct{}{}
+		if d := (*prop.Shape).Base().Description; d != nil {
+			b.WriteString(goComment(*d, "\t"))
+		}
+		fmt.Fprintf(&b, "\t%s %s `json:%q`\n", field, typ, tag)
+	}
+	if patternType != "" {
+		fmt.Fprintf(&b, "\tAdditionalProperties map[string]%s `json:\"-\"`\n", patternType)
+	}
+	b.WriteString("}\n")
+	if patternType != "" {
+		g.imports["fmt"] = struct{}{}
+		fmt.Fprintf(&b, `
+func (o *%[1]s) UnmarshalJSON(data []byte) error {
+	type alias %[1]s
+	if err := json.Unmarshal(data, (*alias)(o)); err != nil {
+		return err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	for _, k := range []string{%[2]s} {
+		delete(raw, k)
+	}
+	if len(raw) == 0 {
+		return nil
+	}
+	o.AdditionalProperties = make(map[string]%[3]s, len(raw))
+	for k, v := range raw {
+		var item %[3]s
+		if err := json.Unmarshal(v, &item); err != nil {
+			return fmt.Errorf("property %%s: %%w", k, err)
+		}
+		o.AdditionalProperties[k] = item
+	}
+	return nil
+}
+
+func (o %[1]s) MarshalJSON() ([]byte, error) {
+	type alias %[1]s
+	data, err := json.Marshal(alias(o))
+	if err != nil || len(o.AdditionalProperties) == 0 {
+		return data, err
+	}
+	m := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	for k, v := range o.AdditionalProperties {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("property %%s: %%w", k, err)
+		}
+		m[k] = b
+	}
+	return json.Marshal(m)
+}
+`, name, strings.Join(propNames, ", "), patternType)
+	}
+	g.decls.Set(name, b.String())
+	return name
+}
+
+func (g *GoGenerator) VisitArrayShape(s *ArrayShape) string {
+	if s.Items == nil {
+		return "[]any"
+	}
+	g.nameHint += "Item"
+	return "[]" + g.Visit(*s.Items)
+}
+
+func (g *GoGenerator) VisitStringShape(s *StringShape) string {
+	if s.Enum == nil {
+		return "string"
+	}
+	return g.declareEnum(s, "string", s.Enum)
+}
+
+func (g *GoGenerator) VisitIntegerShape(s *IntegerShape) string {
+	typ := "int64"
+	if s.Format != nil {
+		switch *s.Format {
+		case "int8":
+			typ = "int8"
+		case "int16":
+			typ = "int16"
+		case "int", "int32":
+			typ = "int32"
+		}
+	}
+	if s.Enum == nil {
+		return typ
+	}
+	return g.declareEnum(s, typ, s.Enum)
+}
+
+func (g *GoGenerator) VisitNumberShape(s *NumberShape) string {
+	if s.Format != nil && *s.Format == "float" {
+		return "float32"
+	}
+	return "float64"
+}
+
+func (g *GoGenerator) VisitBooleanShape(s *BooleanShape) string {
+	return "bool"
+}
+
+func (g *GoGenerator) VisitFileShape(s *FileShape) string {
+	return "[]byte"
+}
+
+func (g *GoGenerator) VisitDateTimeShape(s *DateTimeShape) string {
+	if s.Format != nil && *s.Format == "rfc2616" {
+		return g.useTimeWrapper("DateTimeRFC2616")
+	}
+	return g.useTimeWrapper("DateTime")
+}
+
+func (g *GoGenerator) VisitDateTimeOnlyShape(s *DateTimeOnlyShape) string {
+	return g.useTimeWrapper("DateTimeOnly")
+}
+
+func (g *GoGenerator) VisitDateOnlyShape(s *DateOnlyShape) string {
+	return g.useTimeWrapper("DateOnly")
+}
+
+func (g *GoGenerator) VisitTimeOnlyShape(s *TimeOnlyShape) string {
+	return g.useTimeWrapper("TimeOnly")
+}
+
+func (g *GoGenerator) useTimeWrapper(helper string) string {
+	g.imports["encoding/json"] = struct{}{}
+	g.imports["time"] = struct{}{}
+	return g.useHelper(helper)
+}
+
+func (g *GoGenerator) VisitRecursiveShape(s *RecursiveShape) string {
+	if name, ok := g.names[(*s.Head).Base().Id]; ok {
+		return name
+	}
+	return "any"
+}
+
+func (g *GoGenerator) VisitJSONShape(s *JSONShape) string {
+	g.imports["encoding/json"] = struct{}{}
+	return "json.RawMessage"
+}
+
+func (g *GoGenerator) VisitXMLShape(s *XMLShape) string {
+	return "string"
+}
+
+func (g *GoGenerator) VisitAnyShape(s *AnyShape) string {
+	return "any"
+}
+
+func (g *GoGenerator) VisitNilShape(s *NilShape) string {
+	return "*struct{}"
+}
+
+// VisitUnionShape declares a sum type: a struct holding a value of a sealed interface
+// that is implemented by all union members. Unions with nil are represented by pointers.
+func (g *GoGenerator) VisitUnionShape(s *UnionShape) string {
+	var members []Shape
+	nullable := false
+	for _, item := range s.AnyOf {
+		if _, ok := (*item).(*NilShape); ok {
+			nullable = true
+			continue
+		}
+		members = append(members, *item)
+	}
+	if len(members) == 0 {
+		return "*struct{}"
+	}
+	if len(members) == 1 {
+		typ := g.Visit(members[0])
+		if nullable && isGoPointerable(typ) {
+			return "*" + typ
+		}
+		return typ
+	}
+
+	name := g.takeName(s)
+	iface := name + "Value"
+	marker := "is" + iface
+	g.imports["encoding/json"] = struct{}{}
+	g.imports["fmt"] = struct{}{}
+
+	var b strings.Builder
+	b.WriteString(g.docComment(s.Base()))
+	fmt.Fprintf(&b, "type %s struct {\n\tValue %s\n}\n\n", name, iface)
+	fmt.Fprintf(&b, "// %s is implemented by members of %s.\ntype %s interface {\n\t%s()\n}\n\n", iface, name, iface, marker)
+
+	memberTypes := make([]string, len(members))
+	for i, m := range members {
+		g.nameHint = name + "Member" + strconv.Itoa(i)
+		typ := g.Visit(m)
+		if !g.isDeclaredType(typ) {
+			// Types without declaration in this file are wrapped to implement the marker method.
+			wrapper := g.uniqueName(name + goIdentifier(strings.TrimLeft(typ, "[]*.")))
+			underlying := typ
+			if g.isTimeWrapper(typ) {
+				underlying = "struct{ " + typ + " }"
+			}
+			g.decls.Set(wrapper, fmt.Sprintf("type %s %s\n", wrapper, underlying))
+			typ = wrapper
+		}
+		memberTypes[i] = typ
+		target := typ
+		if alias, ok := g.aliases[typ]; ok {
+			target = alias
+		}
+		if _, ok := g.unionMembers[target+"."+marker]; !ok {
+			g.unionMembers[target+"."+marker] = struct{}{}
+			fmt.Fprintf(&b, "func (%s) %s() {}\n", typ, marker)
+		}
+	}
+
+	fmt.Fprintf(&b, "\nfunc (u %s) MarshalJSON() ([]byte, error) {\n\treturn json.Marshal(u.Value)\n}\n\n", name)
+	fmt.Fprintf(&b, "func (u *%s) UnmarshalJSON(data []byte) error {\n", name)
+	if nullable {
+		b.WriteString("\tif string(data) == \"null\" {\n\t\tu.Value = nil\n\t\treturn nil\n\t}\n")
+	}
+	if discriminator := unionDiscriminator(members); discriminator != "" {
+		fmt.Fprintf(&b, "\tvar probe map[string]any\n\tif err := json.Unmarshal(data, &probe); err != nil {\n\t\treturn err\n\t}\n")
+		fmt.Fprintf(&b, "\tswitch fmt.Sprint(probe[%q]) {\n", discriminator)
+		for i, m := range members {
+			fmt.Fprintf(&b, "\tcase %q:\n\t\tvar v %s\n\t\tif err := json.Unmarshal(data, &v); err != nil {\n\t\t\treturn err\n\t\t}\n\t\tu.Value = v\n",
+				discriminatorValueOf(g.discriminatedMember(m)), memberTypes[i])
+		}
+		fmt.Fprintf(&b, "\tdefault:\n\t\treturn fmt.Errorf(\"unknown %s %%v of %s\", probe[%q])\n\t}\n\treturn nil\n}\n", discriminator, name, discriminator)
+	} else {
+		g.imports["bytes"] = struct{}{}
+		strict := g.useHelper(goHelperStrict)
+		for _, typ := range memberTypes {
+			fmt.Fprintf(&b, "\t{\n\t\tvar v %s\n\t\tif err := %s(data, &v); err == nil {\n\t\t\tu.Value = v\n\t\t\treturn nil\n\t\t}\n\t}\n", typ, strict)
+		}
+		fmt.Fprintf(&b, "\treturn fmt.Errorf(\"value does not match any member of %s\")\n}\n", name)
+	}
+	g.decls.Set(name, b.String())
+
+	if nullable {
+		return "*" + name
+	}
+	return name
+}
+
+// discriminatedMember returns the declared library type of the union member
+// since aliases in type expressions lose the original name.
+func (g *GoGenerator) discriminatedMember(s Shape) *ObjectShape {
+	o := s.(*ObjectShape)
+	if name, ok := g.names[o.Id]; ok && o.DiscriminatorValue == nil && o.Name != name {
+		c := *o
+		c.Name = name
+		return &c
+	}
+	return o
+}
+
+func (g *GoGenerator) isDeclaredType(typ string) bool {
+	if g.isTimeWrapper(typ) {
+		return false
+	}
+	if _, ok := g.aliases[typ]; ok {
+		return true
+	}
+	_, ok := g.decls.Get(typ)
+	return ok
+}
+
+func (g *GoGenerator) declareEnum(s Shape, typ string, enum Nodes) string {
+	base := s.Base()
+	name := g.takeName(s)
+	var b strings.Builder
+	b.WriteString(g.docComment(base))
+	fmt.Fprintf(&b, "type %s %s\n\nconst (\n", name, typ)
+	consts := make(map[string]struct{})
+	for _, e := range enum {
+		var constName, value string
+		switch v := e.Value.(type) {
+		case string:
+			constName = name + goIdentifier(v)
+			value = strconv.Quote(v)
+		default:
+			s := fmt.Sprint(v)
+			constName = name + strings.ReplaceAll(s, "-", "Minus")
+			value = s
+		}
+		for n := 1; ; n++ {
+			if _, ok := consts[constName]; !ok {
+				break
+			}
+			constName += strconv.Itoa(n)
+		}
+		consts[constName] = struct{}{}
+		fmt.Fprintf(&b, "\t%s %s = %s\n", constName, name, value)
+	}
+	b.WriteString(")\n")
+	g.decls.Set(name, b.String())
+	return name
+}
+
+func (g *GoGenerator) docComment(base *BaseShape) string {
+	var parts []string
+	if base.DisplayName != nil {
+		parts = append(parts, *base.DisplayName)
+	}
+	if base.Description != nil {
+		parts = append(parts, *base.Description)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return goComment(strings.Join(parts, "\n\n"), "")
+}
+
+func unionDiscriminator(members []Shape) string {
+	var discriminator string
+	for _, m := range members {
+		o, ok := m.(*ObjectShape)
+		if !ok || o.Discriminator == nil {
+			return ""
+		}
+		if discriminator == "" {
+			discriminator = *o.Discriminator
+		} else if discriminator != *o.Discriminator {
+			return ""
+		}
+	}
+	return discriminator
+}
+
+// isGoPointerable reports whether optional values of the type must be pointers to distinguish absent values.
+func isGoPointerable(typ string) bool {
+	return !strings.HasPrefix(typ, "*") && !strings.HasPrefix(typ, "[]") && !strings.HasPrefix(typ, "map[") &&
+		typ != "any" && typ != "json.RawMessage"
+}
+
+func goComment(text string, indent string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		b.WriteString(indent)
+		b.WriteString("// ")
+		b.WriteString(strings.TrimRight(line, " \t"))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// goIdentifier converts a name to an exported Go identifier.
+func goIdentifier(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for _, p := range parts {
+		if _, ok := goInitialisms[strings.ToUpper(p)]; ok {
+			b.WriteString(strings.ToUpper(p))
+			continue
+		}
+		r := []rune(p)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	id := b.String()
+	if id == "" {
+		return "X"
+	}
+	if unicode.IsDigit([]rune(id)[0]) {
+		return "X" + id
+	}
+	return id
+}
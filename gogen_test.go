@@ -0,0 +1,99 @@
+package raml
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoGenerator_GenerateLibrary(t *testing.T) {
+	rml, err := ParseFromPath("./tests/gogen/library.raml", OptWithUnwrap(), OptWithValidate())
+	require.NoError(t, err)
+	src, err := NewGoGenerator(WithGoPackage("main")).GenerateLibrary(rml.EntryPoint().(*Library))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "library type keeps its name", want: "type DateTime struct {"},
+		{name: "time wrapper is renamed", want: "type DateTime1 struct {\n\ttime.Time\n}"},
+		{name: "datetime-only layout", want: `"2006-01-02T15:04:05.999999999"`},
+		{name: "rfc2616 is formatted in UTC", want: `json.Marshal(t.UTC().Format("Mon, 02 Jan 2006 15:04:05 GMT"))`},
+		{name: "strict unmarshal helper", want: "func unmarshalStrict(data []byte, v any) error {"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Contains(t, string(src), tt.want)
+		})
+	}
+}
+
+func TestGoGenerator_RoundTrip(t *testing.T) {
+	goBin, err := exec.LookPath("go")
+	if testing.Short() || err != nil {
+		t.Skip("go toolchain is required to build generated code")
+	}
+	rml, err := ParseFromPath("./tests/gogen/library.raml", OptWithUnwrap(), OptWithValidate())
+	require.NoError(t, err)
+	src, err := NewGoGenerator(WithGoPackage("main")).GenerateLibrary(rml.EntryPoint().(*Library))
+	require.NoError(t, err)
+
+	// Examples of the library types are round-tripped through the generated types.
+	type example struct {
+		typeName string
+		data     string
+	}
+	var values []example
+	lib := rml.EntryPoint().(*Library)
+	for pair := lib.Types.Oldest(); pair != nil; pair = pair.Next() {
+		base := (*pair.Value).Base()
+		var nodes []*Node
+		if base.Example != nil {
+			nodes = append(nodes, base.Example.Data)
+		}
+		if base.Examples != nil && base.Examples.Map != nil {
+			for ex := base.Examples.Map.Oldest(); ex != nil; ex = ex.Next() {
+				nodes = append(nodes, ex.Value.Data)
+			}
+		}
+		for _, n := range nodes {
+			data, err := json.Marshal(n.Value)
+			require.NoError(t, err)
+			values = append(values, example{typeName: pair.Key, data: string(data)})
+		}
+	}
+	require.Len(t, values, 4)
+
+	var main strings.Builder
+	main.WriteString("package main\n\nimport (\n\t\"encoding/json\"\n\t\"fmt\"\n)\n\n")
+	main.WriteString("func roundTrip[T any](data string) {\n\tvar v T\n\tif err := json.Unmarshal([]byte(data), &v); err != nil {\n\t\tpanic(err)\n\t}\n")
+	main.WriteString("\tout, err := json.Marshal(v)\n\tif err != nil {\n\t\tpanic(err)\n\t}\n\tfmt.Println(string(out))\n}\n\nfunc main() {\n")
+	for _, v := range values {
+		fmt.Fprintf(&main, "\troundTrip[%s](%q)\n", v.typeName, v.data)
+	}
+	main.WriteString("}\n")
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module gen\n\ngo 1.22\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "types.go"), src, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte(main.String()), 0o600))
+
+	cmd := exec.Command(goBin, "run", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOTOOLCHAIN=local")
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, "generated code:\n%s\noutput:\n%s", src, out)
+
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	require.Len(t, lines, len(values))
+	for i, v := range values {
+		require.JSONEq(t, v.data, lines[i])
+	}
+}
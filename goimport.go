@@ -0,0 +1,462 @@
+package raml
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/acronis/go-raml/stacktrace"
+)
+
+// GoFacetsTag is the struct tag with RAML facets of the field type,
+// e.g. `raml:"minLength=1;maxLength=64;pattern=^[a-z]+$"`.
+const GoFacetsTag = "raml"
+
+// goStringFacets are facets whose values are always strings in the facets tag.
+var goStringFacets = map[string]struct{}{
+	"pattern": {}, "description": {}, "displayName": {}, "format": {}, "discriminator": {}, "discriminatorValue": {},
+}
+
+// goTimeTypes maps time.Time wrappers generated by GoGenerator to RAML date types and formats.
+var goTimeTypes = map[string][2]string{
+	"DateTime":        {TypeDatetime, ""},
+	"DateTimeRFC2616": {TypeDatetime, "rfc2616"},
+	"DateTimeOnly":    {TypeDatetimeOnly, ""},
+	"DateOnly":        {TypeDateOnly, ""},
+	"TimeOnly":        {TypeTimeOnly, ""},
+}
+
+var jsonRawMessageType = reflect.TypeOf(json.RawMessage{})
+
+type GoImporterOpt interface {
+	Apply(*GoImporterOptions)
+}
+
+type optGoSource struct {
+	dirs []string
+}
+
+func (o optGoSource) Apply(e *GoImporterOptions) {
+	e.sourceDirs = append(e.sourceDirs, o.dirs...)
+}
+
+// WithGoSource sets directories with Go sources of the packages that declare the types.
+// Doc comments of types and fields become descriptions, typed constants become enums.
+func WithGoSource(dirs ...string) GoImporterOpt {
+	return optGoSource{dirs: dirs}
+}
+
+type GoImporterOptions struct {
+	sourceDirs []string
+}
+
+// GoImporter converts Go types to RAML 1.0 libraries using reflection.
+// Named Go types become library types, struct fields become properties named after json tags.
+// Fields are required unless they are pointers or have omitempty option.
+type GoImporter struct {
+	opts GoImporterOptions
+
+	location string
+	types    *orderedmap.OrderedMap[string, *yaml.Node]
+	names    map[reflect.Type]string
+	// docs holds doc comments by qualified type names ("pkg.Type") and field names ("pkg.Type.Field").
+	docs map[string]string
+	// enums holds values of typed constants by qualified type names.
+	enums    map[string][]*yaml.Node
+	warnings []*stacktrace.StackTrace
+}
+
+func NewGoImporter(opts ...GoImporterOpt) *GoImporter {
+	i := &GoImporter{}
+	for _, opt := range opts {
+		opt.Apply(&i.opts)
+	}
+	return i
+}
+
+// ImportGoTypes converts Go types to the RAML model.
+// The types are converted to a RAML library located at the given location,
+// which is then parsed with the regular parser and given options.
+func ImportGoTypes(ctx context.Context, goTypes []reflect.Type, location string, opts ...ParseOpt) (*RAML, []*stacktrace.StackTrace, error) {
+	if ctx == nil {
+		return nil, nil, fmt.Errorf("context is nil")
+	}
+	content, warnings, err := NewGoImporter().ConvertToRAML(goTypes, location)
+	if err != nil {
+		return nil, warnings, err
+	}
+	rml := New(ctx)
+	err = rml.ParseFromString(string(content), filepath.Base(location), filepath.Dir(location), opts...)
+	return rml, warnings, err
+}
+
+// ConvertToRAML converts Go types and the named types they reference to RAML 1.0 Library definition.
+// Types that have no RAML counterpart are reported as warnings.
+func (i *GoImporter) ConvertToRAML(goTypes []reflect.Type, location string) ([]byte, []*stacktrace.StackTrace, error) {
+	i.location = location
+	i.types = orderedmap.New[string, *yaml.Node]()
+	i.names = make(map[reflect.Type]string)
+	i.docs = make(map[string]string)
+	i.enums = make(map[string][]*yaml.Node)
+	i.warnings = nil
+
+	for _, dir := range i.opts.sourceDirs {
+		if err := i.loadSource(dir); err != nil {
+			return nil, nil, stacktrace.NewWrapped("load source", err, location,
+				stacktrace.WithType(stacktrace.TypeParsing), stacktrace.WithInfo("dir", dir))
+		}
+	}
+	for _, t := range goTypes {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Name() == "" {
+			return nil, nil, stacktrace.New("type must be named", location,
+				stacktrace.WithType(stacktrace.TypeConverting), stacktrace.WithInfo("type", t.String()))
+		}
+		i.declaration(t)
+	}
+
+	types := yamlMapNode()
+	for pair := i.types.Oldest(); pair != nil; pair = pair.Next() {
+		yamlMapSet(types, pair.Key, pair.Value)
+	}
+	out, err := yaml.Marshal(yamlMapNode("types", types))
+	if err != nil {
+		return nil, i.warnings, stacktrace.NewWrapped("marshal library", err, location, stacktrace.WithType(stacktrace.TypeConverting))
+	}
+	return append([]byte("#%RAML 1.0 Library\n"), out...), i.warnings, nil
+}
+
+// declaration returns RAML type declaration of the Go type.
+// Named types are declared in the library and referenced by name.
+// The returned node is either a type expression or a map of facets.
+func (i *GoImporter) declaration(t reflect.Type) *yaml.Node {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if decl := i.builtinDeclaration(t); decl != nil {
+		return decl
+	}
+	if t.Name() == "" || t.PkgPath() == "" {
+		return i.typeDeclaration(t)
+	}
+	if name, ok := i.names[t]; ok {
+		return yamlStrNode(name)
+	}
+	// Name is reserved first to support recursive types.
+	name := reserveTypeName(i.types, t.Name())
+	i.names[t] = name
+	decl := i.typeDeclaration(t)
+	if values, ok := i.enums[t.String()]; ok {
+		decl = toDeclarationMap(decl)
+		yamlMapSet(decl, "enum", &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: values})
+	}
+	if doc, ok := i.docs[t.String()]; ok {
+		decl = toDeclarationMap(decl)
+		yamlMapSet(decl, "description", yamlStrNode(doc))
+	}
+	i.types.Set(name, decl)
+	return yamlStrNode(name)
+}
+
+// builtinDeclaration returns declarations of Go types that have RAML counterparts regardless of their kind.
+func (i *GoImporter) builtinDeclaration(t reflect.Type) *yaml.Node {
+	switch t {
+	case timeType:
+		return yamlStrNode(TypeDatetime)
+	case jsonNumberType:
+		return yamlStrNode(TypeNumber)
+	case jsonRawMessageType:
+		return yamlStrNode(TypeAny)
+	}
+	// Wrappers of time.Time, including those generated by GoGenerator.
+	if t.Kind() == reflect.Struct && t.NumField() == 1 && t.Field(0).Anonymous && t.Field(0).Type == timeType {
+		tf, ok := goTimeTypes[t.Name()]
+		if !ok {
+			return yamlStrNode(TypeDatetime)
+		}
+		if tf[1] == "" {
+			return yamlStrNode(tf[0])
+		}
+		return yamlMapNode("type", yamlStrNode(tf[0]), "format", yamlStrNode(tf[1]))
+	}
+	return nil
+}
+
+func (i *GoImporter) typeDeclaration(t reflect.Type) *yaml.Node {
+	if t.Implements(jsonMarshalerType) {
+		i.warn("type implements json.Marshaler, its JSON representation is unknown", stacktrace.WithInfo("type", t.String()))
+		return yamlStrNode(TypeAny)
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return yamlStrNode(TypeBoolean)
+	case reflect.Int, reflect.Int64:
+		return yamlMapNode("type", yamlStrNode(TypeInteger), "format", yamlStrNode("int64"))
+	case reflect.Int8:
+		return yamlMapNode("type", yamlStrNode(TypeInteger), "format", yamlStrNode("int8"))
+	case reflect.Int16:
+		return yamlMapNode("type", yamlStrNode(TypeInteger), "format", yamlStrNode("int16"))
+	case reflect.Int32:
+		return yamlMapNode("type", yamlStrNode(TypeInteger), "format", yamlStrNode("int32"))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		decl := yamlMapNode("type", yamlStrNode(TypeInteger), "minimum", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: "0"})
+		if bits := t.Bits(); bits < 64 {
+			yamlMapSet(decl, "maximum", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.FormatUint(1<<bits-1, 10)})
+		}
+		return decl
+	case reflect.Float32:
+		return yamlMapNode("type", yamlStrNode(TypeNumber), "format", yamlStrNode("float"))
+	case reflect.Float64:
+		return yamlMapNode("type", yamlStrNode(TypeNumber), "format", yamlStrNode("double"))
+	case reflect.String:
+		return yamlStrNode(TypeString)
+	case reflect.Interface:
+		return yamlStrNode(TypeAny)
+	case reflect.Slice, reflect.Array:
+		// Byte slices are encoded as base64 strings like encoding/json does.
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return yamlStrNode(TypeString)
+		}
+		items := i.declaration(t.Elem())
+		var decl *yaml.Node
+		if items.Kind == yaml.ScalarNode && !strings.ContainsAny(items.Value, "| ") {
+			decl = yamlMapNode("type", yamlStrNode(items.Value+"[]"))
+		} else {
+			decl = yamlMapNode("type", yamlStrNode(TypeArray), "items", items)
+		}
+		if t.Kind() == reflect.Array {
+			length := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(t.Len())}
+			yamlMapSet(decl, "minItems", length)
+			yamlMapSet(decl, "maxItems", length)
+		}
+		return compactDeclaration(decl)
+	case reflect.Map:
+		return yamlMapNode("type", yamlStrNode(TypeObject), "properties", yamlMapNode("//", i.declaration(t.Elem())))
+	case reflect.Struct:
+		return i.structDeclaration(t)
+	}
+	i.warn("type is not supported", stacktrace.WithInfo("type", t.String()))
+	return yamlStrNode(TypeAny)
+}
+
+func (i *GoImporter) structDeclaration(t reflect.Type) *yaml.Node {
+	decl := yamlMapNode("type", yamlStrNode(TypeObject))
+	properties := yamlMapNode()
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		// Fields of embedded structs without name are promoted.
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if strings.HasPrefix(name, "/") && strings.HasSuffix(name, "/") {
+			i.warn("property name is reserved for pattern properties and is skipped", stacktrace.WithInfo("property", name))
+			continue
+		}
+		propDecl := i.declaration(f.Type)
+		if facets := f.Tag.Get(GoFacetsTag); facets != "" {
+			propDecl = toDeclarationMap(propDecl)
+			i.applyFacets(propDecl, facets, t.String()+"."+f.Name)
+		}
+		if doc, ok := i.docs[fieldOwner(t, f).String()+"."+f.Name]; ok && yamlMapGet(propDecl, "description") == nil {
+			propDecl = toDeclarationMap(propDecl)
+			yamlMapSet(propDecl, "description", yamlStrNode(doc))
+		}
+		required := f.Type.Kind() != reflect.Pointer && !strings.Contains(","+opts+",", ",omitempty,")
+		// Explicit "required" facet keeps the trailing "?" as part of the property name.
+		if !required || strings.HasSuffix(name, "?") {
+			propDecl = toDeclarationMap(propDecl)
+			yamlMapSet(propDecl, "required", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(required)})
+		}
+		yamlMapSet(properties, name, compactDeclaration(propDecl))
+	}
+	if len(properties.Content) > 0 {
+		yamlMapSet(decl, "properties", properties)
+	}
+	return compactDeclaration(decl)
+}
+
+// applyFacets sets facets from the facets tag to the declaration.
+// Facets are separated by semicolons, numbers and booleans are typed, enum accepts YAML flow sequence.
+func (i *GoImporter) applyFacets(decl *yaml.Node, tag string, field string) {
+	for _, facet := range strings.Split(tag, ";") {
+		facet = strings.TrimSpace(facet)
+		if facet == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(facet, "=")
+		if !ok {
+			i.warn("facet must be in key=value form", stacktrace.WithInfo("field", field), stacktrace.WithInfo("facet", facet))
+			continue
+		}
+		key = strings.TrimSpace(key)
+		if _, ok := goStringFacets[key]; ok {
+			yamlMapSet(decl, key, yamlStrNode(value))
+			continue
+		}
+		var node yaml.Node
+		if err := yaml.Unmarshal([]byte(value), &node); err != nil || len(node.Content) == 0 {
+			i.warn("invalid facet value", stacktrace.WithInfo("field", field), stacktrace.WithInfo("facet", facet))
+			continue
+		}
+		yamlMapSet(decl, key, node.Content[0])
+	}
+}
+
+// loadSource reads doc comments and typed constants of the package in the directory.
+func (i *GoImporter) loadSource(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("read dir: %w", err)
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".go") || strings.HasSuffix(e.Name(), "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(dir, e.Name()), nil, parser.ParseComments)
+		if err != nil {
+			return fmt.Errorf("parse file: %w", err)
+		}
+		files = append(files, f)
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no Go files")
+	}
+	pkgName := files[0].Name.Name
+	for _, f := range files {
+		for _, d := range f.Decls {
+			gd, ok := d.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				doc := ts.Doc
+				if doc == nil && len(gd.Specs) == 1 {
+					doc = gd.Doc
+				}
+				i.setDoc(pkgName+"."+ts.Name.Name, doc)
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				for _, field := range st.Fields.List {
+					doc := field.Doc
+					if doc == nil {
+						doc = field.Comment
+					}
+					for _, n := range field.Names {
+						i.setDoc(pkgName+"."+ts.Name.Name+"."+n.Name, doc)
+					}
+				}
+			}
+		}
+	}
+
+	// Constants are evaluated by the type checker. Errors of unresolved imports are ignored
+	// since they do not affect constants of the package types.
+	conf := types.Config{Importer: importer.Default(), Error: func(error) {}}
+	pkg, _ := conf.Check(pkgName, fset, files, nil)
+	if pkg == nil {
+		return nil
+	}
+	var consts []*types.Const
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		c, ok := scope.Lookup(name).(*types.Const)
+		if !ok || !c.Exported() {
+			continue
+		}
+		if named, ok := c.Type().(*types.Named); ok && named.Obj().Pkg() == pkg {
+			consts = append(consts, c)
+		}
+	}
+	sort.Slice(consts, func(a, b int) bool { return consts[a].Pos() < consts[b].Pos() })
+	for _, c := range consts {
+		key := pkgName + "." + c.Type().(*types.Named).Obj().Name()
+		if node := constantNode(c.Val()); node != nil {
+			i.enums[key] = append(i.enums[key], node)
+		}
+	}
+	return nil
+}
+
+func (i *GoImporter) setDoc(key string, doc *ast.CommentGroup) {
+	if text := strings.TrimSpace(doc.Text()); text != "" {
+		i.docs[key] = text
+	}
+}
+
+func (i *GoImporter) warn(message string, opts ...stacktrace.Option) {
+	opts = append(opts,
+		stacktrace.WithSeverity(stacktrace.SeverityWarning),
+		stacktrace.WithType(stacktrace.TypeConverting),
+	)
+	i.warnings = append(i.warnings, stacktrace.New(message, i.location, opts...))
+}
+
+// fieldOwner returns the struct type that declares the field, which differs from t for promoted fields.
+func fieldOwner(t reflect.Type, f reflect.StructField) reflect.Type {
+	for _, idx := range f.Index[:len(f.Index)-1] {
+		t = t.Field(idx).Type
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+	}
+	return t
+}
+
+func constantNode(v constant.Value) *yaml.Node {
+	switch v.Kind() {
+	case constant.String:
+		return yamlStrNode(constant.StringVal(v))
+	case constant.Int:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: v.ExactString()}
+	case constant.Float:
+		f, _ := constant.Float64Val(v)
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: strconv.FormatFloat(f, 'g', -1, 64)}
+	case constant.Bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(constant.BoolVal(v))}
+	}
+	return nil
+}
+
+// toDeclarationMap returns the declaration as a map of facets.
+func toDeclarationMap(decl *yaml.Node) *yaml.Node {
+	if decl.Kind == yaml.MappingNode {
+		return decl
+	}
+	return yamlMapNode("type", decl)
+}
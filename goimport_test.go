@@ -0,0 +1,148 @@
+package raml
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type goImportTestStatus string
+
+type goImportTestBase struct {
+	ID int64 `json:"id"`
+}
+
+type goImportTestPet struct {
+	goImportTestBase
+	Name    string             `json:"name" raml:"minLength=1;pattern=^[a-z]+$"`
+	Age     *uint8             `json:"age"`
+	Tags    []string           `json:"tags,omitempty"`
+	Status  goImportTestStatus `json:"status"`
+	Born    time.Time          `json:"born"`
+	Labels  map[string]float32 `json:"labels,omitempty"`
+	Parent  *goImportTestPet   `json:"parent,omitempty"`
+	Secret  string             `json:"-"`
+	private string
+}
+
+type goImportTestInvalid struct {
+	Ch   chan int `json:"ch"`
+	Size int      `json:"size" raml:"minimum"`
+}
+
+func TestGoImporter_ConvertToRAML(t *testing.T) {
+	got, warnings, err := NewGoImporter().ConvertToRAML([]reflect.Type{reflect.TypeOf(&goImportTestPet{})}, "pet.raml")
+	require.NoError(t, err)
+	require.Empty(t, warnings)
+	require.Equal(t, `#%RAML 1.0 Library
+types:
+    goImportTestPet:
+        type: object
+        properties:
+            id:
+                type: integer
+                format: int64
+            name:
+                type: string
+                minLength: 1
+                pattern: ^[a-z]+$
+            age:
+                type: integer
+                minimum: 0
+                maximum: 255
+                required: false
+            tags:
+                type: string[]
+                required: false
+            status: goImportTestStatus
+            born: datetime
+            labels:
+                type: object
+                properties:
+                    //:
+                        type: number
+                        format: float
+                required: false
+            parent:
+                type: goImportTestPet
+                required: false
+    goImportTestStatus: string
+`, string(got))
+}
+
+func TestGoImporter_ConvertToRAML_Source(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pet.go"), []byte(`package raml
+
+// goImportTestStatus is the status of the pet.
+type goImportTestStatus string
+
+const (
+	StatusActive goImportTestStatus = "active"
+	StatusSold   goImportTestStatus = "sold"
+)
+
+type goImportTestInvalid struct {
+	// Size is the size.
+	Size int
+}
+`), 0o600))
+	got, warnings, err := NewGoImporter(WithGoSource(dir)).ConvertToRAML(
+		[]reflect.Type{reflect.TypeOf(goImportTestStatus("")), reflect.TypeOf(goImportTestInvalid{})}, "pet.raml")
+	require.NoError(t, err)
+	require.Equal(t, `#%RAML 1.0 Library
+types:
+    goImportTestStatus:
+        type: string
+        enum:
+            - active
+            - sold
+        description: goImportTestStatus is the status of the pet.
+    goImportTestInvalid:
+        type: object
+        properties:
+            ch: any
+            size:
+                type: integer
+                format: int64
+                description: Size is the size.
+`, string(got))
+	messages := make([]string, len(warnings))
+	for i, w := range warnings {
+		messages[i] = w.Message
+	}
+	require.Equal(t, []string{"type is not supported", "facet must be in key=value form"}, messages)
+}
+
+func TestGoImporter_ConvertToRAML_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		types []reflect.Type
+		opts  []GoImporterOpt
+	}{
+		{name: "unnamed type", types: []reflect.Type{reflect.TypeOf(struct{}{})}},
+		{name: "missing source", types: []reflect.Type{reflect.TypeOf(goImportTestPet{})}, opts: []GoImporterOpt{WithGoSource(filepath.Join(t.TempDir(), "missing"))}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := NewGoImporter(tt.opts...).ConvertToRAML(tt.types, "pet.raml")
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestImportGoTypes(t *testing.T) {
+	rml, warnings, err := ImportGoTypes(context.Background(), []reflect.Type{reflect.TypeOf(goImportTestPet{})},
+		filepath.Join(t.TempDir(), "pet.raml"), OptWithUnwrap(), OptWithValidate())
+	require.NoError(t, err)
+	require.Empty(t, warnings)
+	s := libraryType(t, rml, "goImportTestPet")
+	age := uint8(2)
+	require.NoError(t, ValidateValue(s, &goImportTestPet{Name: "rex", Age: &age, Status: "active", Born: time.Now()}, "$"))
+	require.Error(t, ValidateValue(s, &goImportTestPet{Name: "Rex", Status: "active", Born: time.Now()}, "$"))
+}
@@ -0,0 +1,515 @@
+package raml
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+
+	"github.com/acronis/go-raml/stacktrace"
+)
+
+var graphQLNameRegexp = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// graphQLScalars are descriptions of custom scalars used for RAML types without GraphQL counterparts.
+var graphQLScalars = map[string]string{
+	"DateTime":        "Date and time in RFC3339 format.",
+	"DateTimeRFC2616": "Date and time in RFC2616 format.",
+	"LocalDateTime":   "Date and time without time zone, e.g. 2006-01-02T15:04:05.",
+	"Date":            "Date without time zone, e.g. 2006-01-02.",
+	"Time":            "Time without time zone, e.g. 15:04:05.",
+	"Long":            "64-bit signed integer.",
+	"File":            "File contents.",
+	"JSON":            "Arbitrary JSON value.",
+}
+
+// graphQLType is a GraphQL type reference.
+type graphQLType struct {
+	name     string
+	nullable bool
+}
+
+// GraphQLConverter converts library types to GraphQL SDL.
+// Objects are converted to pairs of object and input types, the latter named with the "Input" suffix.
+// Visit methods return type references for the current mode and declare named types when necessary.
+type GraphQLConverter struct {
+	decls *orderedmap.OrderedMap[string, string]
+	// names maps shape IDs to names of declared types.
+	names map[string]string
+	// objects holds names of object types that have input counterparts.
+	objects  map[string]struct{}
+	scalars  map[string]struct{}
+	warnings []*stacktrace.StackTrace
+	// warned holds reported warnings since fields are visited for both object and input types.
+	warned map[string]struct{}
+	// input reports whether the type is referenced from an input type.
+	input    bool
+	nameHint string
+}
+
+var _ ShapeVisitor[graphQLType] = (*GraphQLConverter)(nil)
+
+func NewGraphQLConverter() *GraphQLConverter {
+	return &GraphQLConverter{}
+}
+
+// ConvertLibrary converts the library and the libraries it uses into GraphQL SDL.
+// Types of used libraries are prefixed with the "uses" alias, e.g. "CommonType".
+// Constructs that cannot be represented in GraphQL are reported as warnings.
+func (c *GraphQLConverter) ConvertLibrary(lib *Library) ([]byte, []*stacktrace.StackTrace) {
+	c.decls = orderedmap.New[string, string]()
+	c.names = make(map[string]string)
+	c.objects = make(map[string]struct{})
+	c.scalars = make(map[string]struct{})
+	c.warnings = nil
+	c.warned = make(map[string]struct{})
+	c.input = false
+
+	types := orderedmap.New[string, Shape]()
+	c.collectLibrary(lib, "", types, make(map[string]struct{}))
+	// Only objects, enums and unions are declared, other types are inlined into fields.
+	for pair := types.Oldest(); pair != nil; pair = pair.Next() {
+		id := pair.Value.Base().Id
+		if target, ok := c.names[id]; ok {
+			c.warn(pair.Value.Base(), "alias is replaced with the referenced type", stacktrace.WithInfo("type", target))
+			continue
+		}
+		if isGraphQLDeclaration(pair.Value) {
+			c.names[id] = pair.Key
+			c.decls.Set(pair.Key, "")
+			if _, ok := pair.Value.(*ObjectShape); ok {
+				c.objects[pair.Key] = struct{}{}
+				c.decls.Set(pair.Key+"Input", "")
+			}
+		}
+	}
+	for pair := types.Oldest(); pair != nil; pair = pair.Next() {
+		if c.names[pair.Value.Base().Id] != pair.Key {
+			continue
+		}
+		c.nameHint = pair.Key
+		c.visit(pair.Value)
+	}
+	return c.render(), c.warnings
+}
+
+func isGraphQLDeclaration(s Shape) bool {
+	switch s := s.(type) {
+	case *ObjectShape:
+		return s.Properties != nil && s.Properties.Len() > 0
+	case *StringShape:
+		return s.Enum != nil
+	case *UnionShape:
+		members := 0
+		for _, item := range s.AnyOf {
+			if _, ok := (*item).(*NilShape); !ok {
+				members++
+			}
+		}
+		return members > 1
+	}
+	return false
+}
+
+func (c *GraphQLConverter) collectLibrary(lib *Library, prefix string, types *orderedmap.OrderedMap[string, Shape], visited map[string]struct{}) {
+	if _, ok := visited[lib.Location]; ok {
+		return
+	}
+	visited[lib.Location] = struct{}{}
+	for pair := lib.Types.Oldest(); pair != nil; pair = pair.Next() {
+		s := *pair.Value
+		if !s.Base().IsUnwrapped() {
+			us, err := lib.raml.UnwrapShape(pair.Value, make([]Shape, 0))
+			if err != nil {
+				c.warn(s.Base(), "type cannot be unwrapped and is skipped", stacktrace.WithInfo("error", err))
+				continue
+			}
+			s = us
+		}
+		types.Set(prefix+goIdentifier(pair.Key), s)
+	}
+	for pair := lib.Uses.Oldest(); pair != nil; pair = pair.Next() {
+		if pair.Value.Link != nil {
+			c.collectLibrary(pair.Value.Link, prefix+goIdentifier(pair.Key), types, visited)
+		}
+	}
+}
+
+func (c *GraphQLConverter) render() []byte {
+	var b strings.Builder
+	b.WriteString("# Code generated by go-raml. DO NOT EDIT.\n")
+	scalars := make([]string, 0, len(c.scalars))
+	for k := range c.scalars {
+		scalars = append(scalars, k)
+	}
+	sort.Strings(scalars)
+	for _, s := range scalars {
+		fmt.Fprintf(&b, "\n%sscalar %s\n", graphQLDescription(graphQLScalars[s], ""), s)
+	}
+	for pair := c.decls.Oldest(); pair != nil; pair = pair.Next() {
+		if pair.Value == "" {
+			continue
+		}
+		b.WriteString("\n")
+		b.WriteString(pair.Value)
+	}
+	return []byte(b.String())
+}
+
+// Visit returns the reference to the GraphQL type of the shape.
+func (c *GraphQLConverter) Visit(s Shape) graphQLType {
+	if name, ok := c.names[s.Base().Id]; ok {
+		return c.reference(s, name)
+	}
+	return c.visit(s)
+}
+
+// reference returns the reference to the declared type in the current mode.
+func (c *GraphQLConverter) reference(s Shape, name string) graphQLType {
+	if _, ok := c.objects[name]; ok && c.input {
+		return graphQLType{name: name + "Input"}
+	}
+	if _, ok := s.(*UnionShape); ok && c.input {
+		c.warn(s.Base(), "unions cannot be used in input types and are replaced with JSON")
+		return c.scalar("JSON")
+	}
+	return graphQLType{name: name}
+}
+
+func (c *GraphQLConverter) visit(s Shape) graphQLType {
+	switch s := s.(type) {
+	case *ObjectShape:
+		return c.VisitObjectShape(s)
+	case *ArrayShape:
+		return c.VisitArrayShape(s)
+	case *StringShape:
+		return c.VisitStringShape(s)
+	case *NumberShape:
+		return c.VisitNumberShape(s)
+	case *IntegerShape:
+		return c.VisitIntegerShape(s)
+	case *BooleanShape:
+		return c.VisitBooleanShape(s)
+	case *FileShape:
+		return c.VisitFileShape(s)
+	case *UnionShape:
+		return c.VisitUnionShape(s)
+	case *DateTimeShape:
+		return c.VisitDateTimeShape(s)
+	case *DateTimeOnlyShape:
+		return c.VisitDateTimeOnlyShape(s)
+	case *DateOnlyShape:
+		return c.VisitDateOnlyShape(s)
+	case *TimeOnlyShape:
+		return c.VisitTimeOnlyShape(s)
+	case *RecursiveShape:
+		return c.VisitRecursiveShape(s)
+	case *JSONShape:
+		return c.VisitJSONShape(s)
+	case *XMLShape:
+		return c.VisitXMLShape(s)
+	case *AnyShape:
+		return c.VisitAnyShape(s)
+	case *NilShape:
+		return c.VisitNilShape(s)
+	default:
+		c.warn(s.Base(), "unsupported type is replaced with JSON")
+		return c.scalar("JSON")
+	}
+}
+
+// takeName returns the name for the declared shape and registers it.
+func (c *GraphQLConverter) takeName(s Shape) string {
+	if name, ok := c.names[s.Base().Id]; ok {
+		return name
+	}
+	name := c.nameHint
+	for n := 1; ; n++ {
+		_, declared := c.decls.Get(name)
+		_, inputDeclared := c.decls.Get(name + "Input")
+		if !declared && !inputDeclared {
+			break
+		}
+		name = c.nameHint + strconv.Itoa(n)
+	}
+	c.names[s.Base().Id] = name
+	c.decls.Set(name, "")
+	return name
+}
+
+func (c *GraphQLConverter) scalar(name string) graphQLType {
+	c.scalars[name] = struct{}{}
+	return graphQLType{name: name}
+}
+
+// VisitObjectShape declares the object type and its input counterpart.
+func (c *GraphQLConverter) VisitObjectShape(s *ObjectShape) graphQLType {
+	if s.Properties == nil || s.Properties.Len() == 0 {
+		if s.PatternProperties != nil && s.PatternProperties.Len() > 0 {
+			c.warn(s.Base(), "maps are replaced with JSON")
+		}
+		return c.scalar("JSON")
+	}
+	name := c.takeName(s)
+	c.objects[name] = struct{}{}
+	if s.PatternProperties != nil && s.PatternProperties.Len() > 0 {
+		c.warn(s.Base(), "pattern properties are dropped")
+	}
+
+	input := c.input
+	defer func() { c.input = input }()
+	for _, mode := range []bool{false, true} {
+		c.input = mode
+		typeName, keyword := name, "type"
+		if mode {
+			typeName, keyword = name+"Input", "input"
+		}
+		// Declaration is reserved to keep the order of types.
+		if _, ok := c.decls.Get(typeName); !ok {
+			c.decls.Set(typeName, "")
+		}
+		var b strings.Builder
+		b.WriteString(graphQLDescription(graphQLDoc(s.Base()), ""))
+		fmt.Fprintf(&b, "%s %s {\n", keyword, typeName)
+		fields := make(map[string]struct{})
+		for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+			prop := pair.Value
+			ps := *prop.Shape
+			c.nameHint = name + goIdentifier(prop.Name)
+			t := c.Visit(ps)
+			field := prop.Name
+			if !graphQLNameRegexp.MatchString(field) {
+				field = graphQLIdentifier(field)
+				if !mode {
+					c.warn(ps.Base(), "property name is not a valid GraphQL name and is renamed", stacktrace.WithInfo("field", field))
+				}
+			}
+			for n := 1; ; n++ {
+				if _, ok := fields[field]; !ok {
+					break
+				}
+				field = graphQLIdentifier(prop.Name) + strconv.Itoa(n)
+			}
+			fields[field] = struct{}{}
+			if _, ok := c.names[ps.Base().Id]; !ok {
+				b.WriteString(graphQLDescription(graphQLDoc(ps.Base()), "  "))
+			}
+			typ := t.name
+			if prop.Required && !t.nullable {
+				typ += "!"
+			}
+			fmt.Fprintf(&b, "  %s: %s\n", field, typ)
+		}
+		b.WriteString("}\n")
+		c.decls.Set(typeName, b.String())
+	}
+	if input {
+		return graphQLType{name: name + "Input"}
+	}
+	return graphQLType{name: name}
+}
+
+func (c *GraphQLConverter) VisitArrayShape(s *ArrayShape) graphQLType {
+	if s.Items == nil {
+		return graphQLType{name: "[" + c.scalar("JSON").name + "]"}
+	}
+	c.nameHint += "Item"
+	item := c.Visit(*s.Items)
+	if !item.nullable {
+		item.name += "!"
+	}
+	return graphQLType{name: "[" + item.name + "]"}
+}
+
+func (c *GraphQLConverter) VisitStringShape(s *StringShape) graphQLType {
+	if s.Enum == nil {
+		return graphQLType{name: "String"}
+	}
+	name := c.takeName(s)
+	var b strings.Builder
+	b.WriteString(graphQLDescription(graphQLDoc(s.Base()), ""))
+	fmt.Fprintf(&b, "enum %s {\n", name)
+	for _, e := range s.Enum {
+		value := fmt.Sprint(e.Value)
+		if !graphQLNameRegexp.MatchString(value) || value == "true" || value == "false" || value == "null" {
+			c.warn(s.Base(), "enum value is not a valid GraphQL name and is renamed", stacktrace.WithInfo("value", value))
+			value = graphQLIdentifier(value)
+		}
+		fmt.Fprintf(&b, "  %s\n", value)
+	}
+	b.WriteString("}\n")
+	c.decls.Set(name, b.String())
+	return graphQLType{name: name}
+}
+
+func (c *GraphQLConverter) VisitNumberShape(s *NumberShape) graphQLType {
+	return graphQLType{name: "Float"}
+}
+
+func (c *GraphQLConverter) VisitIntegerShape(s *IntegerShape) graphQLType {
+	if s.Format != nil && (*s.Format == "int64" || *s.Format == "long") {
+		return c.scalar("Long")
+	}
+	return graphQLType{name: "Int"}
+}
+
+func (c *GraphQLConverter) VisitBooleanShape(s *BooleanShape) graphQLType {
+	return graphQLType{name: "Boolean"}
+}
+
+func (c *GraphQLConverter) VisitFileShape(s *FileShape) graphQLType {
+	return c.scalar("File")
+}
+
+// VisitUnionShape declares a union of object members. Other members are reported and dropped.
+// Unions with nil are represented by nullable types.
+func (c *GraphQLConverter) VisitUnionShape(s *UnionShape) graphQLType {
+	var members []Shape
+	nullable := false
+	for _, item := range s.AnyOf {
+		if _, ok := (*item).(*NilShape); ok {
+			nullable = true
+			continue
+		}
+		members = append(members, *item)
+	}
+	if len(members) == 0 {
+		return graphQLType{name: c.scalar("JSON").name, nullable: true}
+	}
+	if len(members) == 1 {
+		t := c.Visit(members[0])
+		t.nullable = t.nullable || nullable
+		return t
+	}
+	if c.input {
+		c.warn(s.Base(), "unions cannot be used in input types and are replaced with JSON")
+		return graphQLType{name: c.scalar("JSON").name, nullable: nullable}
+	}
+
+	name := c.takeName(s)
+	hint := c.nameHint
+	var types []string
+	for i, m := range members {
+		c.nameHint = hint + "Member" + strconv.Itoa(i)
+		t := c.Visit(m)
+		if _, ok := c.objects[t.name]; !ok {
+			c.warn(s.Base(), "union members must be object types, member is dropped", stacktrace.WithInfo("member", t.name))
+			continue
+		}
+		types = append(types, t.name)
+	}
+	if len(types) == 0 {
+		c.decls.Delete(name)
+		delete(c.names, s.Id)
+		return graphQLType{name: c.scalar("JSON").name, nullable: nullable}
+	}
+	var b strings.Builder
+	b.WriteString(graphQLDescription(graphQLDoc(s.Base()), ""))
+	fmt.Fprintf(&b, "union %s = %s\n", name, strings.Join(types, " | "))
+	c.decls.Set(name, b.String())
+	return graphQLType{name: name, nullable: nullable}
+}
+
+func (c *GraphQLConverter) VisitDateTimeShape(s *DateTimeShape) graphQLType {
+	if s.Format != nil && *s.Format == "rfc2616" {
+		return c.scalar("DateTimeRFC2616")
+	}
+	return c.scalar("DateTime")
+}
+
+func (c *GraphQLConverter) VisitDateTimeOnlyShape(s *DateTimeOnlyShape) graphQLType {
+	return c.scalar("LocalDateTime")
+}
+
+func (c *GraphQLConverter) VisitDateOnlyShape(s *DateOnlyShape) graphQLType {
+	return c.scalar("Date")
+}
+
+func (c *GraphQLConverter) VisitTimeOnlyShape(s *TimeOnlyShape) graphQLType {
+	return c.scalar("Time")
+}
+
+func (c *GraphQLConverter) VisitRecursiveShape(s *RecursiveShape) graphQLType {
+	if name, ok := c.names[(*s.Head).Base().Id]; ok {
+		return c.reference(*s.Head, name)
+	}
+	c.warn(s.Base(), "recursion to undeclared type is replaced with JSON")
+	return c.scalar("JSON")
+}
+
+func (c *GraphQLConverter) VisitJSONShape(s *JSONShape) graphQLType {
+	return c.scalar("JSON")
+}
+
+func (c *GraphQLConverter) VisitXMLShape(s *XMLShape) graphQLType {
+	return graphQLType{name: "String"}
+}
+
+func (c *GraphQLConverter) VisitAnyShape(s *AnyShape) graphQLType {
+	return c.scalar("JSON")
+}
+
+func (c *GraphQLConverter) VisitNilShape(s *NilShape) graphQLType {
+	c.warn(s.Base(), "nil type is replaced with nullable JSON")
+	return graphQLType{name: c.scalar("JSON").name, nullable: true}
+}
+
+func (c *GraphQLConverter) warn(base *BaseShape, message string, opts ...stacktrace.Option) {
+	key := fmt.Sprintf("%s:%d:%d:%s", base.Location, base.Line, base.Column, message)
+	if _, ok := c.warned[key]; ok {
+		return
+	}
+	c.warned[key] = struct{}{}
+	opts = append(opts,
+		stacktrace.WithPosition(&base.Position),
+		stacktrace.WithSeverity(stacktrace.SeverityWarning),
+		stacktrace.WithType(stacktrace.TypeConverting),
+		stacktrace.WithInfo("shape", base.Name),
+	)
+	c.warnings = append(c.warnings, stacktrace.New(message, base.Location, opts...))
+}
+
+func graphQLDoc(base *BaseShape) string {
+	if base.Description != nil {
+		return *base.Description
+	}
+	if base.DisplayName != nil {
+		return *base.DisplayName
+	}
+	return ""
+}
+
+// graphQLDescription returns the description as a block string.
+func graphQLDescription(text string, indent string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return ""
+	}
+	text = strings.ReplaceAll(text, `"""`, `\"""`)
+	if !strings.Contains(text, "\n") {
+		return fmt.Sprintf("%s\"\"\"%s\"\"\"\n", indent, text)
+	}
+	var b strings.Builder
+	b.WriteString(indent + "\"\"\"\n")
+	for _, line := range strings.Split(text, "\n") {
+		b.WriteString(indent + strings.TrimRight(line, " \t") + "\n")
+	}
+	b.WriteString(indent + "\"\"\"\n")
+	return b.String()
+}
+
+func graphQLIdentifier(name string) string {
+	id := strings.Map(func(r rune) rune {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, name)
+	if !graphQLNameRegexp.MatchString(id) || id == "true" || id == "false" || id == "null" {
+		id = "_" + id
+	}
+	return id
+}
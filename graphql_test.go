@@ -0,0 +1,48 @@
+package raml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphQLConverter_ConvertLibrary(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0 Library
+types:
+  Color:
+    enum: [red, green]
+  Shape:
+    type: object
+    properties:
+      name: string
+      color?: Color
+      sides: integer
+      points: number[]
+  Circle:
+    type: object
+    properties:
+      r: number
+  Square:
+    type: object
+    properties:
+      side: number
+  Figure: Circle | Square
+`)
+	out, warnings := NewGraphQLConverter().ConvertLibrary(rml.EntryPoint().(*Library))
+	require.Empty(t, warnings)
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "enum", want: "enum Color {\n  red\n  green\n}\n"},
+		{name: "object type", want: "type Shape {\n  name: String!\n  color: Color\n  sides: Int!\n  points: [Float!]!\n}\n"},
+		{name: "input type", want: "input ShapeInput {\n  name: String!\n  color: Color\n  sides: Int!\n  points: [Float!]!\n}\n"},
+		{name: "union", want: "union Figure = Circle | Square\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Contains(t, string(out), tt.want)
+		})
+	}
+}
@@ -0,0 +1,361 @@
+package raml
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/acronis/go-raml/stacktrace"
+)
+
+// InferDefaultEnumLimit is the default maximum number of distinct string values that are suggested as enum.
+const InferDefaultEnumLimit = 5
+
+// inferDateFormats lists string formats detected by the inferrer in the order of precedence.
+// RFC2616 date-times are declared as datetime type with rfc2616 format.
+// Values are matched with parseDateValue against the shape of the format.
+var inferDateFormats = []struct {
+	typ    string
+	format string
+	shape  Shape
+}{
+	{typ: TypeDatetime, shape: &DateTimeShape{}},
+	{typ: TypeDatetime, format: "rfc2616", shape: &DateTimeShape{FormatFacets: FormatFacets{Format: &inferRFC2616}}},
+	{typ: TypeDatetimeOnly, shape: &DateTimeOnlyShape{}},
+	{typ: TypeDateOnly, shape: &DateOnlyShape{}},
+	{typ: TypeTimeOnly, shape: &TimeOnlyShape{}},
+}
+
+var inferRFC2616 = "rfc2616"
+
+type TypeInferrerOpt interface {
+	Apply(*TypeInferrerOptions)
+}
+
+type optInferEnumLimit struct {
+	limit int
+}
+
+func (o optInferEnumLimit) Apply(e *TypeInferrerOptions) {
+	e.enumLimit = o.limit
+}
+
+// WithInferEnumLimit sets the maximum number of distinct string values that are suggested as enum.
+// Zero disables enum suggestions.
+func WithInferEnumLimit(limit int) TypeInferrerOpt {
+	return optInferEnumLimit{limit: limit}
+}
+
+type optInferDates struct {
+	enabled bool
+}
+
+func (o optInferDates) Apply(e *TypeInferrerOptions) {
+	e.dates = o.enabled
+}
+
+// WithInferDates enables or disables detection of date and time types in string values.
+func WithInferDates(enabled bool) TypeInferrerOpt {
+	return optInferDates{enabled: enabled}
+}
+
+type TypeInferrerOptions struct {
+	enumLimit int
+	dates     bool
+}
+
+// TypeInferrer infers RAML 1.0 types from sample documents.
+// Samples are merged: properties missing in some samples become optional, values of different kinds
+// become unions, strings with few distinct values are suggested as enums.
+type TypeInferrer struct {
+	opts TypeInferrerOptions
+
+	location string
+	types    *orderedmap.OrderedMap[string, *yaml.Node]
+	warnings []*stacktrace.StackTrace
+}
+
+func NewTypeInferrer(opts ...TypeInferrerOpt) *TypeInferrer {
+	i := &TypeInferrer{
+		opts: TypeInferrerOptions{
+			enumLimit: InferDefaultEnumLimit,
+			dates:     true,
+		},
+	}
+	for _, opt := range opts {
+		opt.Apply(&i.opts)
+	}
+	return i
+}
+
+// InferTypes infers a type with the given name from sample JSON or YAML documents.
+// The inferred types are converted to a RAML library located at the given location,
+// which is then parsed with the regular parser and given options.
+func InferTypes(ctx context.Context, samples [][]byte, location string, name string,
+	opts ...ParseOpt) (*RAML, []*stacktrace.StackTrace, error) {
+	if ctx == nil {
+		return nil, nil, fmt.Errorf("context is nil")
+	}
+	content, warnings, err := NewTypeInferrer().InferRAML(samples, location, name)
+	if err != nil {
+		return nil, warnings, err
+	}
+	rml := New(ctx)
+	err = rml.ParseFromString(string(content), filepath.Base(location), filepath.Dir(location), opts...)
+	return rml, warnings, err
+}
+
+// InferRAML infers a type with the given name from sample JSON or YAML documents
+// and returns RAML 1.0 Library definition that declares it.
+// Anonymous types that cannot be declared inline (union members) are declared as named types.
+func (i *TypeInferrer) InferRAML(samples [][]byte, location string, name string) ([]byte, []*stacktrace.StackTrace, error) {
+	i.location = location
+	i.types = orderedmap.New[string, *yaml.Node]()
+	i.warnings = nil
+
+	if len(samples) == 0 {
+		return nil, nil, stacktrace.New("no samples", location, stacktrace.WithType(stacktrace.TypeParsing))
+	}
+	root := &inferredType{}
+	for j, data := range samples {
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, nil, stacktrace.NewWrapped("unmarshal sample", err, location,
+				stacktrace.WithType(stacktrace.TypeParsing), stacktrace.WithInfo("sample", j))
+		}
+		if len(doc.Content) == 0 {
+			return nil, nil, stacktrace.New("sample is empty", location,
+				stacktrace.WithType(stacktrace.TypeParsing), stacktrace.WithInfo("sample", j))
+		}
+		i.observe(root, doc.Content[0])
+	}
+	name = reserveTypeName(i.types, name)
+	i.types.Set(name, i.declaration(root, name))
+
+	types := yamlMapNode()
+	for pair := i.types.Oldest(); pair != nil; pair = pair.Next() {
+		yamlMapSet(types, pair.Key, pair.Value)
+	}
+	out, err := yaml.Marshal(yamlMapNode("types", types))
+	if err != nil {
+		return nil, i.warnings, stacktrace.NewWrapped("marshal library", err, location, stacktrace.WithType(stacktrace.TypeConverting))
+	}
+	return append([]byte("#%RAML 1.0 Library\n"), out...), i.warnings, nil
+}
+
+// inferredType accumulates kinds of values observed at the same place in samples.
+type inferredType struct {
+	nulls    int
+	booleans int
+	integers int
+	numbers  int
+
+	strings int
+	// values holds distinct string values in order of appearance up to the enum limit.
+	values   []string
+	overflow bool
+	// formats counts string values by the index of the matching format in inferDateFormats.
+	formats map[int]int
+
+	objects    int
+	properties *orderedmap.OrderedMap[string, *inferredType]
+
+	arrays int
+	items  *inferredType
+}
+
+// count returns the number of observed values.
+func (t *inferredType) count() int {
+	return t.nulls + t.booleans + t.integers + t.numbers + t.strings + t.objects + t.arrays
+}
+
+// observe merges the value into the inferred type.
+func (i *TypeInferrer) observe(t *inferredType, node *yaml.Node) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		t.objects++
+		if t.properties == nil {
+			t.properties = orderedmap.New[string, *inferredType]()
+		}
+		for j := 0; j+1 < len(node.Content); j += 2 {
+			key := node.Content[j].Value
+			p, ok := t.properties.Get(key)
+			if !ok {
+				p = &inferredType{}
+				t.properties.Set(key, p)
+			}
+			i.observe(p, node.Content[j+1])
+		}
+	case yaml.SequenceNode:
+		t.arrays++
+		if t.items == nil {
+			t.items = &inferredType{}
+		}
+		for _, item := range node.Content {
+			i.observe(t.items, item)
+		}
+	case yaml.ScalarNode:
+		switch node.ShortTag() {
+		case "!!null":
+			t.nulls++
+		case "!!bool":
+			t.booleans++
+		case "!!int":
+			t.integers++
+		case "!!float":
+			t.numbers++
+		default:
+			i.observeString(t, node.Value)
+		}
+	}
+}
+
+func (i *TypeInferrer) observeString(t *inferredType, value string) {
+	t.strings++
+	if !t.overflow {
+		found := false
+		for _, v := range t.values {
+			if v == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			if len(t.values) < i.opts.enumLimit {
+				t.values = append(t.values, value)
+			} else {
+				t.overflow = true
+				t.values = nil
+			}
+		}
+	}
+	if i.opts.dates {
+		for j, f := range inferDateFormats {
+			if _, err := parseDateValue(f.shape, value); err == nil {
+				if t.formats == nil {
+					t.formats = make(map[int]int)
+				}
+				t.formats[j]++
+				break
+			}
+		}
+	}
+}
+
+// declaration returns RAML type declaration of the inferred type.
+// The returned node is either a type expression or a map of facets.
+func (i *TypeInferrer) declaration(t *inferredType, name string) *yaml.Node {
+	var members []*yaml.Node
+	if t.objects > 0 {
+		members = append(members, i.objectDeclaration(t, name))
+	}
+	if t.arrays > 0 {
+		members = append(members, i.arrayDeclaration(t, name))
+	}
+	if t.strings > 0 {
+		members = append(members, i.stringDeclaration(t))
+	}
+	switch {
+	case t.numbers > 0:
+		members = append(members, yamlStrNode(TypeNumber))
+	case t.integers > 0:
+		members = append(members, yamlStrNode(TypeInteger))
+	}
+	if t.booleans > 0 {
+		members = append(members, yamlStrNode(TypeBoolean))
+	}
+	if t.nulls > 0 {
+		members = append(members, yamlStrNode(TypeNil))
+	}
+	switch len(members) {
+	case 0:
+		return yamlStrNode(TypeAny)
+	case 1:
+		return members[0]
+	}
+	expr := make([]string, len(members))
+	for j, m := range members {
+		kind := m.Value
+		if m.Kind == yaml.MappingNode {
+			kind = yamlNodeValue(yamlMapGet(m, "type"))
+		}
+		expr[j] = groupTypeExpression(hoistDeclaration(i.types, m, name+"_"+kind))
+	}
+	return yamlStrNode(strings.Join(expr, " | "))
+}
+
+func (i *TypeInferrer) objectDeclaration(t *inferredType, name string) *yaml.Node {
+	decl := yamlMapNode("type", yamlStrNode(TypeObject))
+	properties := yamlMapNode()
+	for pair := t.properties.Oldest(); pair != nil; pair = pair.Next() {
+		key := pair.Key
+		if strings.HasPrefix(key, "/") && strings.HasSuffix(key, "/") {
+			i.warn("property name is reserved for pattern properties and is skipped", stacktrace.WithInfo("property", key))
+			continue
+		}
+		propDecl := i.declaration(pair.Value, name+"_"+invalidTypeNameChars.ReplaceAllString(key, "_"))
+		required := pair.Value.count() >= t.objects
+		// Explicit "required" facet keeps the trailing "?" as part of the property name.
+		if !required || strings.HasSuffix(key, "?") {
+			if propDecl.Kind != yaml.MappingNode {
+				propDecl = yamlMapNode("type", propDecl)
+			}
+			yamlMapSet(propDecl, "required", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(required)})
+		}
+		yamlMapSet(properties, key, propDecl)
+	}
+	if len(properties.Content) > 0 {
+		yamlMapSet(decl, "properties", properties)
+	}
+	return compactDeclaration(decl)
+}
+
+func (i *TypeInferrer) arrayDeclaration(t *inferredType, name string) *yaml.Node {
+	if t.items.count() == 0 {
+		// Only empty arrays were observed.
+		return yamlStrNode(TypeArray)
+	}
+	items := i.declaration(t.items, name+"_item")
+	// Parenthesized type expressions cannot be used as array items, so unions are declared with items facet.
+	if items.Kind == yaml.ScalarNode && !strings.ContainsAny(items.Value, "| ") {
+		return yamlStrNode(items.Value + "[]")
+	}
+	return yamlMapNode("type", yamlStrNode(TypeArray), "items", items)
+}
+
+func (i *TypeInferrer) stringDeclaration(t *inferredType) *yaml.Node {
+	for j, f := range inferDateFormats {
+		if t.formats[j] != t.strings {
+			continue
+		}
+		if f.format == "" {
+			return yamlStrNode(f.typ)
+		}
+		return yamlMapNode("type", yamlStrNode(f.typ), "format", yamlStrNode(f.format))
+	}
+	// Enum is suggested only if some values repeat, otherwise there is no evidence of low cardinality.
+	if !t.overflow && len(t.values) > 0 && len(t.values) < t.strings {
+		enum := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, v := range t.values {
+			enum.Content = append(enum.Content, yamlStrNode(v))
+		}
+		return yamlMapNode("type", yamlStrNode(TypeString), "enum", enum)
+	}
+	return yamlStrNode(TypeString)
+}
+
+func (i *TypeInferrer) warn(message string, opts ...stacktrace.Option) {
+	opts = append(opts,
+		stacktrace.WithSeverity(stacktrace.SeverityWarning),
+		stacktrace.WithType(stacktrace.TypeConverting),
+	)
+	i.warnings = append(i.warnings, stacktrace.New(message, i.location, opts...))
+}
@@ -0,0 +1,165 @@
+package raml
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypeInferrer_InferRAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []string
+		opts    []TypeInferrerOpt
+		want    string
+	}{
+		{
+			name:    "optional property",
+			samples: []string{`{"id": 1, "name": "a"}`, `{"id": 2}`},
+			want: `#%RAML 1.0 Library
+types:
+    Sample:
+        type: object
+        properties:
+            id: integer
+            name:
+                type: string
+                required: false
+`,
+		},
+		{
+			name:    "integer and number",
+			samples: []string{`[1, 2.5]`},
+			want: `#%RAML 1.0 Library
+types:
+    Sample: number[]
+`,
+		},
+		{
+			name:    "union members",
+			samples: []string{`{"v": "a"}`, `{"v": true}`, `{"v": null}`},
+			want: `#%RAML 1.0 Library
+types:
+    Sample:
+        type: object
+        properties:
+            v: string | boolean | nil
+`,
+		},
+		{
+			name:    "enum",
+			samples: []string{`["red", "green", "red"]`},
+			want: `#%RAML 1.0 Library
+types:
+    Sample:
+        type: array
+        items:
+            type: string
+            enum:
+                - red
+                - green
+`,
+		},
+		{
+			name:    "enum limit",
+			samples: []string{`["red", "green", "red"]`},
+			opts:    []TypeInferrerOpt{WithInferEnumLimit(1)},
+			want: `#%RAML 1.0 Library
+types:
+    Sample: string[]
+`,
+		},
+		{
+			name:    "dates",
+			samples: []string{`{"at": "2024-01-02T03:04:05Z", "day": "2024-01-02", "local": "2024-01-02T03:04:05"}`},
+			want: `#%RAML 1.0 Library
+types:
+    Sample:
+        type: object
+        properties:
+            at: datetime
+            day: date-only
+            local: datetime-only
+`,
+		},
+		{
+			name:    "dates by grammar",
+			samples: []string{`{"clock": "03:04:05.5", "modified": "Tue, 02 Jan 2024 03:04:05 GMT", "wrong": "Mon, 02 Jan 2024 03:04:05 GMT"}`},
+			want: `#%RAML 1.0 Library
+types:
+    Sample:
+        type: object
+        properties:
+            clock: time-only
+            modified:
+                type: datetime
+                format: rfc2616
+            wrong: string
+`,
+		},
+		{
+			name:    "dates disabled",
+			samples: []string{`{"day": "2024-01-02"}`},
+			opts:    []TypeInferrerOpt{WithInferDates(false)},
+			want: `#%RAML 1.0 Library
+types:
+    Sample:
+        type: object
+        properties:
+            day: string
+`,
+		},
+		{
+			name:    "empty array",
+			samples: []string{`{"tags": []}`},
+			want: `#%RAML 1.0 Library
+types:
+    Sample:
+        type: object
+        properties:
+            tags: array
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			samples := make([][]byte, len(tt.samples))
+			for i, s := range tt.samples {
+				samples[i] = []byte(s)
+			}
+			got, warnings, err := NewTypeInferrer(tt.opts...).InferRAML(samples, "sample.raml", "Sample")
+			require.NoError(t, err)
+			require.Empty(t, warnings)
+			require.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestTypeInferrer_InferRAML_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples [][]byte
+	}{
+		{name: "no samples"},
+		{name: "empty sample", samples: [][]byte{[]byte("")}},
+		{name: "malformed sample", samples: [][]byte{[]byte("{")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := NewTypeInferrer().InferRAML(tt.samples, "sample.raml", "Sample")
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestInferTypes(t *testing.T) {
+	samples := [][]byte{[]byte(`{"id": 1, "tags": ["a"]}`), []byte(`{"id": 2, "tags": []}`)}
+	rml, warnings, err := InferTypes(context.Background(), samples, t.TempDir()+"/sample.raml", "Sample",
+		OptWithUnwrap(), OptWithValidate())
+	require.NoError(t, err)
+	require.Empty(t, warnings)
+	s := libraryType(t, rml, "Sample")
+	require.NoError(t, s.Validate(map[string]interface{}{"id": 3, "tags": []interface{}{"b"}}, "$"))
+	require.Error(t, s.Validate(map[string]interface{}{"tags": []interface{}{}}, "$"))
+}
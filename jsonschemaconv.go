@@ -3,6 +3,7 @@ package raml
 import (
 	"encoding/json"
 	"strconv"
+	"strings"
 
 	orderedmap "github.com/wk8/go-ordered-map/v2"
 )
@@ -23,8 +24,97 @@ func WithOmitRefs(omitRefs bool) JSONSchemaConverterOpt {
 	return optOmitRefs{omitRefs: omitRefs}
 }
 
+// ExtensionKind identifies the origin of a JSON Schema extension keyword.
+type ExtensionKind int
+
+const (
+	// ExtensionAnnotation is an annotation applied to a shape, e.g. (deprecated).
+	ExtensionAnnotation ExtensionKind = iota
+	// ExtensionFacetDefinitions is a set of custom facets declared with "facets".
+	ExtensionFacetDefinitions
+	// ExtensionFacet is a value of a custom facet.
+	ExtensionFacet
+)
+
+// ExtensionNamer returns the keyword name for an annotation or custom facet.
+// For ExtensionFacetDefinitions name is empty.
+type ExtensionNamer func(kind ExtensionKind, name string) string
+
+// DefaultExtensionNamer produces x-domainExt-*, x-shapeExt-definitions and x-shapeExt-data-* keywords.
+func DefaultExtensionNamer(kind ExtensionKind, name string) string {
+	switch kind {
+	case ExtensionAnnotation:
+		return "x-domainExt-" + name
+	case ExtensionFacetDefinitions:
+		return "x-shapeExt-definitions"
+	default:
+		return "x-shapeExt-data-" + name
+	}
+}
+
+// AnnotationMapper maps an annotation to standard JSON Schema keywords.
+// It returns true if the annotation was consumed and must not be emitted as an extension keyword.
+type AnnotationMapper func(schema *JSONSchema, name string, value any) bool
+
+// StandardAnnotationMapper maps (deprecated), (readOnly) and (writeOnly) annotations
+// to the corresponding JSON Schema keywords. Annotations from libraries are matched by their local name.
+// Only boolean and empty annotation values are mapped.
+func StandardAnnotationMapper(schema *JSONSchema, name string, value any) bool {
+	if i := strings.LastIndexByte(name, '.'); i != -1 {
+		name = name[i+1:]
+	}
+	var flag bool
+	switch v := value.(type) {
+	case nil:
+		flag = true
+	case bool:
+		flag = v
+	default:
+		return false
+	}
+	switch name {
+	case "deprecated":
+		schema.Deprecated = flag
+	case "readOnly":
+		schema.ReadOnly = flag
+	case "writeOnly":
+		schema.WriteOnly = flag
+	default:
+		return false
+	}
+	return true
+}
+
+type optExtensionNamer struct {
+	namer ExtensionNamer
+}
+
+func (o optExtensionNamer) Apply(e *JSONSchemaConverterOptions) {
+	e.extensionNamer = o.namer
+}
+
+// WithExtensionNamer sets the naming scheme for extension keywords.
+func WithExtensionNamer(namer ExtensionNamer) JSONSchemaConverterOpt {
+	return optExtensionNamer{namer: namer}
+}
+
+type optAnnotationMapper struct {
+	mapper AnnotationMapper
+}
+
+func (o optAnnotationMapper) Apply(e *JSONSchemaConverterOptions) {
+	e.annotationMapper = o.mapper
+}
+
+// WithAnnotationMapper sets the callback that maps annotations to standard keywords.
+func WithAnnotationMapper(mapper AnnotationMapper) JSONSchemaConverterOpt {
+	return optAnnotationMapper{mapper: mapper}
+}
+
 type JSONSchemaConverterOptions struct {
-	omitRefs bool
+	omitRefs         bool
+	extensionNamer   ExtensionNamer
+	annotationMapper AnnotationMapper
 }
 
 type JSONSchemaConverter struct {
@@ -33,11 +123,20 @@ type JSONSchemaConverter struct {
 	definitions    Definitions
 	complexSchemas map[string]*JSONSchema
 
+	// refs maps shape IDs to references that replace inline schemas.
+	refs map[string]string
+	// hook is invoked for every inline schema produced from a shape.
+	hook func(s Shape, schema *JSONSchema)
+
 	opts JSONSchemaConverterOptions
 }
 
 func NewJSONSchemaConverter(opts ...JSONSchemaConverterOpt) *JSONSchemaConverter {
-	c := &JSONSchemaConverter{}
+	c := &JSONSchemaConverter{
+		opts: JSONSchemaConverterOptions{
+			extensionNamer: DefaultExtensionNamer,
+		},
+	}
 	for _, opt := range opts {
 		opt.Apply(&c.opts)
 	}
@@ -58,6 +157,17 @@ func (c *JSONSchemaConverter) Convert(s Shape) *JSONSchema {
 }
 
 func (c *JSONSchemaConverter) Visit(s Shape) *JSONSchema {
+	if ref, ok := c.refs[s.Base().Id]; ok {
+		return &JSONSchema{Ref: ref}
+	}
+	schema := c.visit(s)
+	if c.hook != nil && schema != nil {
+		c.hook(s, schema)
+	}
+	return schema
+}
+
+func (c *JSONSchemaConverter) visit(s Shape) *JSONSchema {
 	// TODO: Detect recursion
 	// if !s.Base().IsUnwrapped() {
 	// 	link := s.Base().Link
@@ -108,6 +218,8 @@ func (c *JSONSchemaConverter) Visit(s Shape) *JSONSchema {
 		return c.VisitTimeOnlyShape(s)
 	case *JSONShape:
 		return c.VisitJSONShape(s)
+	case *XMLShape:
+		return c.VisitXMLShape(s)
 	case *RecursiveShape:
 		return c.VisitRecursiveShape(s)
 	default:
@@ -324,6 +436,14 @@ func (c *JSONSchemaConverter) VisitJSONShape(s *JSONShape) *JSONSchema {
 	return schema
 }
 
+// VisitXMLShape returns a string schema since XML documents are carried as strings.
+func (c *JSONSchemaConverter) VisitXMLShape(s *XMLShape) *JSONSchema {
+	schema := c.makeSchemaFromBaseShape(s.Base())
+	schema.Type = "string"
+	schema.ContentMediaType = "application/xml"
+	return schema
+}
+
 func (c *JSONSchemaConverter) overrideSchema(parent *JSONSchema, child *JSONSchema) *JSONSchema {
 	cs := *child
 	if parent.Title != "" {
@@ -338,6 +458,15 @@ func (c *JSONSchemaConverter) overrideSchema(parent *JSONSchema, child *JSONSche
 	if parent.Examples != nil {
 		cs.Examples = parent.Examples
 	}
+	if parent.ReadOnly {
+		cs.ReadOnly = true
+	}
+	if parent.WriteOnly {
+		cs.WriteOnly = true
+	}
+	if parent.Deprecated {
+		cs.Deprecated = true
+	}
 	if parent.Extras != nil {
 		if cs.Extras == nil {
 			cs.Extras = parent.Extras
@@ -374,21 +503,30 @@ func (c *JSONSchemaConverter) makeSchemaFromBaseShape(base *BaseShape) *JSONSche
 	}
 	for pair := base.CustomDomainProperties.Oldest(); pair != nil; pair = pair.Next() {
 		k, v := pair.Key, pair.Value
-		schema.Extras["x-domainExt-"+k] = v.Extension.Value
+		if c.opts.annotationMapper != nil && c.opts.annotationMapper(schema, k, v.Extension.Value) {
+			continue
+		}
+		c.setExtra(schema, c.opts.extensionNamer(ExtensionAnnotation, k), v.Extension.Value)
 	}
-	for pair := base.CustomShapeFacetDefinitions.Oldest(); pair != nil; pair = pair.Next() {
-		k, v := pair.Key, pair.Value
-		m := schema.Extras["x-shapeExt-definitions"]
-		if m == nil {
-			m = make(map[string]interface{})
-			schema.Extras["x-shapeExt-definitions"] = m
+	if base.CustomShapeFacetDefinitions.Len() > 0 {
+		shapeExtDefs := make(map[string]interface{}, base.CustomShapeFacetDefinitions.Len())
+		for pair := base.CustomShapeFacetDefinitions.Oldest(); pair != nil; pair = pair.Next() {
+			k, v := pair.Key, pair.Value
+			shapeExtDefs[k] = c.Visit(*v.Shape)
 		}
-		shapeExtDefs := m.(map[string]interface{})
-		shapeExtDefs[k] = c.Visit(*v.Shape)
+		c.setExtra(schema, c.opts.extensionNamer(ExtensionFacetDefinitions, ""), shapeExtDefs)
 	}
 	for pair := base.CustomShapeFacets.Oldest(); pair != nil; pair = pair.Next() {
 		k, v := pair.Key, pair.Value
-		schema.Extras["x-shapeExt-data-"+k] = v.Value
+		c.setExtra(schema, c.opts.extensionNamer(ExtensionFacet, k), v.Value)
 	}
 	return schema
 }
+
+// setExtra sets an extension keyword. Empty names produced by the namer are skipped.
+func (c *JSONSchemaConverter) setExtra(schema *JSONSchema, name string, value any) {
+	if name == "" {
+		return
+	}
+	schema.Extras[name] = value
+}
@@ -0,0 +1,127 @@
+package raml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const extensionsLibrary = `#%RAML 1.0 Library
+annotationTypes:
+  deprecated: nil
+  readOnly: boolean
+  owner: string
+types:
+  Parent:
+    type: string
+    facets:
+      region: string
+  Child:
+    type: Parent
+    region: eu
+    (deprecated):
+    (readOnly): true
+    (owner): team
+`
+
+func TestJSONSchemaConverter_Extensions(t *testing.T) {
+	rml := parseLibrary(t, extensionsLibrary)
+	customNamer := func(kind ExtensionKind, name string) string {
+		switch kind {
+		case ExtensionAnnotation:
+			return "x-annotation-" + name
+		case ExtensionFacet:
+			return "x-facet-" + name
+		default:
+			return ""
+		}
+	}
+	tests := []struct {
+		name       string
+		typeName   string
+		opts       []JSONSchemaConverterOpt
+		wantExtras map[string]any
+		wantFlags  [2]bool
+	}{
+		{
+			name:     "default namer",
+			typeName: "Child",
+			wantExtras: map[string]any{
+				"x-domainExt-deprecated": nil,
+				"x-domainExt-readOnly":   true,
+				"x-domainExt-owner":      "team",
+				"x-shapeExt-data-region": "eu",
+			},
+		},
+		{
+			name:     "custom namer",
+			typeName: "Child",
+			opts:     []JSONSchemaConverterOpt{WithExtensionNamer(customNamer)},
+			wantExtras: map[string]any{
+				"x-annotation-deprecated": nil,
+				"x-annotation-readOnly":   true,
+				"x-annotation-owner":      "team",
+				"x-facet-region":          "eu",
+			},
+		},
+		{
+			name:     "standard annotation mapper",
+			typeName: "Child",
+			opts:     []JSONSchemaConverterOpt{WithAnnotationMapper(StandardAnnotationMapper)},
+			wantExtras: map[string]any{
+				"x-domainExt-owner":      "team",
+				"x-shapeExt-data-region": "eu",
+			},
+			wantFlags: [2]bool{true, true},
+		},
+		{
+			name:       "facet definitions skipped by namer",
+			typeName:   "Parent",
+			opts:       []JSONSchemaConverterOpt{WithExtensionNamer(customNamer)},
+			wantExtras: map[string]any{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conv := NewJSONSchemaConverter(tt.opts...)
+			schema := conv.Convert(libraryType(t, rml, tt.typeName))
+			got := schema.Definitions[tt.typeName]
+			require.NotNil(t, got)
+			require.Equal(t, tt.wantExtras, got.Extras)
+			require.Equal(t, tt.wantFlags, [2]bool{got.Deprecated, got.ReadOnly})
+		})
+	}
+}
+
+func TestJSONSchemaConverter_FacetDefinitions(t *testing.T) {
+	rml := parseLibrary(t, extensionsLibrary)
+	schema := NewJSONSchemaConverter().Convert(libraryType(t, rml, "Parent"))
+	defs, ok := schema.Definitions["Parent"].Extras["x-shapeExt-definitions"].(map[string]interface{})
+	require.True(t, ok)
+	region, ok := defs["region"].(*JSONSchema)
+	require.True(t, ok)
+	require.Equal(t, "string", region.Type)
+}
+
+func TestStandardAnnotationMapper(t *testing.T) {
+	tests := []struct {
+		name     string
+		annName  string
+		value    any
+		want     bool
+		wantFlag bool
+	}{
+		{name: "nil value", annName: "deprecated", value: nil, want: true, wantFlag: true},
+		{name: "false value", annName: "deprecated", value: false, want: true, wantFlag: false},
+		{name: "library annotation", annName: "lib.deprecated", value: true, want: true, wantFlag: true},
+		{name: "non-boolean value", annName: "deprecated", value: "yes", want: false},
+		{name: "unknown annotation", annName: "owner", value: true, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := &JSONSchema{}
+			require.Equal(t, tt.want, StandardAnnotationMapper(schema, tt.annName, tt.value))
+			require.Equal(t, tt.wantFlag, schema.Deprecated)
+		})
+	}
+}
@@ -75,3 +75,25 @@ func printMemUsage(t *testing.T) {
 	t.Logf("\tSys = %v MiB", m.Sys/1024/1024)
 	t.Logf("\tNumGC = %v\n", m.NumGC)
 }
+
+// parseLibrary parses the library content with unwrapping and validation enabled.
+func parseLibrary(t *testing.T, content string, opts ...ParseOpt) *RAML {
+	t.Helper()
+	opts = append([]ParseOpt{OptWithUnwrap(), OptWithValidate()}, opts...)
+	rml, err := ParseFromString(content, "library.raml", t.TempDir(), opts...)
+	if vErr, ok := stacktrace.Unwrap(err); ok {
+		t.Logf("ParseFromString error:\n%s", vErr.Sprint())
+	}
+	require.NoError(t, err)
+	return rml
+}
+
+// libraryType returns the named type of the parsed library.
+func libraryType(t *testing.T, rml *RAML, name string) Shape {
+	t.Helper()
+	lib, ok := rml.EntryPoint().(*Library)
+	require.True(t, ok, "entry point must be a library")
+	s, ok := lib.Types.Get(name)
+	require.True(t, ok, "type %s must be defined", name)
+	return *s
+}
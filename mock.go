@@ -0,0 +1,1044 @@
+package raml
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"regexp/syntax"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	// MockDefaultMaxDepth is the default depth of nested objects and arrays after which
+	// optional properties are omitted and arrays are generated with the least number of items.
+	MockDefaultMaxDepth = 3
+
+	// mockAttempts is the number of attempts to generate a valid value.
+	mockAttempts = 20
+	// mockMaxRepeat limits unbounded repetitions in patterns.
+	mockMaxRepeat = 8
+	// mockRange is the width of the range of numbers that are bounded from one side only.
+	mockRange = 1000
+)
+
+var mockWords = []string{
+	"alpha", "bravo", "cloud", "delta", "echo", "falcon", "green", "harbor", "island", "jade",
+	"kite", "lemon", "maple", "north", "ocean", "pixel", "quartz", "river", "stone", "tiger",
+	"urban", "violet", "willow", "xenon", "yellow", "zephyr",
+}
+
+type MockGeneratorOpt interface {
+	Apply(*MockGeneratorOptions)
+}
+
+type optMockSeed struct {
+	seed int64
+}
+
+func (o optMockSeed) Apply(e *MockGeneratorOptions) {
+	e.seed = o.seed
+}
+
+// WithMockSeed sets the seed of the random generator. Generators with the same seed produce the same data.
+func WithMockSeed(seed int64) MockGeneratorOpt {
+	return optMockSeed{seed: seed}
+}
+
+type optMockMaxDepth struct {
+	depth int
+}
+
+func (o optMockMaxDepth) Apply(e *MockGeneratorOptions) {
+	e.maxDepth = o.depth
+}
+
+// WithMockMaxDepth sets the depth of nested objects and arrays after which only required data is generated.
+// It limits recursion of recursive shapes.
+func WithMockMaxDepth(depth int) MockGeneratorOpt {
+	return optMockMaxDepth{depth: depth}
+}
+
+type MockGeneratorOptions struct {
+	seed     int64
+	maxDepth int
+}
+
+// MockGenerator generates random data that conforms to shapes.
+type MockGenerator struct {
+	opts MockGeneratorOptions
+
+	rand  *rand.Rand
+	depth int
+}
+
+func NewMockGenerator(opts ...MockGeneratorOpt) *MockGenerator {
+	g := &MockGenerator{
+		opts: MockGeneratorOptions{maxDepth: MockDefaultMaxDepth},
+	}
+	for _, opt := range opts {
+		opt.Apply(&g.opts)
+	}
+	g.rand = rand.New(rand.NewSource(g.opts.seed))
+	return g
+}
+
+// Generate generates random data for the unwrapped shape with the given seed.
+func Generate(s Shape, seed int64) (interface{}, error) {
+	return NewMockGenerator(WithMockSeed(seed)).Generate(s)
+}
+
+// Generate generates random data for the unwrapped shape. The data is represented the same way as
+// values accepted by Shape.Validate and is always valid against the shape.
+// An error is returned if the shape constraints cannot be satisfied.
+func (g *MockGenerator) Generate(s Shape) (interface{}, error) {
+	var err error
+	for i := 0; i < mockAttempts; i++ {
+		g.depth = 0
+		var v interface{}
+		v, err = g.generate(s)
+		if err != nil {
+			continue
+		}
+		if err = s.Validate(v, "$"); err == nil {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("generate %s: %w", s.Base().Name, err)
+}
+
+func (g *MockGenerator) generate(s Shape) (interface{}, error) {
+	switch s := s.(type) {
+	case *ObjectShape:
+		return g.object(s)
+	case *ArrayShape:
+		return g.array(s)
+	case *StringShape:
+		return g.string(s)
+	case *IntegerShape:
+		return g.integer(s)
+	case *NumberShape:
+		return g.number(s)
+	case *BooleanShape:
+		if s.Enum != nil {
+			return g.enum(s.Enum), nil
+		}
+		return g.rand.Intn(2) == 1, nil
+	case *FileShape:
+		return g.file(s)
+	case *DateTimeShape:
+		t := g.time()
+		if s.Format != nil && *s.Format == "rfc2616" {
+			return t.Format(RFC2616), nil
+		}
+		return t.Format(time.RFC3339), nil
+	case *DateTimeOnlyShape:
+		return g.time().Format(DateTime), nil
+	case *DateOnlyShape:
+		return g.time().Format(time.DateOnly), nil
+	case *TimeOnlyShape:
+		return g.time().Format(time.TimeOnly), nil
+	case *UnionShape:
+		return g.union(s)
+	case *RecursiveShape:
+		return g.generate(*s.Head)
+	case *JSONShape:
+		return map[string]interface{}{}, nil
+	case *XMLShape:
+		return g.xml(s)
+	case *AnyShape:
+		return g.word(), nil
+	case *NilShape:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("cannot generate data for shape of type %s", s.Base().Type)
+}
+
+// limited reports whether the depth limit is reached and only required data must be generated.
+func (g *MockGenerator) limited() bool {
+	return g.depth >= g.opts.maxDepth
+}
+
+// enter increases the depth of nested data and fails when recursion cannot be stopped.
+func (g *MockGenerator) enter() error {
+	g.depth++
+	if g.depth > g.opts.maxDepth*4+1 {
+		return fmt.Errorf("recursion depth limit exceeded")
+	}
+	return nil
+}
+
+func (g *MockGenerator) enum(enum Nodes) interface{} {
+	return enum[g.rand.Intn(len(enum))].Value
+}
+
+func (g *MockGenerator) object(s *ObjectShape) (interface{}, error) {
+	if err := g.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { g.depth-- }()
+
+	v := make(map[string]interface{})
+	var optional []string
+	if s.Properties != nil {
+		for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+			prop := pair.Value
+			if s.Discriminator != nil && *s.Discriminator == prop.Name {
+				v[prop.Name] = s.discriminatorValue()
+				continue
+			}
+			if !prop.Required {
+				optional = append(optional, prop.Name)
+				if g.limited() || g.rand.Intn(2) == 0 {
+					continue
+				}
+			}
+			pv, err := g.generate(*prop.Shape)
+			if err != nil {
+				if !prop.Required {
+					continue
+				}
+				return nil, fmt.Errorf("property %s: %w", prop.Name, err)
+			}
+			v[prop.Name] = pv
+		}
+	}
+	if s.PatternProperties != nil && s.PatternProperties.Len() > 0 && !g.limited() {
+		for n := g.rand.Intn(3); n > 0; n-- {
+			g.patternProperty(s, v)
+		}
+	}
+
+	// Optional and pattern properties are added or removed to satisfy the number of properties.
+	if s.MinProperties != nil {
+		for _, name := range optional {
+			if uint64(len(v)) >= *s.MinProperties {
+				break
+			}
+			if _, ok := v[name]; ok {
+				continue
+			}
+			prop, _ := s.Properties.Get(name)
+			if pv, err := g.generate(*prop.Shape); err == nil {
+				v[name] = pv
+			}
+		}
+		for i := 0; uint64(len(v)) < *s.MinProperties && s.PatternProperties != nil && i < mockAttempts; i++ {
+			g.patternProperty(s, v)
+		}
+		if uint64(len(v)) < *s.MinProperties {
+			return nil, fmt.Errorf("cannot generate %d properties", *s.MinProperties)
+		}
+	}
+	if s.MaxProperties != nil {
+		for i := len(optional) - 1; i >= 0 && uint64(len(v)) > *s.MaxProperties; i-- {
+			delete(v, optional[i])
+		}
+	}
+	return v, nil
+}
+
+// patternProperty adds a property with the key generated from a pattern property.
+func (g *MockGenerator) patternProperty(s *ObjectShape, v map[string]interface{}) {
+	pair := s.PatternProperties.Oldest()
+	for i := g.rand.Intn(s.PatternProperties.Len()); i > 0; i-- {
+		pair = pair.Next()
+	}
+	pp := pair.Value
+	key, err := g.pattern(pp.Pattern, 1, 20)
+	if err != nil {
+		return
+	}
+	if _, ok := v[key]; ok {
+		return
+	}
+	if s.Properties != nil {
+		if _, ok := s.Properties.Get(key); ok {
+			return
+		}
+	}
+	pv, err := g.generate(*pp.Shape)
+	if err != nil {
+		return
+	}
+	v[key] = pv
+}
+
+func (g *MockGenerator) array(s *ArrayShape) (interface{}, error) {
+	if err := g.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { g.depth-- }()
+
+	minItems, maxItems := g.bounds(s.MinItems, s.MaxItems, 3)
+	if minItems > maxItems {
+		return nil, fmt.Errorf("minItems is greater than maxItems")
+	}
+	n := minItems
+	if !g.limited() {
+		n += uint64(g.rand.Int63n(int64(maxItems - minItems + 1)))
+	}
+	items := make([]interface{}, 0, n)
+	if s.Items == nil {
+		for uint64(len(items)) < n {
+			items = append(items, g.word())
+		}
+		return items, nil
+	}
+	unique := s.UniqueItems != nil && *s.UniqueItems
+	seen := make(map[string]struct{})
+	for attempts := 0; uint64(len(items)) < n && attempts < int(n)*mockAttempts; attempts++ {
+		item, err := g.generate(*s.Items)
+		if err != nil {
+			return nil, fmt.Errorf("items: %w", err)
+		}
+		if unique {
+			key := valueKey(item)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+		}
+		items = append(items, item)
+	}
+	if uint64(len(items)) < minItems {
+		return nil, fmt.Errorf("cannot generate %d unique items", minItems)
+	}
+	return items, nil
+}
+
+// bounds returns the bounds of the length with the default width when the maximum is not set.
+func (g *MockGenerator) bounds(minimum *uint64, maximum *uint64, width uint64) (uint64, uint64) {
+	var lo uint64
+	if minimum != nil {
+		lo = *minimum
+	}
+	hi := lo + width
+	if maximum != nil && *maximum < hi {
+		hi = *maximum
+	}
+	return lo, hi
+}
+
+func (g *MockGenerator) union(s *UnionShape) (interface{}, error) {
+	members := make([]*Shape, len(s.AnyOf))
+	copy(members, s.AnyOf)
+	g.rand.Shuffle(len(members), func(i, j int) {
+		members[i], members[j] = members[j], members[i]
+	})
+	// Scalar members are preferred at the depth limit to stop recursion.
+	if g.limited() {
+		scalars := members[:0:0]
+		var rest []*Shape
+		for _, m := range members {
+			switch (*m).(type) {
+			case *ObjectShape, *ArrayShape, *RecursiveShape, *UnionShape:
+				rest = append(rest, m)
+			default:
+				scalars = append(scalars, m)
+			}
+		}
+		members = append(scalars, rest...)
+	}
+	var err error
+	for _, m := range members {
+		var v interface{}
+		v, err = g.generate(*m)
+		if err == nil && (*m).Validate(v, "$") == nil {
+			return v, nil
+		}
+	}
+	if err == nil {
+		err = fmt.Errorf("no member is generated")
+	}
+	return nil, fmt.Errorf("union: %w", err)
+}
+
+func (g *MockGenerator) word() string {
+	return mockWords[g.rand.Intn(len(mockWords))]
+}
+
+func (g *MockGenerator) string(s *StringShape) (interface{}, error) {
+	if s.Enum != nil {
+		return g.enum(s.Enum), nil
+	}
+	minLength, maxLength := g.bounds(s.MinLength, s.MaxLength, 16)
+	if minLength > maxLength {
+		return nil, fmt.Errorf("minLength is greater than maxLength")
+	}
+	if s.Pattern != nil {
+		return g.pattern(s.Pattern, minLength, maxLength)
+	}
+	return g.text(minLength, maxLength), nil
+}
+
+// text returns words separated by spaces with the length in the given bounds.
+func (g *MockGenerator) text(minLength uint64, maxLength uint64) string {
+	target := minLength
+	if maxLength > minLength {
+		target += uint64(g.rand.Int63n(int64(maxLength - minLength + 1)))
+	}
+	var b strings.Builder
+	for uint64(b.Len()) < target {
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(g.word())
+	}
+	text := strings.TrimRight(b.String()[:target], " ")
+	for uint64(len(text)) < target {
+		text += string(rune('a' + g.rand.Intn(26)))
+	}
+	return text
+}
+
+// pattern returns a string that matches the pattern and has the length in the given bounds.
+func (g *MockGenerator) pattern(re Regexp, minLength uint64, maxLength uint64) (string, error) {
+	expr, err := mockPatternExpr(re.String())
+	if err != nil {
+		return "", fmt.Errorf("pattern %s is not supported: %w", re.String(), err)
+	}
+	tree, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return "", fmt.Errorf("pattern %s is not supported: %w", re.String(), err)
+	}
+	tree = tree.Simplify()
+	for i := 0; i < mockAttempts*5; i++ {
+		var b strings.Builder
+		// Repetitions are widened gradually to reach the minimum length.
+		g.regexp(&b, tree, mockMaxRepeat+i)
+		v := b.String()
+		// Unanchored patterns may be padded to reach the minimum length.
+		for uint64(len(v)) < minLength && re.MatchString(v+"a") {
+			v += "a"
+		}
+		if uint64(len(v)) >= minLength && uint64(len(v)) <= maxLength && re.MatchString(v) {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("cannot generate string matching pattern %s", re.String())
+}
+
+// mockPatternExpr converts the ECMAScript pattern to the syntax of regexp/syntax to generate candidates from.
+// Lookarounds are removed, so candidates are generated from the rest of the pattern and filtered
+// by the pattern itself. Backreferences cannot be generated.
+func mockPatternExpr(expr string) (string, error) {
+	var b strings.Builder
+	inClass := false
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		switch {
+		case c == '\\' && i+1 < len(expr):
+			next := expr[i+1]
+			switch {
+			case next >= '1' && next <= '9', next == 'k':
+				return "", fmt.Errorf("backreferences are not supported")
+			case next == 'u' && i+6 <= len(expr):
+				// ECMAScript "\uXXXX" is "\x{XXXX}" in Go.
+				b.WriteString(`\x{` + expr[i+2:i+6] + "}")
+				i += 5
+				continue
+			}
+			b.WriteString(expr[i : i+2])
+			i++
+		case inClass:
+			if c == ']' {
+				inClass = false
+			}
+			b.WriteByte(c)
+		case c == '[':
+			inClass = true
+			b.WriteByte(c)
+		case c == '(' && isLookaround(expr[i:]):
+			i = skipGroup(expr, i)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), nil
+}
+
+func isLookaround(expr string) bool {
+	for _, prefix := range []string{"(?=", "(?!", "(?<=", "(?<!"} {
+		if strings.HasPrefix(expr, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// skipGroup returns the index of the parenthesis that closes the group starting at i.
+func skipGroup(expr string, i int) int {
+	depth, inClass := 0, false
+	for ; i < len(expr); i++ {
+		switch c := expr[i]; {
+		case c == '\\':
+			i++
+		case inClass:
+			inClass = c != ']'
+		case c == '[':
+			inClass = true
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return i
+}
+
+func (g *MockGenerator) regexp(b *strings.Builder, re *syntax.Regexp, maxRepeat int) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			if re.Flags&syntax.FoldCase != 0 && g.rand.Intn(2) == 0 {
+				r = unicode.SimpleFold(r)
+			}
+			b.WriteRune(r)
+		}
+	case syntax.OpCharClass:
+		b.WriteRune(g.charClass(re.Rune))
+	case syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+		b.WriteRune(rune('a' + g.rand.Intn(26)))
+	case syntax.OpCapture:
+		g.regexp(b, re.Sub[0], maxRepeat)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			g.regexp(b, sub, maxRepeat)
+		}
+	case syntax.OpAlternate:
+		g.regexp(b, re.Sub[g.rand.Intn(len(re.Sub))], maxRepeat)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		lo, hi := re.Min, re.Max
+		switch re.Op {
+		case syntax.OpStar:
+			lo, hi = 0, -1
+		case syntax.OpPlus:
+			lo, hi = 1, -1
+		case syntax.OpQuest:
+			lo, hi = 0, 1
+		}
+		if hi < 0 || hi > lo+maxRepeat {
+			hi = lo + maxRepeat
+		}
+		for n := lo + g.rand.Intn(hi-lo+1); n > 0; n-- {
+			g.regexp(b, re.Sub[0], maxRepeat)
+		}
+	}
+}
+
+// charClass returns a random rune of the class given as pairs of ranges. Printable ASCII runes are preferred.
+func (g *MockGenerator) charClass(ranges []rune) rune {
+	var ascii []rune
+	for i := 0; i+1 < len(ranges); i += 2 {
+		lo, hi := max(ranges[i], ' '), min(ranges[i+1], '~')
+		if lo <= hi {
+			ascii = append(ascii, lo, hi)
+		}
+	}
+	if len(ascii) > 0 {
+		ranges = ascii
+	}
+	if len(ranges) == 0 {
+		return 'a'
+	}
+	i := g.rand.Intn(len(ranges)/2) * 2
+	lo, hi := ranges[i], ranges[i+1]
+	if hi > unicode.MaxRune {
+		hi = unicode.MaxRune
+	}
+	r := lo + rune(g.rand.Int63n(int64(hi-lo+1)))
+	if r >= 0xD800 && r <= 0xDFFF {
+		r = lo
+	}
+	return r
+}
+
+// integerFormatBounds returns the range of the integer format.
+func integerFormatBounds(format *string) (*big.Int, *big.Int) {
+	if format == nil {
+		return nil, nil
+	}
+	var bits uint
+	switch *format {
+	case "int8":
+		bits = 8
+	case "int16":
+		bits = 16
+	case "int32", "int":
+		bits = 32
+	case "int64", "long":
+		bits = 64
+	default:
+		return nil, nil
+	}
+	hi := new(big.Int).Lsh(big.NewInt(1), bits-1)
+	lo := new(big.Int).Neg(hi)
+	return lo, hi.Sub(hi, big.NewInt(1))
+}
+
+// intRange returns the range of integers within the bounds and the format range. A missing bound
+// is set at a distance of mockRange from the other one.
+func intRange(minimum *big.Int, maximum *big.Int, formatMin *big.Int, formatMax *big.Int) (*big.Int, *big.Int) {
+	lo, hi := minimum, maximum
+	switch {
+	case lo == nil && hi == nil:
+		lo, hi = big.NewInt(0), big.NewInt(mockRange)
+	case lo == nil:
+		lo = new(big.Int).Sub(hi, big.NewInt(mockRange))
+	case hi == nil:
+		hi = new(big.Int).Add(lo, big.NewInt(mockRange))
+	}
+	if formatMin != nil && lo.Cmp(formatMin) < 0 {
+		lo = formatMin
+	}
+	if formatMax != nil && hi.Cmp(formatMax) > 0 {
+		hi = formatMax
+	}
+	return lo, hi
+}
+
+func (g *MockGenerator) randomInt(lo *big.Int, hi *big.Int) *big.Int {
+	width := new(big.Int).Sub(hi, lo)
+	width.Add(width, big.NewInt(1))
+	return new(big.Int).Add(lo, new(big.Int).Rand(g.rand, width))
+}
+
+func (g *MockGenerator) integer(s *IntegerShape) (interface{}, error) {
+	if s.Enum != nil {
+		return g.enum(s.Enum), nil
+	}
+	formatMin, formatMax := integerFormatBounds(s.Format)
+	lo, hi := intRange(s.Minimum, s.Maximum, formatMin, formatMax)
+	if lo.Cmp(hi) > 0 {
+		return nil, fmt.Errorf("minimum is greater than maximum")
+	}
+	v := g.randomInt(lo, hi)
+	if s.MultipleOf != nil {
+		m, _ := new(big.Float).SetFloat64(*s.MultipleOf).Int(nil)
+		if *s.MultipleOf != math.Trunc(*s.MultipleOf) || m.Sign() <= 0 {
+			return nil, fmt.Errorf("multipleOf of integer must be a positive integer")
+		}
+		// Multiples of m within [lo, hi] are k*m for k in [ceil(lo/m), floor(hi/m)].
+		klo := new(big.Int).Neg(new(big.Int).Div(new(big.Int).Neg(lo), m))
+		khi := new(big.Int).Div(hi, m)
+		if klo.Cmp(khi) > 0 {
+			return nil, fmt.Errorf("no multiple of %v within bounds", *s.MultipleOf)
+		}
+		v = new(big.Int).Mul(g.randomInt(klo, khi), m)
+	}
+	if v.IsInt64() {
+		return int(v.Int64()), nil
+	}
+	if v.IsUint64() {
+		return uint(v.Uint64()), nil
+	}
+	return nil, fmt.Errorf("integer %s is out of range", v.String())
+}
+
+// ratFloor returns the greatest integer that is not greater than r.
+func ratFloor(r *big.Rat) *big.Int {
+	return new(big.Int).Div(r.Num(), r.Denom())
+}
+
+func (g *MockGenerator) number(s *NumberShape) (interface{}, error) {
+	if s.Enum != nil {
+		return g.enum(s.Enum), nil
+	}
+	if s.Format != nil {
+		if formatMin, _ := integerFormatBounds(s.Format); formatMin != nil {
+			var minimum, maximum *big.Int
+			if s.Minimum != nil {
+				minimum, _ = new(big.Float).SetFloat64(math.Ceil(*s.Minimum)).Int(nil)
+			}
+			if s.Maximum != nil {
+				maximum, _ = new(big.Float).SetFloat64(math.Floor(*s.Maximum)).Int(nil)
+			}
+			v, err := g.integer(&IntegerShape{
+				BaseShape: s.BaseShape, FormatFacets: s.FormatFacets,
+				IntegerFacets: IntegerFacets{Minimum: minimum, Maximum: maximum, MultipleOf: s.MultipleOf},
+			})
+			if err != nil {
+				return nil, err
+			}
+			return float64(v.(int)), nil
+		}
+	}
+	lo, hi := 0.0, float64(mockRange)
+	switch {
+	case s.Minimum != nil && s.Maximum != nil:
+		lo, hi = *s.Minimum, *s.Maximum
+	case s.Minimum != nil:
+		lo, hi = *s.Minimum, *s.Minimum+mockRange
+	case s.Maximum != nil:
+		lo, hi = *s.Maximum-mockRange, *s.Maximum
+	}
+	if lo > hi {
+		return nil, fmt.Errorf("minimum is greater than maximum")
+	}
+	if s.MultipleOf != nil {
+		m := *s.MultipleOf
+		if m <= 0 {
+			return nil, fmt.Errorf("multipleOf must be positive")
+		}
+		// Multiples are computed with decimal values of the bounds and multipleOf as Validate compares them,
+		// since k*m in float64 may be off the exact decimal multiple, e.g. 3*0.1.
+		mr, err := numericValue(m)
+		if err != nil {
+			return nil, err
+		}
+		lor, err := numericValue(lo)
+		if err != nil {
+			return nil, err
+		}
+		hir, err := numericValue(hi)
+		if err != nil {
+			return nil, err
+		}
+		klo := ratFloor(new(big.Rat).Neg(new(big.Rat).Quo(lor, mr)))
+		klo.Neg(klo)
+		khi := ratFloor(new(big.Rat).Quo(hir, mr))
+		if klo.Cmp(khi) > 0 {
+			return nil, fmt.Errorf("no multiple of %v within bounds", m)
+		}
+		v, _ := new(big.Rat).Mul(new(big.Rat).SetInt(g.randomInt(klo, khi)), mr).Float64()
+		return v, nil
+	}
+	v := lo + g.rand.Float64()*(hi-lo)
+	// Values are rounded to cents to look realistic, unless rounding breaks the bounds.
+	if r := math.Round(v*100) / 100; r >= lo && r <= hi {
+		v = r
+	}
+	if s.Format != nil && *s.Format == "float" {
+		v = float64(float32(v))
+	}
+	return v, nil
+}
+
+func (g *MockGenerator) file(s *FileShape) (interface{}, error) {
+	minLength, maxLength := g.bounds(s.MinLength, s.MaxLength, 64)
+	if minLength > maxLength {
+		return nil, fmt.Errorf("minLength is greater than maxLength")
+	}
+	// Lengths are sizes of the content that is base64 encoded.
+	length := minLength + uint64(g.rand.Int63n(int64(maxLength-minLength+1)))
+	var mediaType string
+	if len(s.FileTypes) > 0 {
+		mediaType, _ = s.FileTypes[g.rand.Intn(len(s.FileTypes))].Value.(string)
+	}
+	var data []byte
+	if isTextualFileType(mediaType) {
+		data = []byte(g.text(length, length))
+	} else {
+		data = make([]byte, length)
+		g.rand.Read(data)
+		// Content of media types recognized by magic numbers starts with the signature.
+		if sig := mockFileSignature(mediaType); sig != "" {
+			if uint64(len(sig)) > maxLength {
+				return nil, fmt.Errorf("maxLength is less than signature of file type %s", mediaType)
+			}
+			if uint64(len(data)) < uint64(len(sig)) {
+				data = make([]byte, len(sig))
+			}
+			copy(data, sig)
+		}
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// mockFileSignature returns the magic number of content of the media type.
+func mockFileSignature(mediaType string) string {
+	switch mediaType {
+	case "image/png", "image/*":
+		return "\x89PNG\r\n\x1a\n"
+	case "image/jpeg":
+		return "\xff\xd8\xff"
+	case "image/gif":
+		return "GIF89a"
+	case "application/pdf":
+		return "%PDF-"
+	case "application/zip":
+		return "PK\x03\x04"
+	case "application/gzip", "application/x-gzip":
+		return "\x1f\x8b\x08"
+	}
+	for _, sig := range fileSignatures {
+		if sig.mediaType == mediaType {
+			return sig.prefix
+		}
+	}
+	return ""
+}
+
+// time returns a random time between 2000 and 2030 with a precision of seconds.
+func (g *MockGenerator) time() time.Time {
+	start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	end := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	return time.Unix(start+g.rand.Int63n(end-start), 0).UTC()
+}
+
+// xml generates an XML document for a random global element of the schema.
+func (g *MockGenerator) xml(s *XMLShape) (interface{}, error) {
+	if s.Schema == nil || len(s.Schema.Elements) == 0 {
+		return nil, fmt.Errorf("xml schema has no global elements")
+	}
+	names := make([]string, 0, len(s.Schema.Elements))
+	for name := range s.Schema.Elements {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	x := &mockXML{g: g, schema: s.Schema}
+	e := s.Schema.Elements[names[g.rand.Intn(len(names))]]
+	if err := x.element(e, s.Schema.TargetNamespace); err != nil {
+		return nil, err
+	}
+	return x.b.String(), nil
+}
+
+// mockXML writes XML documents that conform to the schema.
+type mockXML struct {
+	g      *MockGenerator
+	schema *XSDSchema
+	b      strings.Builder
+}
+
+func (x *mockXML) element(e *xsdElement, namespace string) error {
+	if e.Ref.Local != "" {
+		ref, ok := x.schema.Elements[e.Ref.Local]
+		if !ok {
+			return fmt.Errorf("referenced element %s is not declared", e.Ref.Local)
+		}
+		e = ref
+	}
+	if err := x.g.enter(); err != nil {
+		return err
+	}
+	defer func() { x.g.depth-- }()
+
+	x.b.WriteString("<" + e.Name)
+	if namespace != "" {
+		x.b.WriteString(` xmlns="`)
+		_ = xml.EscapeText(&x.b, []byte(namespace))
+		x.b.WriteString(`"`)
+	}
+	var complexType *xsdComplexType
+	simpleType := e.SimpleType
+	switch {
+	case e.ComplexType != nil:
+		complexType = e.ComplexType
+	case e.SimpleType != nil:
+	case e.Type.Local == "" || e.Type.Space == XSDNamespace && e.Type.Local == "anyType":
+		x.b.WriteString("/>")
+		return nil
+	case e.Type.Space == XSDNamespace:
+		simpleType = &xsdSimpleType{Base: e.Type}
+	default:
+		if t, ok := x.schema.ComplexTypes[e.Type.Local]; ok {
+			complexType = t
+		} else if t, ok := x.schema.SimpleTypes[e.Type.Local]; ok {
+			simpleType = t
+		} else {
+			return fmt.Errorf("type %s is not declared", e.Type.Local)
+		}
+	}
+	if complexType != nil {
+		particle, attributes, _, simple := x.schema.flatten(complexType)
+		for _, a := range attributes {
+			if !a.Required && (x.g.limited() || x.g.rand.Intn(2) == 0) {
+				continue
+			}
+			t := a.SimpleType
+			if t == nil {
+				t = x.schema.simpleType(a.Type)
+			}
+			v, err := x.simple(t)
+			if err != nil {
+				return fmt.Errorf("attribute %s: %w", a.Name, err)
+			}
+			x.b.WriteString(" " + a.Name + `="`)
+			_ = xml.EscapeText(&x.b, []byte(v))
+			x.b.WriteString(`"`)
+		}
+		x.b.WriteString(">")
+		if complexType.SimpleContent && simple != nil {
+			simpleType = simple
+		} else if particle != nil {
+			if err := x.particle(particle); err != nil {
+				return err
+			}
+		}
+	} else {
+		x.b.WriteString(">")
+	}
+	if simpleType != nil {
+		v, err := x.simple(simpleType)
+		if err != nil {
+			return fmt.Errorf("element %s: %w", e.Name, err)
+		}
+		_ = xml.EscapeText(&x.b, []byte(v))
+	}
+	x.b.WriteString("</" + e.Name + ">")
+	return nil
+}
+
+func (x *mockXML) particle(p *xsdParticle) error {
+	n := p.MinOccurs
+	if !x.g.limited() {
+		hi := p.MaxOccurs
+		if hi < 0 || hi > p.MinOccurs+2 {
+			hi = p.MinOccurs + 2
+		}
+		n += x.g.rand.Intn(hi - p.MinOccurs + 1)
+	}
+	for ; n > 0; n-- {
+		switch p.Kind {
+		case xsdParticleElement:
+			if err := x.element(p.Element, ""); err != nil {
+				return err
+			}
+		case xsdParticleAny:
+			x.b.WriteString("<" + x.g.word() + "/>")
+		case xsdParticleChoice:
+			if len(p.Particles) > 0 {
+				if err := x.particle(p.Particles[x.g.rand.Intn(len(p.Particles))]); err != nil {
+					return err
+				}
+			}
+		case xsdParticleSequence, xsdParticleAll:
+			for _, child := range p.Particles {
+				if err := x.particle(child); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// simple returns a value of the simple type.
+func (x *mockXML) simple(t *xsdSimpleType) (string, error) {
+	for i := 0; i < mockAttempts; i++ {
+		v, err := x.candidate(t)
+		if err != nil {
+			return "", err
+		}
+		if x.schema.validateSimple(t, v) == nil {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("cannot generate value of simple type %s", t.Name)
+}
+
+// candidate returns a value of the simple type that may violate facets which are not generated for.
+func (x *mockXML) candidate(t *xsdSimpleType) (string, error) {
+	g := x.g
+	switch {
+	case len(t.Enumeration) > 0:
+		return t.Enumeration[g.rand.Intn(len(t.Enumeration))], nil
+	case len(t.Patterns) > 0:
+		minLength, maxLength := uint64(0), uint64(32)
+		if t.MinLength != nil {
+			minLength = uint64(*t.MinLength)
+		}
+		if t.MaxLength != nil {
+			maxLength = uint64(*t.MaxLength)
+		}
+		if t.Length != nil {
+			minLength, maxLength = uint64(*t.Length), uint64(*t.Length)
+		}
+		return g.pattern(t.Patterns[0], minLength, maxLength)
+	case t.ItemType != nil || t.ItemSimple != nil:
+		item := t.ItemSimple
+		if item == nil {
+			item = x.schema.simpleType(*t.ItemType)
+		}
+		return x.simple(item)
+	case len(t.MemberTypes) > 0 || len(t.Members) > 0:
+		members := append([]*xsdSimpleType{}, t.Members...)
+		for _, q := range t.MemberTypes {
+			members = append(members, x.schema.simpleType(q))
+		}
+		return x.simple(members[g.rand.Intn(len(members))])
+	case t.BaseType != nil:
+		return x.candidate(t.BaseType)
+	case t.Base.Local != "" && t.Base.Space != XSDNamespace:
+		if base, ok := x.schema.SimpleTypes[t.Base.Local]; ok {
+			return x.candidate(base)
+		}
+	}
+
+	lo, hi := 0.0, float64(mockRange)
+	if t.MinInclusive != nil {
+		lo = *t.MinInclusive
+	} else if t.MinExclusive != nil {
+		lo = *t.MinExclusive + 1
+	}
+	if t.MaxInclusive != nil {
+		hi = *t.MaxInclusive
+	} else if t.MaxExclusive != nil {
+		hi = *t.MaxExclusive - 1
+	}
+	if hi < lo {
+		hi = lo + mockRange
+	}
+	switch t.Base.Local {
+	case "boolean":
+		return fmt.Sprint(g.rand.Intn(2) == 1), nil
+	case "integer", "long", "int", "short", "byte", "nonNegativeInteger", "positiveInteger", "unsignedLong",
+		"unsignedInt", "unsignedShort", "unsignedByte":
+		if t.Base.Local == "positiveInteger" && lo < 1 {
+			lo = 1
+		}
+		if hi > math.MaxInt8 && (t.Base.Local == "byte" || t.Base.Local == "unsignedByte") {
+			hi = math.MaxInt8
+		}
+		ilo, ihi := math.Ceil(lo), math.Floor(hi)
+		if ilo > ihi {
+			return "", fmt.Errorf("no integer of simple type %s within bounds", t.Name)
+		}
+		return fmt.Sprint(int64(ilo) + g.rand.Int63n(int64(ihi-ilo)+1)), nil
+	case "nonPositiveInteger", "negativeInteger":
+		return fmt.Sprint(-1 - g.rand.Int63n(mockRange)), nil
+	case "decimal", "float", "double":
+		return fmt.Sprint(math.Round((lo+g.rand.Float64()*(hi-lo))*100) / 100), nil
+	case "date":
+		return g.time().Format(time.DateOnly), nil
+	case "time":
+		return g.time().Format(time.TimeOnly), nil
+	case "dateTime":
+		return g.time().Format(time.RFC3339), nil
+	case "base64Binary":
+		data := make([]byte, 3+g.rand.Intn(9))
+		g.rand.Read(data)
+		return base64.StdEncoding.EncodeToString(data), nil
+	case "hexBinary":
+		data := make([]byte, 1+g.rand.Intn(8))
+		g.rand.Read(data)
+		return hex.EncodeToString(data), nil
+	case "anyURI":
+		return "https://example.com/" + g.word(), nil
+	}
+	minLength, maxLength := uint64(1), uint64(16)
+	if t.MinLength != nil {
+		minLength = uint64(*t.MinLength)
+	}
+	if t.MaxLength != nil {
+		maxLength = uint64(*t.MaxLength)
+	}
+	if t.Length != nil {
+		minLength, maxLength = uint64(*t.Length), uint64(*t.Length)
+	}
+	if maxLength < minLength {
+		maxLength = minLength
+	}
+	return g.text(minLength, maxLength), nil
+}
@@ -0,0 +1,233 @@
+package raml
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const mockLibrary = `#%RAML 1.0 Library
+types:
+  Code:
+    type: string
+    pattern: ^[A-Z]{2}-\d{3}$
+  Name:
+    type: string
+    minLength: 3
+    maxLength: 5
+  Percent:
+    type: number
+    minimum: 0
+    maximum: 1
+    multipleOf: 0.25
+  Small:
+    type: integer
+    format: int8
+    minimum: 100
+  Color:
+    enum: [red, green]
+  Tags:
+    type: string[]
+    minItems: 2
+    maxItems: 3
+    uniqueItems: true
+  Pet:
+    type: object
+    discriminator: kind
+    additionalProperties: false
+    properties:
+      kind: string
+      name: Name
+      code?: Code
+      born: date-only
+  Node:
+    type: object
+    properties:
+      value: integer
+      next?: Node
+  Id: Code | Small
+`
+
+func TestMockGenerator_Generate(t *testing.T) {
+	rml := parseLibrary(t, mockLibrary)
+	tests := []struct {
+		name     string
+		typeName string
+	}{
+		{name: "pattern", typeName: "Code"},
+		{name: "length", typeName: "Name"},
+		{name: "multipleOf", typeName: "Percent"},
+		{name: "integer format", typeName: "Small"},
+		{name: "enum", typeName: "Color"},
+		{name: "unique items", typeName: "Tags"},
+		{name: "discriminated object", typeName: "Pet"},
+		{name: "recursive object", typeName: "Node"},
+		{name: "union", typeName: "Id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := libraryType(t, rml, tt.typeName)
+			for seed := int64(0); seed < 20; seed++ {
+				v, err := Generate(s, seed)
+				require.NoError(t, err)
+				require.NoError(t, s.Validate(v, "$"), "seed %d: %v", seed, v)
+			}
+		})
+	}
+}
+
+func TestMockGenerator_Deterministic(t *testing.T) {
+	rml := parseLibrary(t, mockLibrary)
+	s := libraryType(t, rml, "Pet")
+	a, err := Generate(s, 42)
+	require.NoError(t, err)
+	b, err := Generate(s, 42)
+	require.NoError(t, err)
+	require.Equal(t, a, b)
+}
+
+func TestMockGenerator_Unsatisfiable(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0 Library
+types:
+  Impossible:
+    type: integer
+    format: int8
+    minimum: 200
+`)
+	_, err := Generate(libraryType(t, rml, "Impossible"), 1)
+	require.Error(t, err)
+}
+
+func TestMockGenerator_ECMAScriptPatterns(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0 Library
+types:
+  Password:
+    type: string
+    pattern: ^(?=.*\d)(?!.*[xyz])[a-z0-9]{8}$
+  Accent:
+    type: string
+    pattern: ^café-(?<n>\d+)$
+  Repeated:
+    type: string
+    pattern: ^(a)\1$
+`, OptWithRegexpEngine(NewECMAScriptEngine()))
+	for _, name := range []string{"Password", "Accent"} {
+		t.Run(name, func(t *testing.T) {
+			s := libraryType(t, rml, name)
+			for seed := int64(0); seed < 20; seed++ {
+				v, err := Generate(s, seed)
+				require.NoError(t, err)
+				require.NoError(t, s.Validate(v, "$"), "seed %d: %v", seed, v)
+			}
+		})
+	}
+	_, err := Generate(libraryType(t, rml, "Repeated"), 1)
+	require.ErrorContains(t, err, "backreferences are not supported")
+}
+
+func Test_mockPatternExpr(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{expr: `^[a-z]+$`, want: `^[a-z]+$`},
+		{expr: `^(?=.*\d)\w+$`, want: `^\w+$`},
+		{expr: `(?<!a(b))c(?<=[)])d`, want: `cd`},
+		{expr: `[(?=]x`, want: `[(?=]x`},
+		{expr: `\u00e9\(?!`, want: `\x{00e9}\(?!`},
+		{expr: `(?<year>\d{4})`, want: `(?<year>\d{4})`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := mockPatternExpr(tt.expr)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMockGenerator_DecimalMultipleOf(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0 Library
+types:
+  Step:
+    type: number
+    minimum: 0
+    maximum: 1
+    multipleOf: 0.1
+  Price:
+    type: number
+    minimum: -5.5
+    maximum: 20
+    multipleOf: 0.05
+  Scores:
+    type: object
+    properties:
+      a: Step
+      b: Step
+      c: Step
+      d: Step
+      e: Step
+      f: Step
+      g: Step
+      h: Step
+      i: Step
+      j: Step
+`)
+	for _, name := range []string{"Step", "Price", "Scores"} {
+		t.Run(name, func(t *testing.T) {
+			s := libraryType(t, rml, name)
+			for seed := int64(0); seed < 100; seed++ {
+				v, err := Generate(s, seed)
+				require.NoError(t, err)
+				require.NoError(t, s.Validate(v, "$"), "seed %d: %v", seed, v)
+			}
+		})
+	}
+}
+
+func TestMockGenerator_XMLBounds(t *testing.T) {
+	dir := t.TempDir()
+	schemas := map[string]string{
+		"fraction.xsd": `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+  <xs:element name="v">
+    <xs:simpleType>
+      <xs:restriction base="xs:integer">
+        <xs:minInclusive value="0.5"/>
+        <xs:maxInclusive value="0.7"/>
+      </xs:restriction>
+    </xs:simpleType>
+  </xs:element>
+</xs:schema>`,
+		"range.xsd": `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+  <xs:element name="v">
+    <xs:simpleType>
+      <xs:restriction base="xs:integer">
+        <xs:minInclusive value="0.5"/>
+        <xs:maxInclusive value="3.5"/>
+      </xs:restriction>
+    </xs:simpleType>
+  </xs:element>
+</xs:schema>`,
+	}
+	for name, content := range schemas {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+	rml, err := ParseFromString(`#%RAML 1.0 Library
+types:
+  Fraction: !include fraction.xsd
+  Range: !include range.xsd
+`, "library.raml", dir, OptWithUnwrap(), OptWithValidate())
+	require.NoError(t, err)
+
+	_, err = Generate(libraryType(t, rml, "Fraction"), 1)
+	require.ErrorContains(t, err, "within bounds")
+
+	s := libraryType(t, rml, "Range")
+	for seed := int64(0); seed < 20; seed++ {
+		v, err := Generate(s, seed)
+		require.NoError(t, err)
+		require.NoError(t, s.Validate(v, "$"), "seed %d: %v", seed, v)
+	}
+}
@@ -0,0 +1,543 @@
+package raml
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NegativeCase is an invalid payload that violates a single facet of a shape.
+type NegativeCase struct {
+	// Path is the JSON path of the violation, e.g. "$.pets[0].name".
+	Path string
+	// Facet is the violated facet, e.g. "maxLength", "required" or "type" for a type mismatch.
+	Facet string
+	// Description describes the mutation.
+	Description string
+	// Value is the whole payload with the mutation applied.
+	Value interface{}
+	// Rejected reports whether Shape.Validate rejects the payload.
+	Rejected bool
+	// Err is the error returned by Shape.Validate.
+	Err error
+}
+
+// GenerateNegative generates negative cases for the unwrapped shape with the given seed.
+func GenerateNegative(s Shape, seed int64) ([]NegativeCase, error) {
+	return NewMockGenerator(WithMockSeed(seed)).GenerateNegative(s)
+}
+
+// GenerateNegative generates a valid payload for the unwrapped shape and derives negative cases from it.
+// Every case applies one mutation that violates one facet of the shape or of a nested shape,
+// while the rest of the payload stays valid. Each case is verified against Shape.Validate
+// and the result is reported in the Rejected field. Optional properties missing in the payload
+// are added to cover their facets as well.
+func (g *MockGenerator) GenerateNegative(s Shape) ([]NegativeCase, error) {
+	root, err := g.Generate(s)
+	if err != nil {
+		return nil, err
+	}
+	n := &negativeGenerator{g: g, root: root}
+	n.shape(s, root, nil, make(map[string]struct{}))
+	for i := range n.cases {
+		c := &n.cases[i]
+		c.Err = validateSafely(s, c.Value)
+		c.Rejected = c.Err != nil
+	}
+	return n.cases, nil
+}
+
+// validateSafely validates the value and converts panics of validation into errors.
+func validateSafely(s Shape, v interface{}) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("validate: %v", r)
+		}
+	}()
+	return s.Validate(v, "$")
+}
+
+type negativeGenerator struct {
+	g     *MockGenerator
+	root  interface{}
+	cases []NegativeCase
+}
+
+// negativePath is a path to a value in the payload. Elements are object keys or array indexes.
+type negativePath []interface{}
+
+func (p negativePath) String() string {
+	var b strings.Builder
+	b.WriteString("$")
+	for _, e := range p {
+		switch e := e.(type) {
+		case string:
+			b.WriteString("." + e)
+		case int:
+			b.WriteString("[" + strconv.Itoa(e) + "]")
+		}
+	}
+	return b.String()
+}
+
+// valueOf returns the value at the path of the payload.
+func (p negativePath) valueOf(root interface{}) (interface{}, bool) {
+	cur := root
+	for _, e := range p {
+		switch e := e.(type) {
+		case string:
+			m, ok := cur.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if cur, ok = m[e]; !ok {
+				return nil, false
+			}
+		case int:
+			a, ok := cur.([]interface{})
+			if !ok || e >= len(a) {
+				return nil, false
+			}
+			cur = a[e]
+		}
+	}
+	return cur, true
+}
+
+func (p negativePath) with(e interface{}) negativePath {
+	return append(p[:len(p):len(p)], e)
+}
+
+// negativeDelete is a sentinel value that deletes the object property at the path.
+type negativeDelete struct{}
+
+// set returns a copy of the payload with the value at the path replaced.
+func (n *negativeGenerator) set(path negativePath, v interface{}) interface{} {
+	if len(path) == 0 {
+		return v
+	}
+	root := deepCopyValue(n.root)
+	cur := root
+	for i, e := range path {
+		last := i == len(path)-1
+		switch e := e.(type) {
+		case string:
+			m := cur.(map[string]interface{})
+			if !last {
+				cur = m[e]
+			} else if _, ok := v.(negativeDelete); ok {
+				delete(m, e)
+			} else {
+				m[e] = v
+			}
+		case int:
+			a := cur.([]interface{})
+			if !last {
+				cur = a[e]
+			} else {
+				a[e] = v
+			}
+		}
+	}
+	return root
+}
+
+func (n *negativeGenerator) add(path negativePath, facet string, description string, v interface{}) {
+	n.cases = append(n.cases, NegativeCase{
+		Path:        path.String(),
+		Facet:       facet,
+		Description: description,
+		Value:       n.set(path, v),
+	})
+}
+
+// deepCopyValue copies maps and slices of the value.
+func deepCopyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			c[k] = deepCopyValue(item)
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(v))
+		for i, item := range v {
+			c[i] = deepCopyValue(item)
+		}
+		return c
+	}
+	return v
+}
+
+// shape adds negative cases of the shape whose valid value is located at the path.
+// Recursive shapes are visited once per path to keep the number of cases finite.
+func (n *negativeGenerator) shape(s Shape, v interface{}, path negativePath, visiting map[string]struct{}) {
+	switch s := s.(type) {
+	case *ObjectShape:
+		n.object(s, v, path, visiting)
+	case *ArrayShape:
+		n.array(s, v, path, visiting)
+	case *StringShape:
+		n.string(s, v, path)
+	case *IntegerShape:
+		n.integer(s, path)
+	case *NumberShape:
+		n.number(s, v, path)
+	case *BooleanShape:
+		n.add(path, "type", "string instead of boolean", "true")
+		if len(s.Enum) == 1 {
+			n.add(path, "enum", "value not in enum", !s.Enum[0].Value.(bool))
+		}
+	case *FileShape:
+		n.add(path, "type", "number instead of file", 1)
+		n.fileLength(path, s.LengthFacets)
+	case *DateTimeShape:
+		n.add(path, "type", "number instead of datetime", 1)
+		if s.Format != nil && *s.Format == "rfc2616" {
+			n.add(path, "format", "RFC 3339 instead of RFC 2616 datetime", "2020-01-01T00:00:00Z")
+		} else {
+			n.add(path, "format", "RFC 2616 instead of RFC 3339 datetime", "Wed, 01 Jan 2020 00:00:00 GMT")
+		}
+	case *DateTimeOnlyShape:
+		n.add(path, "type", "number instead of datetime-only", 1)
+		n.add(path, "format", "datetime with time zone", "2020-01-01T00:00:00Z")
+	case *DateOnlyShape:
+		n.add(path, "type", "number instead of date-only", 1)
+		n.add(path, "format", "date with invalid month", "2020-13-01")
+	case *TimeOnlyShape:
+		n.add(path, "type", "number instead of time-only", 1)
+		n.add(path, "format", "time with invalid hour", "25:00:00")
+	case *UnionShape:
+		n.union(s, v, path, visiting)
+	case *RecursiveShape:
+		n.shape(*s.Head, v, path, visiting)
+	case *NilShape:
+		n.add(path, "type", "string instead of nil", "")
+	case *XMLShape:
+		n.add(path, "type", "number instead of XML document", 1)
+		n.add(path, "schema", "undeclared root element", "<undeclared/>")
+	}
+}
+
+func (n *negativeGenerator) object(s *ObjectShape, v interface{}, path negativePath, visiting map[string]struct{}) {
+	if _, ok := visiting[s.Id]; ok {
+		return
+	}
+	visiting[s.Id] = struct{}{}
+	defer delete(visiting, s.Id)
+
+	n.add(path, "type", "array instead of object", []interface{}{})
+	m, _ := v.(map[string]interface{})
+	if s.Properties != nil {
+		for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+			prop := pair.Value
+			ppath := path.with(prop.Name)
+			// Objects accept any discriminator value since it may identify a subtype,
+			// so wrong discriminator values are only generated for union members.
+			if s.Discriminator != nil && *s.Discriminator == prop.Name {
+				continue
+			}
+			if prop.Required {
+				n.add(ppath, "required", "missing required property", negativeDelete{})
+			}
+			pv, ok := m[prop.Name]
+			if !ok {
+				// Cases of optional properties are built on top of a payload that includes them.
+				generated, err := n.g.generate(*prop.Shape)
+				if err != nil {
+					continue
+				}
+				saved := n.root
+				n.root = n.set(ppath, generated)
+				n.shape(*prop.Shape, generated, ppath, visiting)
+				n.root = saved
+				continue
+			}
+			n.shape(*prop.Shape, pv, ppath, visiting)
+		}
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if s.PatternProperties != nil {
+		for _, k := range keys {
+			pv := m[k]
+			if s.Properties != nil {
+				if _, ok := s.Properties.Get(k); ok {
+					continue
+				}
+			}
+			for pair := s.PatternProperties.Oldest(); pair != nil; pair = pair.Next() {
+				if pair.Value.Pattern.MatchString(k) {
+					// A mutated value may be accepted by another pattern property or as an additional property.
+					from := len(n.cases)
+					n.shape(*pair.Value.Shape, pv, path.with(k), visiting)
+					n.keepRejected(s, path, from)
+					break
+				}
+			}
+		}
+	}
+	if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+		key := "unexpectedProperty"
+		for _, ok := m[key]; ok; _, ok = m[key] {
+			key += "_"
+		}
+		n.add(path.with(key), "additionalProperties", "additional property", "unexpected")
+	}
+	if s.MinProperties != nil && *s.MinProperties > 0 {
+		c := deepCopyValue(m).(map[string]interface{})
+		// Optional properties are removed first to keep required ones.
+		sort.SliceStable(keys, func(i, j int) bool {
+			return !s.isRequired(keys[i]) && s.isRequired(keys[j])
+		})
+		for _, k := range keys {
+			if uint64(len(c)) < *s.MinProperties {
+				break
+			}
+			delete(c, k)
+		}
+		n.add(path, "minProperties", "one property less than minProperties", c)
+	}
+	if s.MaxProperties != nil {
+		c := deepCopyValue(m).(map[string]interface{})
+		for i := 0; uint64(len(c)) <= *s.MaxProperties; i++ {
+			c["extraProperty"+strconv.Itoa(i)] = n.g.word()
+		}
+		n.add(path, "maxProperties", "one property more than maxProperties", c)
+	}
+}
+
+func (n *negativeGenerator) array(s *ArrayShape, v interface{}, path negativePath, visiting map[string]struct{}) {
+	n.add(path, "type", "object instead of array", map[string]interface{}{})
+	a, _ := v.([]interface{})
+	if s.MinItems != nil && *s.MinItems > 0 && uint64(len(a)) >= *s.MinItems {
+		n.add(path, "minItems", "one item less than minItems", deepCopyValue(a[:*s.MinItems-1]))
+	}
+	if s.MaxItems != nil && s.Items != nil {
+		c := deepCopyValue(a).([]interface{})
+		for i := 0; uint64(len(c)) <= *s.MaxItems && i < int(*s.MaxItems)*mockAttempts+mockAttempts; i++ {
+			item, err := n.g.generate(*s.Items)
+			if err != nil {
+				break
+			}
+			c = append(c, item)
+		}
+		if uint64(len(c)) > *s.MaxItems {
+			n.add(path, "maxItems", "one item more than maxItems", c)
+		}
+	}
+	if s.UniqueItems != nil && *s.UniqueItems && len(a) > 0 && (s.MaxItems == nil || uint64(len(a)) < *s.MaxItems) {
+		c := deepCopyValue(a).([]interface{})
+		n.add(path, "uniqueItems", "duplicate item", append(c, deepCopyValue(a[0])))
+	}
+	if s.Items == nil {
+		return
+	}
+	if len(a) > 0 {
+		n.shape(*s.Items, a[0], path.with(0), visiting)
+		return
+	}
+	// Cases of items are built on top of a payload with one item.
+	if s.MaxItems != nil && *s.MaxItems == 0 {
+		return
+	}
+	item, err := n.g.generate(*s.Items)
+	if err != nil {
+		return
+	}
+	saved := n.root
+	n.root = n.set(path, []interface{}{item})
+	n.shape(*s.Items, item, path.with(0), visiting)
+	n.root = saved
+}
+
+func (n *negativeGenerator) string(s *StringShape, v interface{}, path negativePath) {
+	n.add(path, "type", "number instead of string", 1)
+	str, _ := v.(string)
+	n.length(path, str, s.LengthFacets)
+	if s.Pattern != nil {
+		for _, candidate := range []string{"", "!", "#" + str, str + "\n#", "0", "_"} {
+			if s.Pattern.MatchString(candidate) {
+				continue
+			}
+			l := uint64(len(candidate))
+			if (s.MinLength == nil || l >= *s.MinLength) && (s.MaxLength == nil || l <= *s.MaxLength) {
+				n.add(path, "pattern", "value not matching pattern", candidate)
+				break
+			}
+		}
+	}
+	if s.Enum != nil {
+		candidate := "not-in-enum"
+		for enumContains(s.Enum, candidate) {
+			candidate += "_"
+		}
+		n.add(path, "enum", "value not in enum", candidate)
+	}
+}
+
+// length adds cases that violate length facets of strings and files.
+func (n *negativeGenerator) length(path negativePath, v interface{}, f LengthFacets) {
+	str, _ := v.(string)
+	if f.MinLength != nil && *f.MinLength > 0 {
+		short := str
+		if uint64(len(short)) >= *f.MinLength {
+			short = short[:*f.MinLength-1]
+		} else {
+			short = strings.Repeat("a", int(*f.MinLength-1))
+		}
+		n.add(path, "minLength", "one character shorter than minLength", short)
+	}
+	if f.MaxLength != nil {
+		long := str
+		if uint64(len(long)) > *f.MaxLength {
+			long = long[:*f.MaxLength]
+		}
+		pad := "a"
+		if len(long) > 0 {
+			pad = long[len(long)-1:]
+		}
+		long += strings.Repeat(pad, int(*f.MaxLength)+1-len(long))
+		n.add(path, "maxLength", "one character longer than maxLength", long)
+	}
+}
+
+// fileLength adds cases of base64 encoded content that is one byte shorter or longer than allowed.
+func (n *negativeGenerator) fileLength(path negativePath, f LengthFacets) {
+	if f.MinLength != nil && *f.MinLength > 0 {
+		short := strings.Repeat("a", int(*f.MinLength-1))
+		n.add(path, "minLength", "one byte shorter than minLength", base64.StdEncoding.EncodeToString([]byte(short)))
+	}
+	if f.MaxLength != nil {
+		long := strings.Repeat("a", int(*f.MaxLength)+1)
+		n.add(path, "maxLength", "one byte longer than maxLength", base64.StdEncoding.EncodeToString([]byte(long)))
+	}
+}
+
+func (s *ObjectShape) isRequired(name string) bool {
+	if s.Properties == nil {
+		return false
+	}
+	prop, ok := s.Properties.Get(name)
+	return ok && prop.Required
+}
+
+func (n *negativeGenerator) integer(s *IntegerShape, path negativePath) {
+	n.add(path, "type", "string instead of integer", "1")
+	n.add(path, "type", "fractional number instead of integer", 1.5)
+	bigValue := func(v *big.Int) interface{} {
+		if v.IsInt64() {
+			return int(v.Int64())
+		}
+		if v.IsUint64() {
+			return uint(v.Uint64())
+		}
+		f, _ := new(big.Float).SetInt(v).Float64()
+		return f
+	}
+	if s.Minimum != nil {
+		n.add(path, "minimum", "one less than minimum", bigValue(new(big.Int).Sub(s.Minimum, big.NewInt(1))))
+	}
+	if s.Maximum != nil {
+		n.add(path, "maximum", "one more than maximum", bigValue(new(big.Int).Add(s.Maximum, big.NewInt(1))))
+	}
+	if formatMin, formatMax := integerFormatBounds(s.Format); formatMin != nil {
+		if s.Minimum == nil || s.Minimum.Cmp(formatMin) < 0 {
+			n.add(path, "format", "one less than the format range", bigValue(new(big.Int).Sub(formatMin, big.NewInt(1))))
+		}
+		if s.Maximum == nil || s.Maximum.Cmp(formatMax) > 0 {
+			n.add(path, "format", "one more than the format range", bigValue(new(big.Int).Add(formatMax, big.NewInt(1))))
+		}
+	}
+	if s.MultipleOf != nil && *s.MultipleOf > 1 {
+		formatMin, formatMax := integerFormatBounds(s.Format)
+		lo, hi := intRange(s.Minimum, s.Maximum, formatMin, formatMax)
+		for v := new(big.Int).Set(lo); v.Cmp(hi) <= 0; v.Add(v, big.NewInt(1)) {
+			f, _ := new(big.Float).SetInt(v).Float64()
+			if math.Mod(f, *s.MultipleOf) != 0 {
+				n.add(path, "multipleOf", "value not a multiple of multipleOf", bigValue(v))
+				break
+			}
+		}
+	}
+	if s.Enum != nil {
+		candidate := 0
+		for _, e := range s.Enum {
+			if i, ok := e.Value.(int); ok && i >= candidate {
+				candidate = i + 1
+			}
+		}
+		n.add(path, "enum", "value not in enum", candidate)
+	}
+}
+
+func (n *negativeGenerator) number(s *NumberShape, v interface{}, path negativePath) {
+	n.add(path, "type", "string instead of number", "1")
+	if s.Minimum != nil {
+		n.add(path, "minimum", "less than minimum", *s.Minimum-1)
+	}
+	if s.Maximum != nil {
+		n.add(path, "maximum", "more than maximum", *s.Maximum+1)
+	}
+	if s.MultipleOf != nil {
+		f, _ := v.(float64)
+		candidate := f + *s.MultipleOf/2
+		if s.Maximum != nil && candidate > *s.Maximum {
+			candidate = f - *s.MultipleOf/2
+		}
+		n.add(path, "multipleOf", "value not a multiple of multipleOf", candidate)
+	}
+	if s.Format != nil {
+		if _, formatMax := integerFormatBounds(s.Format); formatMax != nil {
+			n.add(path, "format", "fractional number for integer format", 0.5)
+		}
+	}
+	if s.Enum != nil {
+		candidate := 0.5
+		for enumContains(s.Enum, candidate) {
+			candidate++
+		}
+		n.add(path, "enum", "value not in enum", candidate)
+	}
+}
+
+// union adds a type mismatch case and cases of the member that accepts the valid value.
+func (n *negativeGenerator) union(s *UnionShape, v interface{}, path negativePath, visiting map[string]struct{}) {
+	for _, candidate := range []interface{}{true, "not-a-member", 1.5, []interface{}{}, map[string]interface{}{}, nil} {
+		if validateSafely(s, candidate) != nil {
+			n.add(path, "type", "value matching no union member", candidate)
+			break
+		}
+	}
+	for _, item := range s.AnyOf {
+		if validateSafely(*item, v) == nil {
+			// A mutated value may be accepted by another member.
+			from := len(n.cases)
+			if obj, ok := (*item).(*ObjectShape); ok && obj.Discriminator != nil {
+				n.add(path.with(*obj.Discriminator), "discriminator", "unknown discriminator value", "unknown-discriminator-value")
+			}
+			n.shape(*item, v, path, visiting)
+			n.keepRejected(s, path, from)
+			return
+		}
+	}
+}
+
+// keepRejected drops cases added since from whose value at the path is accepted by the shape.
+func (n *negativeGenerator) keepRejected(s Shape, path negativePath, from int) {
+	kept := n.cases[:from]
+	for _, c := range n.cases[from:] {
+		if v, ok := path.valueOf(c.Value); !ok || validateSafely(s, v) != nil {
+			kept = append(kept, c)
+		}
+	}
+	n.cases = kept
+}
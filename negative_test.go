@@ -0,0 +1,117 @@
+package raml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const negativeLibrary = `#%RAML 1.0 Library
+types:
+  Cat:
+    type: object
+    discriminator: kind
+    discriminatorValue: cat
+    properties:
+      kind: string
+      lives:
+        type: integer
+        minimum: 1
+        maximum: 9
+  Dog:
+    type: object
+    discriminator: kind
+    discriminatorValue: dog
+    properties:
+      kind: string
+      lives?: integer
+  Pet: Cat | Dog
+  Loose: string | integer
+  Strict:
+    type: object
+    additionalProperties: false
+    properties:
+      name:
+        type: string
+        maxLength: 3
+  Labels:
+    type: object
+    properties:
+      /^l-/:
+        type: string
+        maxLength: 2
+      /^l/: integer
+  Owner:
+    type: object
+    properties:
+      name: string
+      pet: Pet
+      id: Loose
+      strict: Strict
+      labels: Labels
+`
+
+func TestMockGenerator_GenerateNegative(t *testing.T) {
+	rml := parseLibrary(t, negativeLibrary)
+	s := libraryType(t, rml, "Owner")
+	for seed := int64(0); seed < 10; seed++ {
+		cases, err := GenerateNegative(s, seed)
+		require.NoError(t, err)
+		require.NotEmpty(t, cases)
+		for _, c := range cases {
+			require.True(t, c.Rejected, "seed %d: %s %s (%s) is accepted: %v", seed, c.Path, c.Facet, c.Description, c.Value)
+		}
+	}
+}
+
+func TestMockGenerator_GenerateNegative_Facets(t *testing.T) {
+	rml := parseLibrary(t, negativeLibrary)
+	cases, err := GenerateNegative(libraryType(t, rml, "Owner"), 1)
+	require.NoError(t, err)
+	facets := make(map[string]struct{})
+	for _, c := range cases {
+		facets[c.Path+" "+c.Facet] = struct{}{}
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "missing required property", want: "$.name required"},
+		{name: "wrong type", want: "$.name type"},
+		{name: "wrong discriminator of union member", want: "$.pet.kind discriminator"},
+		{name: "additional property", want: "$.strict.unexpectedProperty additionalProperties"},
+		{name: "string too long", want: "$.strict.name maxLength"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Contains(t, facets, tt.want)
+		})
+	}
+}
+
+func TestObjectShape_Validate_Required(t *testing.T) {
+	rml := parseLibrary(t, negativeLibrary)
+	tests := []struct {
+		name     string
+		typeName string
+		value    interface{}
+		wantErr  bool
+	}{
+		{name: "all properties", typeName: "Dog", value: map[string]interface{}{"kind": "dog", "lives": 1}},
+		{name: "optional property missing", typeName: "Dog", value: map[string]interface{}{"kind": "dog"}},
+		{name: "required property missing", typeName: "Cat", value: map[string]interface{}{"kind": "cat"}, wantErr: true},
+		{name: "union member by discriminator", typeName: "Pet", value: map[string]interface{}{"kind": "dog", "lives": 1}},
+		{name: "union with unknown discriminator", typeName: "Pet", value: map[string]interface{}{"kind": "cow", "lives": 1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := libraryType(t, rml, tt.typeName).Validate(tt.value, "$")
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package raml
+
+type NormalizeOpt interface {
+	Apply(*NormalizeOptions)
+}
+
+type optNormalizeStrict struct{}
+
+func (o optNormalizeStrict) Apply(e *NormalizeOptions) {
+	e.strict = true
+}
+
+// WithNormalizeStrict removes properties that are not declared by explicit or pattern properties
+// regardless of additionalProperties facet.
+func WithNormalizeStrict() NormalizeOpt {
+	return optNormalizeStrict{}
+}
+
+type NormalizeOptions struct {
+	strict bool
+}
+
+// Normalize returns a copy of the value with defaults of the shape applied.
+// Absent properties that declare a default are filled with it, recursively for nested objects,
+// array items and union members. Union members are selected by discriminator,
+// or else the first member the value is valid against is used.
+// Properties that are not declared are removed if additionalProperties is false or strict option is set.
+// Values that do not match the shape are copied as is, Validate reports them.
+func Normalize(s Shape, v interface{}, opts ...NormalizeOpt) interface{} {
+	var o NormalizeOptions
+	for _, opt := range opts {
+		opt.Apply(&o)
+	}
+	return o.normalize(s, v)
+}
+
+func (o NormalizeOptions) normalize(s Shape, v interface{}) interface{} {
+	switch s := s.(type) {
+	case *ObjectShape:
+		return o.normalizeObject(s, v)
+	case *ArrayShape:
+		items, ok := v.([]interface{})
+		if !ok || s.Items == nil {
+			return deepCopyValue(v)
+		}
+		c := make([]interface{}, len(items))
+		for i, item := range items {
+			c[i] = o.normalize(*s.Items, item)
+		}
+		return c
+	case *UnionShape:
+		if member := unionMemberOf(s, v); member != nil {
+			return o.normalize(member, v)
+		}
+		// The value may be valid against a member only with defaults applied.
+		for _, member := range s.AnyOf {
+			if c := o.normalize(*member, v); (*member).Validate(c, "$") == nil {
+				return c
+			}
+		}
+	case *RecursiveShape:
+		return o.normalize(*s.Head, v)
+	}
+	return deepCopyValue(v)
+}
+
+func (o NormalizeOptions) normalizeObject(s *ObjectShape, v interface{}) interface{} {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return deepCopyValue(v)
+	}
+	strip := o.strict || (s.AdditionalProperties != nil && !*s.AdditionalProperties)
+	c := make(map[string]interface{}, len(m))
+	for k, item := range m {
+		// Explicitly defined properties have priority over pattern properties.
+		if s.Properties != nil {
+			if p, ok := s.Properties.Get(k); ok {
+				c[k] = o.normalize(*p.Shape, item)
+				continue
+			}
+		}
+		declared := false
+		if s.PatternProperties != nil {
+			for pair := s.PatternProperties.Oldest(); pair != nil; pair = pair.Next() {
+				if pair.Value.Pattern.MatchString(k) {
+					c[k] = o.normalize(*pair.Value.Shape, item)
+					declared = true
+					break
+				}
+			}
+		}
+		if !declared && !strip {
+			c[k] = deepCopyValue(item)
+		}
+	}
+	if s.Properties != nil {
+		for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+			if _, ok := c[pair.Key]; ok {
+				continue
+			}
+			if d := (*pair.Value.Shape).Base().Default; d != nil {
+				c[pair.Key] = o.normalize(*pair.Value.Shape, d.Value)
+			}
+		}
+	}
+	return c
+}
+
+// unionMemberOf returns the union member that the value belongs to.
+// Objects are matched by discriminator, other values by validation.
+func unionMemberOf(s *UnionShape, v interface{}) Shape {
+	if m, ok := v.(map[string]interface{}); ok {
+		for _, member := range s.AnyOf {
+			obj, ok := (*member).(*ObjectShape)
+			if !ok || obj.Discriminator == nil {
+				continue
+			}
+			if dv, ok := m[*obj.Discriminator]; ok && valuesEqual(dv, obj.discriminatorValue()) {
+				return obj
+			}
+		}
+	}
+	for _, member := range s.AnyOf {
+		if (*member).Validate(v, "$") == nil {
+			return *member
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package raml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0 Library
+types:
+  Pet:
+    type: object
+    discriminator: kind
+    properties:
+      kind: string
+  Cat:
+    type: Pet
+    properties:
+      lives:
+        type: integer
+        default: 9
+  Dog:
+    type: Pet
+    properties:
+      good:
+        type: boolean
+        default: true
+  Animal: Cat | Dog
+  Kennel:
+    type: object
+    additionalProperties: false
+    properties:
+      pets:
+        type: Animal[]
+      size?:
+        type: integer
+        default: 1
+`)
+	tests := []struct {
+		name  string
+		typ   string
+		value interface{}
+		opts  []NormalizeOpt
+		want  interface{}
+	}{
+		{
+			name:  "default by discriminator name",
+			typ:   "Animal",
+			value: map[string]interface{}{"kind": "Dog"},
+			want:  map[string]interface{}{"kind": "Dog", "good": true},
+		},
+		{
+			name:  "first member by discriminator name",
+			typ:   "Animal",
+			value: map[string]interface{}{"kind": "Cat"},
+			want:  map[string]interface{}{"kind": "Cat", "lives": 9},
+		},
+		{
+			name:  "explicit value is kept",
+			typ:   "Cat",
+			value: map[string]interface{}{"kind": "Cat", "lives": 3},
+			want:  map[string]interface{}{"kind": "Cat", "lives": 3},
+		},
+		{
+			name: "nested defaults and undeclared properties",
+			typ:  "Kennel",
+			value: map[string]interface{}{
+				"pets":  []interface{}{map[string]interface{}{"kind": "Dog"}},
+				"owner": "x",
+			},
+			want: map[string]interface{}{
+				"pets": []interface{}{map[string]interface{}{"kind": "Dog", "good": true}},
+				"size": 1,
+			},
+		},
+		{
+			name:  "strict",
+			typ:   "Cat",
+			value: map[string]interface{}{"kind": "Cat", "lives": 1, "owner": "x"},
+			opts:  []NormalizeOpt{WithNormalizeStrict()},
+			want:  map[string]interface{}{"kind": "Cat", "lives": 1},
+		},
+		{
+			name:  "mismatched value is copied",
+			typ:   "Kennel",
+			value: "kennel",
+			want:  "kennel",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Normalize(libraryType(t, rml, tt.typ), tt.value, tt.opts...))
+		})
+	}
+}
+
+func TestObjectShape_discriminatorValue(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0 Library
+types:
+  Pet:
+    type: object
+    discriminator: kind
+    properties:
+      kind: string
+  Cat:
+    type: Pet
+  Dog:
+    type: Pet
+    discriminatorValue: dog
+  Animal: Cat | Dog
+`)
+	union := libraryType(t, rml, "Animal").(*UnionShape)
+	var values []any
+	for _, member := range union.AnyOf {
+		values = append(values, (*member).(*ObjectShape).discriminatorValue())
+	}
+	require.Equal(t, []any{"Cat", "dog"}, values)
+}
@@ -0,0 +1,149 @@
+package raml
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// maxNumberExponent is the maximum absolute decimal exponent of numbers converted to big numbers.
+// Conversion takes time and memory proportional to the exponent, so larger exponents are rejected.
+const maxNumberExponent = 10000
+
+// maxNumberBinaryExponent is maxNumberExponent converted to the binary exponent of big.Float.
+const maxNumberBinaryExponent = maxNumberExponent * 3322 / 1000
+
+// checkNumberExponent returns an error if the decimal exponent of the number exceeds maxNumberExponent.
+func checkNumberExponent(n string) error {
+	i := strings.IndexAny(n, "eE")
+	if i == -1 {
+		return nil
+	}
+	digits := strings.TrimLeft(strings.TrimLeft(n[i+1:], "+-"), "0")
+	if digits == "" {
+		return nil
+	}
+	if len(digits) <= len(strconv.Itoa(maxNumberExponent)) {
+		if exp, err := strconv.Atoi(digits); err == nil && exp <= maxNumberExponent {
+			return nil
+		}
+	}
+	return fmt.Errorf("exponent of number must not exceed %d", maxNumberExponent)
+}
+
+// numericValue converts a value of any Go numeric type, json.Number, big.Int, big.Float or big.Rat
+// to big.Rat without loss of precision.
+// Floats are converted by their shortest decimal representation, so that 0.1 is exactly 1/10.
+func numericValue(v interface{}) (*big.Rat, error) {
+	switch v := v.(type) {
+	case int:
+		return new(big.Rat).SetInt64(int64(v)), nil
+	case int8:
+		return new(big.Rat).SetInt64(int64(v)), nil
+	case int16:
+		return new(big.Rat).SetInt64(int64(v)), nil
+	case int32:
+		return new(big.Rat).SetInt64(int64(v)), nil
+	case int64:
+		return new(big.Rat).SetInt64(v), nil
+	case uint:
+		return new(big.Rat).SetUint64(uint64(v)), nil
+	case uint8:
+		return new(big.Rat).SetUint64(uint64(v)), nil
+	case uint16:
+		return new(big.Rat).SetUint64(uint64(v)), nil
+	case uint32:
+		return new(big.Rat).SetUint64(uint64(v)), nil
+	case uint64:
+		return new(big.Rat).SetUint64(v), nil
+	case uintptr:
+		return new(big.Rat).SetUint64(uint64(v)), nil
+	case float32:
+		return floatValue(float64(v), 32)
+	case float64:
+		return floatValue(v, 64)
+	case json.Number:
+		if err := checkNumberExponent(string(v)); err != nil {
+			return nil, err
+		}
+		r, ok := new(big.Rat).SetString(string(v))
+		if !ok {
+			return nil, fmt.Errorf("invalid number %q", v)
+		}
+		return r, nil
+	case *big.Int:
+		if v == nil {
+			break
+		}
+		return new(big.Rat).SetInt(v), nil
+	case big.Int:
+		return new(big.Rat).SetInt(&v), nil
+	case *big.Float:
+		if v == nil {
+			break
+		}
+		if v.IsInf() {
+			return nil, fmt.Errorf("number must be finite")
+		}
+		if exp := v.MantExp(nil); exp > maxNumberBinaryExponent || exp < -maxNumberBinaryExponent {
+			return nil, fmt.Errorf("exponent of number must not exceed %d", maxNumberExponent)
+		}
+		r, _ := v.Rat(nil)
+		return r, nil
+	case *big.Rat:
+		if v == nil {
+			break
+		}
+		return v, nil
+	}
+	return nil, fmt.Errorf("invalid type, got %T, expected number", v)
+}
+
+// floatValue converts the float to big.Rat by its shortest decimal representation of the given bit size.
+func floatValue(f float64, bitSize int) (*big.Rat, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("number must be finite")
+	}
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, bitSize))
+	return r, nil
+}
+
+// decimalValue converts the finite float64 facet value to big.Rat by its shortest decimal representation.
+func decimalValue(f float64) *big.Rat {
+	r, err := floatValue(f, 64)
+	if err != nil {
+		return new(big.Rat)
+	}
+	return r
+}
+
+// isMultipleOf reports whether the value is a multiple of m. Both are compared as decimals,
+// so that 0.3 is a multiple of 0.1.
+func isMultipleOf(val *big.Rat, m float64) bool {
+	mr := decimalValue(m)
+	if mr.Sign() == 0 {
+		return false
+	}
+	return new(big.Rat).Quo(val, mr).IsInt()
+}
+
+// numberFormatBounds returns the range of finite values of the number format.
+func numberFormatBounds(format *string) (*big.Rat, *big.Rat) {
+	if format == nil {
+		return nil, nil
+	}
+	var maxValue float64
+	switch *format {
+	case "float":
+		maxValue = math.MaxFloat32
+	case "double":
+		maxValue = math.MaxFloat64
+	default:
+		return nil, nil
+	}
+	hi := new(big.Rat).SetFloat64(maxValue)
+	return new(big.Rat).Neg(hi), hi
+}
@@ -0,0 +1,103 @@
+package raml
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_numericValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    string
+		wantErr bool
+	}{
+		{name: "int", value: -3, want: "-3"},
+		{name: "int8", value: int8(-8), want: "-8"},
+		{name: "uint64", value: uint64(math.MaxUint64), want: "18446744073709551615"},
+		{name: "float64 by shortest decimal", value: 0.1, want: "1/10"},
+		{name: "float32 by shortest decimal", value: float32(0.1), want: "1/10"},
+		{name: "json number", value: json.Number("1.25e2"), want: "125"},
+		{name: "json number with exponent at limit", value: json.Number("1e-10000"), want: "1/1" + strings.Repeat("0", 10000)},
+		{name: "big int", value: big.NewInt(7), want: "7"},
+		{name: "big float", value: big.NewFloat(0.5), want: "1/2"},
+		{name: "big rat", value: big.NewRat(1, 3), want: "1/3"},
+		{name: "NaN", value: math.NaN(), wantErr: true},
+		{name: "infinity", value: math.Inf(1), wantErr: true},
+		{name: "infinite big float", value: new(big.Float).SetInf(false), wantErr: true},
+		{name: "huge exponent", value: json.Number("1e9999999"), wantErr: true},
+		{name: "huge negative exponent", value: json.Number("-1E-9999999"), wantErr: true},
+		{name: "huge big float exponent", value: new(big.Float).SetMantExp(big.NewFloat(1), 1<<20), wantErr: true},
+		{name: "invalid json number", value: json.Number("1x"), wantErr: true},
+		{name: "nil big int", value: (*big.Int)(nil), wantErr: true},
+		{name: "string", value: "1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := numericValue(tt.value)
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Less(t, len(err.Error()), 100)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got.RatString())
+		})
+	}
+}
+
+func Test_isMultipleOf(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		m     float64
+		want  bool
+	}{
+		{value: 0.3, m: 0.1, want: true},
+		{value: json.Number("0.35"), m: 0.1, want: false},
+		{value: 10, m: 2.5, want: true},
+		{value: 1, m: 0, want: false},
+	}
+	for _, tt := range tests {
+		r, err := numericValue(tt.value)
+		require.NoError(t, err)
+		require.Equal(t, tt.want, isMultipleOf(r, tt.m), "%v multiple of %v", tt.value, tt.m)
+	}
+}
+
+func TestNumberShape_Validate_Exponent(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0 Library
+types:
+  Age:
+    type: integer
+    maximum: 150
+  Float:
+    type: number
+    format: float
+`)
+	tests := []struct {
+		name    string
+		typ     string
+		value   interface{}
+		wantErr string
+	}{
+		{name: "huge exponent", typ: "Age", value: json.Number("1e9999999"), wantErr: "exponent of number must not exceed 10000"},
+		{name: "fraction is not expanded", typ: "Age", value: json.Number("1.5e-9000"), wantErr: "value must be an integer, got 1.5e-9000"},
+		{name: "above format range", typ: "Float", value: json.Number("1e39"), wantErr: "value is out of range of format float"},
+		{name: "within format range", typ: "Float", value: json.Number("3.4e38")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := libraryType(t, rml, tt.typ).Validate(tt.value, "$")
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
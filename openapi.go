@@ -0,0 +1,771 @@
+package raml
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+
+	"github.com/acronis/go-raml/stacktrace"
+)
+
+// OpenAPIVersion is the version of produced OpenAPI documents.
+const OpenAPIVersion = "3.1.0"
+
+// OpenAPIComponentsSchemasPath is the prefix of references to component schemas.
+const OpenAPIComponentsSchemasPath = "#/components/schemas/"
+
+// uriTemplateVariable matches variables of URI templates, e.g. "{id}".
+var uriTemplateVariable = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// OpenAPI represents an OpenAPI 3.1 document.
+//
+// https://spec.openapis.org/oas/v3.1.0
+type OpenAPI struct {
+	OpenAPI    string                                           `json:"openapi"`
+	Info       OpenAPIInfo                                      `json:"info"`
+	Servers    []*OpenAPIServer                                 `json:"servers,omitempty"`
+	Paths      *orderedmap.OrderedMap[string, *OpenAPIPathItem] `json:"paths,omitempty"`
+	Components OpenAPIComponents                                `json:"components"`
+	Security   []OpenAPISecurityRequirement                     `json:"security,omitempty"`
+}
+
+// OpenAPIInfo represents an Info Object.
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// OpenAPIComponents represents a Components Object.
+type OpenAPIComponents struct {
+	Schemas         *orderedmap.OrderedMap[string, *JSONSchema]            `json:"schemas,omitempty"`
+	SecuritySchemes *orderedmap.OrderedMap[string, *OpenAPISecurityScheme] `json:"securitySchemes,omitempty"`
+}
+
+// OpenAPIServer represents a Server Object.
+type OpenAPIServer struct {
+	URL       string                                                 `json:"url"`
+	Variables *orderedmap.OrderedMap[string, *OpenAPIServerVariable] `json:"variables,omitempty"`
+}
+
+// OpenAPIServerVariable represents a Server Variable Object.
+type OpenAPIServerVariable struct {
+	Enum        []string `json:"enum,omitempty"`
+	Default     string   `json:"default"`
+	Description string   `json:"description,omitempty"`
+}
+
+// OpenAPIPathItem represents a Path Item Object.
+type OpenAPIPathItem struct {
+	Summary     string              `json:"summary,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Get         *OpenAPIOperation   `json:"get,omitempty"`
+	Put         *OpenAPIOperation   `json:"put,omitempty"`
+	Post        *OpenAPIOperation   `json:"post,omitempty"`
+	Delete      *OpenAPIOperation   `json:"delete,omitempty"`
+	Options     *OpenAPIOperation   `json:"options,omitempty"`
+	Head        *OpenAPIOperation   `json:"head,omitempty"`
+	Patch       *OpenAPIOperation   `json:"patch,omitempty"`
+	Parameters  []*OpenAPIParameter `json:"parameters,omitempty"`
+}
+
+// OpenAPIOperation represents an Operation Object.
+type OpenAPIOperation struct {
+	Summary     string                                           `json:"summary,omitempty"`
+	Description string                                           `json:"description,omitempty"`
+	Parameters  []*OpenAPIParameter                              `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody                              `json:"requestBody,omitempty"`
+	Responses   *orderedmap.OrderedMap[string, *OpenAPIResponse] `json:"responses,omitempty"`
+	Security    []OpenAPISecurityRequirement                     `json:"security,omitempty"`
+}
+
+// OpenAPIParameter represents a Parameter Object.
+type OpenAPIParameter struct {
+	Name        string      `json:"name"`
+	In          string      `json:"in"`
+	Description string      `json:"description,omitempty"`
+	Required    bool        `json:"required,omitempty"`
+	Schema      *JSONSchema `json:"schema,omitempty"`
+}
+
+// OpenAPIRequestBody represents a Request Body Object.
+type OpenAPIRequestBody struct {
+	Content *orderedmap.OrderedMap[string, *OpenAPIMediaType] `json:"content"`
+}
+
+// OpenAPIMediaType represents a Media Type Object.
+type OpenAPIMediaType struct {
+	Schema *JSONSchema `json:"schema,omitempty"`
+}
+
+// OpenAPIResponse represents a Response Object.
+type OpenAPIResponse struct {
+	Description string                                            `json:"description"`
+	Headers     *orderedmap.OrderedMap[string, *OpenAPIHeader]    `json:"headers,omitempty"`
+	Content     *orderedmap.OrderedMap[string, *OpenAPIMediaType] `json:"content,omitempty"`
+}
+
+// OpenAPIHeader represents a Header Object.
+type OpenAPIHeader struct {
+	Description string      `json:"description,omitempty"`
+	Required    bool        `json:"required,omitempty"`
+	Schema      *JSONSchema `json:"schema,omitempty"`
+}
+
+// OpenAPISecurityScheme represents a Security Scheme Object.
+type OpenAPISecurityScheme struct {
+	Type        string             `json:"type"`
+	Description string             `json:"description,omitempty"`
+	Name        string             `json:"name,omitempty"`
+	In          string             `json:"in,omitempty"`
+	Scheme      string             `json:"scheme,omitempty"`
+	Flows       *OpenAPIOAuthFlows `json:"flows,omitempty"`
+}
+
+// OpenAPIOAuthFlows represents an OAuth Flows Object.
+type OpenAPIOAuthFlows struct {
+	Implicit          *OpenAPIOAuthFlow `json:"implicit,omitempty"`
+	Password          *OpenAPIOAuthFlow `json:"password,omitempty"`
+	ClientCredentials *OpenAPIOAuthFlow `json:"clientCredentials,omitempty"`
+	AuthorizationCode *OpenAPIOAuthFlow `json:"authorizationCode,omitempty"`
+}
+
+// OpenAPIOAuthFlow represents an OAuth Flow Object.
+type OpenAPIOAuthFlow struct {
+	AuthorizationURL string            `json:"authorizationUrl,omitempty"`
+	TokenURL         string            `json:"tokenUrl,omitempty"`
+	Scopes           map[string]string `json:"scopes"`
+}
+
+// OpenAPISecurityRequirement represents a Security Requirement Object.
+type OpenAPISecurityRequirement map[string][]string
+
+// OpenAPIDiscriminator represents a Discriminator Object.
+type OpenAPIDiscriminator struct {
+	PropertyName string            `json:"propertyName"`
+	Mapping      map[string]string `json:"mapping,omitempty"`
+}
+
+type OpenAPISchemaExporterOpt interface {
+	Apply(*OpenAPISchemaExporterOptions)
+}
+
+type optOpenAPIInfo struct {
+	info OpenAPIInfo
+}
+
+func (o optOpenAPIInfo) Apply(e *OpenAPISchemaExporterOptions) {
+	e.info = &o.info
+}
+
+// WithOpenAPIInfo sets the Info Object of the produced document.
+func WithOpenAPIInfo(info OpenAPIInfo) OpenAPISchemaExporterOpt {
+	return optOpenAPIInfo{info: info}
+}
+
+type optOpenAPIJSONSchemaOpts struct {
+	opts []JSONSchemaConverterOpt
+}
+
+func (o optOpenAPIJSONSchemaOpts) Apply(e *OpenAPISchemaExporterOptions) {
+	e.jsonSchemaOpts = append(e.jsonSchemaOpts, o.opts...)
+}
+
+// WithOpenAPIJSONSchemaOpts passes options to the underlying JSON Schema converter.
+func WithOpenAPIJSONSchemaOpts(opts ...JSONSchemaConverterOpt) OpenAPISchemaExporterOpt {
+	return optOpenAPIJSONSchemaOpts{opts: opts}
+}
+
+type OpenAPISchemaExporterOptions struct {
+	info           *OpenAPIInfo
+	jsonSchemaOpts []JSONSchemaConverterOpt
+}
+
+// OpenAPISchemaExporter exports RAML libraries and API definitions to OpenAPI 3.1 documents.
+// Shapes are converted with JSONSchemaConverter, named types are referenced via components.
+type OpenAPISchemaExporter struct {
+	opts OpenAPISchemaExporterOptions
+
+	raml *RAML
+	// securitySchemes holds names of security schemes that are exported.
+	securitySchemes map[string]struct{}
+
+	jsonSchema *JSONSchemaConverter
+	// components holds named types in order of their declaration.
+	components *orderedmap.OrderedMap[string, Shape]
+	// names maps type location and name to component names.
+	names    map[string]string
+	warnings []*stacktrace.StackTrace
+}
+
+func NewOpenAPISchemaExporter(opts ...OpenAPISchemaExporterOpt) *OpenAPISchemaExporter {
+	c := &OpenAPISchemaExporter{}
+	for _, opt := range opts {
+		opt.Apply(&c.opts)
+	}
+	return c
+}
+
+// ExportLibrary converts the library and the libraries it uses into components of an OpenAPI document.
+// Types of used libraries are named after the chain of "uses" aliases, e.g. "common.Type".
+// Constructs that cannot be represented in OpenAPI are reported as warnings.
+func (c *OpenAPISchemaExporter) ExportLibrary(lib *Library) (*OpenAPI, []*stacktrace.StackTrace) {
+	c.warnings = nil
+	doc := &OpenAPI{
+		OpenAPI:    OpenAPIVersion,
+		Info:       c.makeInfo(lib),
+		Components: OpenAPIComponents{Schemas: c.exportComponents(lib)},
+	}
+	return doc, c.warnings
+}
+
+// ExportAPI converts the API definition into an OpenAPI document. Types declared in the API and
+// the libraries it uses become components, resources become paths, and security schemes are mapped
+// to their OpenAPI counterparts. Constructs that cannot be represented in OpenAPI are reported as warnings.
+func (c *OpenAPISchemaExporter) ExportAPI(api *API) (*OpenAPI, []*stacktrace.StackTrace) {
+	c.warnings = nil
+	doc := &OpenAPI{
+		OpenAPI:    OpenAPIVersion,
+		Info:       c.makeAPIInfo(api),
+		Servers:    c.makeServers(api),
+		Components: OpenAPIComponents{Schemas: c.exportComponents(api.Library)},
+	}
+	doc.Components.SecuritySchemes = c.makeSecuritySchemes(api)
+	doc.Security = c.makeSecurity(api.SecuredBy)
+	doc.Paths = orderedmap.New[string, *OpenAPIPathItem]()
+	c.addPaths(doc.Paths, api.Resources, "", nil)
+	return doc, c.warnings
+}
+
+// exportComponents converts types of the library and the libraries it uses to component schemas
+// and prepares the JSON Schema converter to reference them.
+func (c *OpenAPISchemaExporter) exportComponents(lib *Library) *orderedmap.OrderedMap[string, *JSONSchema] {
+	c.raml = lib.raml
+	c.components = orderedmap.New[string, Shape]()
+	c.names = make(map[string]string)
+	c.collectLibrary(lib, "", make(map[string]struct{}))
+
+	c.jsonSchema = NewJSONSchemaConverter(c.opts.jsonSchemaOpts...)
+	c.jsonSchema.complexSchemas = make(map[string]*JSONSchema)
+	c.jsonSchema.definitions = make(Definitions)
+	c.jsonSchema.refs = make(map[string]string, c.components.Len())
+	c.jsonSchema.hook = c.hook
+	for pair := c.components.Oldest(); pair != nil; pair = pair.Next() {
+		c.jsonSchema.refs[pair.Value.Base().Id] = OpenAPIComponentsSchemasPath + pair.Key
+	}
+
+	schemas := orderedmap.New[string, *JSONSchema](c.components.Len())
+	for pair := c.components.Oldest(); pair != nil; pair = pair.Next() {
+		s := pair.Value
+		// Component itself must be defined inline instead of referencing itself.
+		schema := c.jsonSchema.visit(s)
+		c.hook(s, schema)
+		c.setSubtypesDiscriminator(s, schema)
+		schemas.Set(pair.Key, schema)
+	}
+	return schemas
+}
+
+func (c *OpenAPISchemaExporter) makeInfo(lib *Library) OpenAPIInfo {
+	if c.opts.info != nil {
+		return *c.opts.info
+	}
+	return OpenAPIInfo{
+		Title:       filepath.Base(lib.Location),
+		Version:     "1.0.0",
+		Description: lib.Usage,
+	}
+}
+
+func (c *OpenAPISchemaExporter) makeAPIInfo(api *API) OpenAPIInfo {
+	if c.opts.info != nil {
+		return *c.opts.info
+	}
+	info := OpenAPIInfo{
+		Title:       api.Title,
+		Version:     api.Version,
+		Description: api.Description,
+	}
+	if info.Version == "" {
+		info.Version = "1.0.0"
+	}
+	return info
+}
+
+// makeServers converts the base URI to the server. The version is substituted,
+// other URI parameters become server variables.
+func (c *OpenAPISchemaExporter) makeServers(api *API) []*OpenAPIServer {
+	if api.BaseURI == "" {
+		return nil
+	}
+	server := &OpenAPIServer{URL: strings.ReplaceAll(api.BaseURI, "{version}", api.Version)}
+	for _, match := range uriTemplateVariable.FindAllStringSubmatch(server.URL, -1) {
+		name := match[1]
+		if server.Variables == nil {
+			server.Variables = orderedmap.New[string, *OpenAPIServerVariable]()
+		}
+		variable := &OpenAPIServerVariable{}
+		var position *stacktrace.Position
+		if p, ok := propertyOf(api.BaseURIParameters, name); ok {
+			base := (*p.Shape).Base()
+			position = &base.Position
+			if base.Description != nil {
+				variable.Description = *base.Description
+			}
+			if str, ok := (*p.Shape).(*StringShape); ok {
+				for _, item := range str.Enum {
+					variable.Enum = append(variable.Enum, fmt.Sprint(item.Value))
+				}
+			}
+			if base.Default != nil {
+				variable.Default = fmt.Sprint(base.Default.Value)
+			} else if len(variable.Enum) > 0 {
+				variable.Default = variable.Enum[0]
+			}
+		}
+		if variable.Default == "" {
+			c.warnAt(api.Location, position, "server variable has no default value", stacktrace.WithInfo("variable", name))
+		}
+		server.Variables.Set(name, variable)
+	}
+	return []*OpenAPIServer{server}
+}
+
+func (c *OpenAPISchemaExporter) makeSecuritySchemes(api *API) *orderedmap.OrderedMap[string, *OpenAPISecurityScheme] {
+	c.securitySchemes = make(map[string]struct{})
+	if api.SecuritySchemes == nil {
+		return nil
+	}
+	schemes := orderedmap.New[string, *OpenAPISecurityScheme](api.SecuritySchemes.Len())
+	for pair := api.SecuritySchemes.Oldest(); pair != nil; pair = pair.Next() {
+		if scheme := c.makeSecurityScheme(pair.Value); scheme != nil {
+			schemes.Set(pair.Key, scheme)
+			c.securitySchemes[pair.Key] = struct{}{}
+		}
+	}
+	return schemes
+}
+
+func (c *OpenAPISchemaExporter) makeSecurityScheme(ss *SecurityScheme) *OpenAPISecurityScheme {
+	scheme := &OpenAPISecurityScheme{Description: ss.Description}
+	switch {
+	case ss.Type == "Basic Authentication":
+		scheme.Type, scheme.Scheme = "http", "basic"
+	case ss.Type == "Digest Authentication":
+		scheme.Type, scheme.Scheme = "http", "digest"
+	case ss.Type == "OAuth 2.0":
+		scheme.Type = "oauth2"
+		scheme.Flows = c.makeOAuthFlows(ss)
+	case ss.Type == "Pass Through" || strings.HasPrefix(ss.Type, "x-"):
+		// Only a single header or query parameter can be represented as an API key.
+		var headers, queryParameters *orderedmap.OrderedMap[string, Property]
+		if ss.DescribedBy != nil {
+			headers, queryParameters = ss.DescribedBy.Headers, ss.DescribedBy.QueryParameters
+		}
+		switch {
+		case headers.Len() == 1 && queryParameters.Len() == 0:
+			scheme.Type, scheme.In, scheme.Name = "apiKey", "header", headers.Oldest().Key
+		case headers.Len() == 0 && queryParameters.Len() == 1:
+			scheme.Type, scheme.In, scheme.Name = "apiKey", "query", queryParameters.Oldest().Key
+		default:
+			c.warnAt(ss.Location, &ss.Position, "security scheme must describe a single header or query parameter to be exported as API key",
+				stacktrace.WithInfo("name", ss.Name))
+			return nil
+		}
+	default:
+		c.warnAt(ss.Location, &ss.Position, "security scheme type has no OpenAPI counterpart and is skipped",
+			stacktrace.WithInfo("name", ss.Name), stacktrace.WithInfo("type", ss.Type))
+		return nil
+	}
+	return scheme
+}
+
+func (c *OpenAPISchemaExporter) makeOAuthFlows(ss *SecurityScheme) *OpenAPIOAuthFlows {
+	authorizationURL := settingString(ss.Settings, "authorizationUri")
+	tokenURL := settingString(ss.Settings, "accessTokenUri")
+	scopes := make(map[string]string)
+	for _, scope := range settingStrings(ss.Settings, "scopes") {
+		scopes[scope] = ""
+	}
+	flows := &OpenAPIOAuthFlows{}
+	for _, grant := range settingStrings(ss.Settings, "authorizationGrants") {
+		switch grant {
+		case "authorization_code":
+			flows.AuthorizationCode = &OpenAPIOAuthFlow{AuthorizationURL: authorizationURL, TokenURL: tokenURL, Scopes: scopes}
+		case "implicit":
+			flows.Implicit = &OpenAPIOAuthFlow{AuthorizationURL: authorizationURL, Scopes: scopes}
+		case "password":
+			flows.Password = &OpenAPIOAuthFlow{TokenURL: tokenURL, Scopes: scopes}
+		case "client_credentials":
+			flows.ClientCredentials = &OpenAPIOAuthFlow{TokenURL: tokenURL, Scopes: scopes}
+		default:
+			c.warnAt(ss.Location, &ss.Position, "authorization grant has no OpenAPI counterpart and is skipped",
+				stacktrace.WithInfo("name", ss.Name), stacktrace.WithInfo("grant", grant))
+		}
+	}
+	return flows
+}
+
+func propertyOf(properties *orderedmap.OrderedMap[string, Property], name string) (Property, bool) {
+	if properties == nil {
+		return Property{}, false
+	}
+	return properties.Get(name)
+}
+
+func settingString(settings map[string]interface{}, key string) string {
+	if v, ok := settings[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+func settingStrings(settings map[string]interface{}, key string) []string {
+	var items []string
+	switch v := settings[key].(type) {
+	case string:
+		items = append(items, v)
+	case []interface{}:
+		for _, item := range v {
+			items = append(items, fmt.Sprint(item))
+		}
+	}
+	return items
+}
+
+// makeSecurity converts references to security schemes. Null reference allows anonymous access.
+func (c *OpenAPISchemaExporter) makeSecurity(refs []*SecuredBy) []OpenAPISecurityRequirement {
+	if refs == nil {
+		return nil
+	}
+	security := make([]OpenAPISecurityRequirement, 0, len(refs))
+	for _, ref := range refs {
+		if ref.Name == "" {
+			security = append(security, OpenAPISecurityRequirement{})
+			continue
+		}
+		// Security schemes that are not exported are already reported.
+		if _, ok := c.securitySchemes[ref.Name]; !ok {
+			continue
+		}
+		scopes := ref.Scopes
+		if scopes == nil {
+			scopes = []string{}
+		}
+		security = append(security, OpenAPISecurityRequirement{ref.Name: scopes})
+	}
+	return security
+}
+
+// addPaths adds resources and their nested resources to paths. URI parameters of parent resources
+// apply to nested resources.
+func (c *OpenAPISchemaExporter) addPaths(
+	paths *orderedmap.OrderedMap[string, *OpenAPIPathItem], resources *orderedmap.OrderedMap[string, *Resource],
+	prefix string, uriParameters map[string]Property,
+) {
+	for pair := resources.Oldest(); pair != nil; pair = pair.Next() {
+		res := pair.Value
+		path := prefix + res.Path
+		params := make(map[string]Property, len(uriParameters))
+		for k, v := range uriParameters {
+			params[k] = v
+		}
+		for p := res.URIParameters.Oldest(); p != nil; p = p.Next() {
+			params[p.Key] = p.Value
+		}
+		if res.Methods.Len() > 0 {
+			paths.Set(path, c.makePathItem(res, path, params))
+		}
+		c.addPaths(paths, res.Resources, path, params)
+	}
+}
+
+func (c *OpenAPISchemaExporter) makePathItem(res *Resource, path string, uriParameters map[string]Property) *OpenAPIPathItem {
+	item := &OpenAPIPathItem{Summary: res.DisplayName, Description: res.Description}
+	for _, match := range uriTemplateVariable.FindAllStringSubmatch(path, -1) {
+		name := match[1]
+		p, ok := uriParameters[name]
+		if !ok {
+			// Undeclared URI parameters are strings.
+			item.Parameters = append(item.Parameters, &OpenAPIParameter{Name: name, In: "path", Required: true, Schema: &JSONSchema{Type: "string"}})
+			continue
+		}
+		param := c.makeParameter(p, "path")
+		if param == nil {
+			continue
+		}
+		if !param.Required {
+			c.warnAt(res.Location, &res.Position, "optional URI parameter is required in OpenAPI", stacktrace.WithInfo("parameter", name))
+			param.Required = true
+		}
+		item.Parameters = append(item.Parameters, param)
+	}
+	for pair := res.Methods.Oldest(); pair != nil; pair = pair.Next() {
+		op := c.makeOperation(pair.Value)
+		switch pair.Key {
+		case "get":
+			item.Get = op
+		case "put":
+			item.Put = op
+		case "post":
+			item.Post = op
+		case "delete":
+			item.Delete = op
+		case "options":
+			item.Options = op
+		case "head":
+			item.Head = op
+		case "patch":
+			item.Patch = op
+		}
+	}
+	return item
+}
+
+func (c *OpenAPISchemaExporter) makeOperation(m *Method) *OpenAPIOperation {
+	op := &OpenAPIOperation{
+		Summary:     m.DisplayName,
+		Description: m.Description,
+		Security:    c.makeSecurity(m.SecuredBy),
+	}
+	for pair := m.QueryParameters.Oldest(); pair != nil; pair = pair.Next() {
+		if param := c.makeParameter(pair.Value, "query"); param != nil {
+			op.Parameters = append(op.Parameters, param)
+		}
+	}
+	for pair := m.Headers.Oldest(); pair != nil; pair = pair.Next() {
+		if param := c.makeParameter(pair.Value, "header"); param != nil {
+			op.Parameters = append(op.Parameters, param)
+		}
+	}
+	if m.Body.Len() > 0 {
+		op.RequestBody = &OpenAPIRequestBody{Content: c.makeContent(m.Body)}
+	}
+	if m.Responses.Len() > 0 {
+		op.Responses = orderedmap.New[string, *OpenAPIResponse](m.Responses.Len())
+		for pair := m.Responses.Oldest(); pair != nil; pair = pair.Next() {
+			op.Responses.Set(pair.Key, c.makeResponse(pair.Value))
+		}
+	}
+	return op
+}
+
+func (c *OpenAPISchemaExporter) makeResponse(r *Response) *OpenAPIResponse {
+	resp := &OpenAPIResponse{Description: r.Description}
+	for pair := r.Headers.Oldest(); pair != nil; pair = pair.Next() {
+		param := c.makeParameter(pair.Value, "header")
+		if param == nil {
+			continue
+		}
+		if resp.Headers == nil {
+			resp.Headers = orderedmap.New[string, *OpenAPIHeader]()
+		}
+		resp.Headers.Set(param.Name, &OpenAPIHeader{Description: param.Description, Required: param.Required, Schema: param.Schema})
+	}
+	if r.Body.Len() > 0 {
+		resp.Content = c.makeContent(r.Body)
+	}
+	return resp
+}
+
+func (c *OpenAPISchemaExporter) makeContent(body *orderedmap.OrderedMap[string, *Body]) *orderedmap.OrderedMap[string, *OpenAPIMediaType] {
+	content := orderedmap.New[string, *OpenAPIMediaType](body.Len())
+	for pair := body.Oldest(); pair != nil; pair = pair.Next() {
+		mt := &OpenAPIMediaType{}
+		if s := c.unwrap(pair.Value.Shape); s != nil {
+			mt.Schema = c.jsonSchema.Visit(s)
+		}
+		content.Set(pair.Key, mt)
+	}
+	return content
+}
+
+func (c *OpenAPISchemaExporter) makeParameter(p Property, in string) *OpenAPIParameter {
+	s := c.unwrap(p.Shape)
+	if s == nil {
+		return nil
+	}
+	param := &OpenAPIParameter{Name: p.Name, In: in, Required: p.Required, Schema: c.jsonSchema.Visit(s)}
+	if d := s.Base().Description; d != nil {
+		param.Description = *d
+	}
+	return param
+}
+
+// unwrap returns the unwrapped shape or nil if the shape cannot be unwrapped.
+func (c *OpenAPISchemaExporter) unwrap(shape *Shape) Shape {
+	s := *shape
+	if s.Base().IsUnwrapped() {
+		return s
+	}
+	us, err := c.raml.UnwrapShape(shape, make([]Shape, 0))
+	if err != nil {
+		c.warn(s.Base(), "shape cannot be unwrapped and is skipped", stacktrace.WithInfo("error", err))
+		return nil
+	}
+	return us
+}
+
+func (c *OpenAPISchemaExporter) collectLibrary(lib *Library, prefix string, visited map[string]struct{}) {
+	if _, ok := visited[lib.Location]; ok {
+		return
+	}
+	visited[lib.Location] = struct{}{}
+
+	for pair := lib.Types.Oldest(); pair != nil; pair = pair.Next() {
+		name, shape := prefix+pair.Key, pair.Value
+		if shape == nil {
+			continue
+		}
+		s := *shape
+		if !s.Base().IsUnwrapped() {
+			us, err := lib.raml.UnwrapShape(shape, make([]Shape, 0))
+			if err != nil {
+				c.warn(s.Base(), "type cannot be unwrapped and is skipped", stacktrace.WithInfo("error", err))
+				continue
+			}
+			s = us
+		}
+		c.components.Set(name, s)
+		c.names[lib.Location+"#"+pair.Key] = name
+	}
+	for pair := lib.Uses.Oldest(); pair != nil; pair = pair.Next() {
+		if pair.Value.Link != nil {
+			c.collectLibrary(pair.Value.Link, prefix+pair.Key+".", visited)
+		}
+	}
+}
+
+// hook adjusts schemas produced by the JSON Schema converter to OpenAPI and reports losses.
+func (c *OpenAPISchemaExporter) hook(s Shape, schema *JSONSchema) {
+	switch s := s.(type) {
+	case *IntegerShape:
+		if s.Format != nil {
+			switch *s.Format {
+			case "int", "int32":
+				schema.Format = "int32"
+			case "int64", "long":
+				schema.Format = "int64"
+			default:
+				schema.Format = "int32"
+				c.warn(s.Base(), "integer format is widened to int32", stacktrace.WithInfo("format", *s.Format))
+			}
+		}
+	case *NumberShape:
+		if s.Format != nil {
+			schema.Format = *s.Format
+		}
+	case *FileShape:
+		if len(s.FileTypes) > 1 {
+			c.warn(s.Base(), "only the first file type is preserved", stacktrace.WithInfo("fileTypes", s.FileTypes.String()))
+		}
+	case *JSONShape:
+		if len(schema.Definitions) > 0 {
+			c.warn(s.Base(), "definitions of embedded JSON schema are not moved to components")
+		}
+	case *RecursiveShape:
+		c.warn(s.Base(), "recursion to anonymous type cannot be referenced")
+	case *UnionShape:
+		c.setUnionDiscriminator(s, schema)
+	}
+}
+
+// setUnionDiscriminator adds discriminator to a union of objects that share the same discriminator property.
+func (c *OpenAPISchemaExporter) setUnionDiscriminator(s *UnionShape, schema *JSONSchema) {
+	var propertyName string
+	mapping := make(map[string]string)
+	for _, item := range s.AnyOf {
+		member, ok := (*item).(*ObjectShape)
+		if !ok || member.Discriminator == nil {
+			return
+		}
+		if propertyName == "" {
+			propertyName = *member.Discriminator
+		} else if propertyName != *member.Discriminator {
+			c.warn(s.Base(), "union members have different discriminators")
+			return
+		}
+		// Union members that are aliases to components lose the original name, so the component is used instead.
+		if ref, ok := c.jsonSchema.refs[member.Id]; ok {
+			if component, ok := c.components.Get(strings.TrimPrefix(ref, OpenAPIComponentsSchemasPath)); ok {
+				if o, ok := component.(*ObjectShape); ok {
+					member = o
+				}
+			}
+			mapping[discriminatorValueOf(member)] = ref
+		}
+	}
+	if propertyName == "" {
+		return
+	}
+	schema.OneOf, schema.AnyOf = schema.AnyOf, nil
+	schema.Extras["discriminator"] = &OpenAPIDiscriminator{PropertyName: propertyName, Mapping: mapping}
+}
+
+// setSubtypesDiscriminator adds discriminator with mapping to components that are parents of discriminated components.
+func (c *OpenAPISchemaExporter) setSubtypesDiscriminator(s Shape, schema *JSONSchema) {
+	parent, ok := s.(*ObjectShape)
+	if !ok || parent.Discriminator == nil {
+		return
+	}
+	mapping := make(map[string]string)
+	hasSubtypes := false
+	for pair := c.components.Oldest(); pair != nil; pair = pair.Next() {
+		child, ok := pair.Value.(*ObjectShape)
+		if !ok || child.Discriminator == nil || *child.Discriminator != *parent.Discriminator {
+			continue
+		}
+		if child.Id == parent.Id {
+			mapping[discriminatorValueOf(child)] = OpenAPIComponentsSchemasPath + pair.Key
+		} else if c.inheritsFrom(child, parent) {
+			mapping[discriminatorValueOf(child)] = OpenAPIComponentsSchemasPath + pair.Key
+			hasSubtypes = true
+		}
+	}
+	// Subtypes inherit the discriminator, but only the parent describes the polymorphism.
+	if !hasSubtypes {
+		return
+	}
+	schema.Extras["discriminator"] = &OpenAPIDiscriminator{PropertyName: *parent.Discriminator, Mapping: mapping}
+}
+
+func (c *OpenAPISchemaExporter) inheritsFrom(child Shape, parent Shape) bool {
+	// NOTE: Unwrap changes IDs of parents to the child ID, so parents are matched by the declaration name.
+	parentName := c.names[parent.Base().Location+"#"+parent.Base().Name]
+	if parentName == "" {
+		return false
+	}
+	for _, item := range child.Base().Inherits {
+		base := (*item).Base()
+		if c.names[base.Location+"#"+base.Name] == parentName || c.inheritsFrom(*item, parent) {
+			return true
+		}
+	}
+	return false
+}
+
+func discriminatorValueOf(s *ObjectShape) string {
+	if s.DiscriminatorValue != nil {
+		return fmt.Sprint(s.DiscriminatorValue)
+	}
+	return s.Name
+}
+
+func (c *OpenAPISchemaExporter) warn(base *BaseShape, message string, opts ...stacktrace.Option) {
+	c.warnAt(base.Location, &base.Position, message, append(opts, stacktrace.WithInfo("shape", base.Name))...)
+}
+
+// warnAt reports the warning at the position, which is nil if unknown.
+func (c *OpenAPISchemaExporter) warnAt(location string, position *stacktrace.Position, message string, opts ...stacktrace.Option) {
+	opts = append(opts,
+		stacktrace.WithSeverity(stacktrace.SeverityWarning),
+		stacktrace.WithType(stacktrace.TypeConverting),
+	)
+	if position != nil {
+		opts = append(opts, stacktrace.WithPosition(position))
+	}
+	c.warnings = append(c.warnings, stacktrace.New(message, location, opts...))
+}
@@ -0,0 +1,232 @@
+package raml
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const openAPILibrary = `#%RAML 1.0 Library
+usage: Pets
+types:
+  Pet:
+    type: object
+    discriminator: kind
+    properties:
+      kind: string
+      age:
+        type: integer
+        format: int8
+  Cat:
+    type: Pet
+    discriminatorValue: cat
+  Dog:
+    type: Pet
+  Owner:
+    type: object
+    properties:
+      pet: Pet
+      pets: Cat | Dog
+`
+
+func TestOpenAPISchemaExporter_ExportLibrary(t *testing.T) {
+	rml := parseLibrary(t, openAPILibrary)
+	doc, warnings := NewOpenAPISchemaExporter().ExportLibrary(rml.EntryPoint().(*Library))
+	require.Equal(t, OpenAPIVersion, doc.OpenAPI)
+	require.Equal(t, "Pets", doc.Info.Description)
+	var names []string
+	for pair := doc.Components.Schemas.Oldest(); pair != nil; pair = pair.Next() {
+		names = append(names, pair.Key)
+	}
+	require.Equal(t, []string{"Pet", "Cat", "Dog", "Owner"}, names)
+
+	b, err := json.Marshal(doc)
+	require.NoError(t, err)
+	var raw struct {
+		Components struct {
+			Schemas map[string]map[string]any `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(b, &raw))
+	schemas := raw.Components.Schemas
+
+	tests := []struct {
+		name    string
+		pointer func() any
+		want    any
+	}{
+		{
+			name:    "parent discriminator mapping",
+			pointer: func() any { return schemas["Pet"]["discriminator"] },
+			want: map[string]any{
+				"propertyName": "kind",
+				"mapping": map[string]any{
+					"Pet": "#/components/schemas/Pet",
+					"cat": "#/components/schemas/Cat",
+					"Dog": "#/components/schemas/Dog",
+				},
+			},
+		},
+		{
+			name:    "subtype has no discriminator",
+			pointer: func() any { return schemas["Cat"]["discriminator"] },
+			want:    nil,
+		},
+		{
+			name: "named type is referenced",
+			pointer: func() any {
+				return schemas["Owner"]["properties"].(map[string]any)["pet"].(map[string]any)["$ref"]
+			},
+			want: "#/components/schemas/Pet",
+		},
+		{
+			name: "union of discriminated objects",
+			pointer: func() any {
+				return schemas["Owner"]["properties"].(map[string]any)["pets"].(map[string]any)["discriminator"]
+			},
+			want: map[string]any{
+				"propertyName": "kind",
+				"mapping": map[string]any{
+					"cat": "#/components/schemas/Cat",
+					"Dog": "#/components/schemas/Dog",
+				},
+			},
+		},
+		{
+			name: "integer format is widened",
+			pointer: func() any {
+				return schemas["Pet"]["properties"].(map[string]any)["age"].(map[string]any)["format"]
+			},
+			want: "int32",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.pointer())
+		})
+	}
+
+	require.NotEmpty(t, warnings)
+	require.Equal(t, "integer format is widened to int32", warnings[0].Message)
+}
+
+func TestOpenAPISchemaExporter_WithOpenAPIInfo(t *testing.T) {
+	rml := parseLibrary(t, openAPILibrary)
+	info := OpenAPIInfo{Title: "Pets API", Version: "2.0.0"}
+	doc, _ := NewOpenAPISchemaExporter(WithOpenAPIInfo(info)).ExportLibrary(rml.EntryPoint().(*Library))
+	require.Equal(t, info, doc.Info)
+}
+
+func TestOpenAPISchemaExporter_ExportAPI(t *testing.T) {
+	rml := parseLibrary(t, testAPI)
+	doc, warnings := NewOpenAPISchemaExporter().ExportAPI(rml.EntryPoint().(*API))
+	require.Empty(t, warnings)
+	require.Equal(t, OpenAPIInfo{Title: "Pets", Version: "v1"}, doc.Info)
+
+	b, err := json.Marshal(doc)
+	require.NoError(t, err)
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(b, &raw))
+	at := func(keys ...any) any {
+		var v any = raw
+		for _, k := range keys {
+			switch k := k.(type) {
+			case string:
+				v = v.(map[string]any)[k]
+			case int:
+				v = v.([]any)[k]
+			}
+		}
+		return v
+	}
+
+	tests := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{name: "server", got: at("servers", 0, "url"), want: "https://api.example.com/v1"},
+		{name: "global security", got: at("security"), want: []any{map[string]any{"oauth": []any{}}}},
+		{
+			name: "query parameter",
+			got:  at("paths", "/pets", "get", "parameters", 0),
+			want: map[string]any{"name": "limit", "in": "query", "schema": map[string]any{"type": "integer", "maximum": float64(100)}},
+		},
+		{
+			name: "response body references component",
+			got:  at("paths", "/pets", "get", "responses", "200", "content", "application/json", "schema", "items", "$ref"),
+			want: "#/components/schemas/Pet",
+		},
+		{
+			name: "request body of default media type",
+			got:  at("paths", "/pets", "post", "requestBody", "content", "application/json", "schema", "$ref"),
+			want: "#/components/schemas/Pet",
+		},
+		{
+			name: "response header",
+			got:  at("paths", "/pets", "post", "responses", "201", "headers", "Location"),
+			want: map[string]any{"required": true, "schema": map[string]any{"type": "string"}},
+		},
+		{
+			name: "operation security with scopes",
+			got:  at("paths", "/pets", "post", "security"),
+			want: []any{map[string]any{"oauth": []any{"write"}}, map[string]any{"key": []any{}}},
+		},
+		{name: "nested resource path", got: at("paths", "/pets/{id}", "parameters", 0, "in"), want: "path"},
+		{name: "anonymous access", got: at("paths", "/pets/{id}", "get", "security"), want: []any{map[string]any{}}},
+		{name: "media types", got: len(at("paths", "/pets/{id}", "get", "responses", "200", "content").(map[string]any)), want: 2},
+		{
+			name: "oauth flows",
+			got:  at("components", "securitySchemes", "oauth", "flows", "authorizationCode"),
+			want: map[string]any{
+				"authorizationUrl": "https://auth.example.com/authorize",
+				"tokenUrl":         "https://auth.example.com/token",
+				"scopes":           map[string]any{"read": "", "write": ""},
+			},
+		},
+		{
+			name: "api key",
+			got:  at("components", "securitySchemes", "key"),
+			want: map[string]any{"type": "apiKey", "in": "header", "name": "X-Api-Key"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.got)
+		})
+	}
+}
+
+func TestOpenAPISchemaExporter_ExportAPI_Warnings(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0
+title: Legacy
+baseUri: https://{tenant}.example.com
+securitySchemes:
+  oauth1:
+    type: OAuth 1.0
+  basic:
+    type: Basic Authentication
+securedBy: [oauth1, basic]
+/items/{id}/{part}:
+  uriParameters:
+    id?: string
+  get:
+`)
+	doc, warnings := NewOpenAPISchemaExporter().ExportAPI(rml.EntryPoint().(*API))
+	var messages []string
+	for _, w := range warnings {
+		messages = append(messages, w.Message)
+	}
+	require.Equal(t, []string{
+		"server variable has no default value",
+		"security scheme type has no OpenAPI counterpart and is skipped",
+		"optional URI parameter is required in OpenAPI",
+	}, messages)
+	require.Equal(t, []OpenAPISecurityRequirement{{"basic": {}}}, doc.Security)
+	item, ok := doc.Paths.Get("/items/{id}/{part}")
+	require.True(t, ok)
+	require.Len(t, item.Parameters, 2)
+	require.True(t, item.Parameters[0].Required)
+	require.Equal(t, &OpenAPIParameter{Name: "part", In: "path", Required: true, Schema: &JSONSchema{Type: "string"}}, item.Parameters[1])
+}
@@ -0,0 +1,1163 @@
+package raml
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/acronis/go-raml/stacktrace"
+)
+
+// openAPISchemaKeywords is a set of schema keywords the importer understands.
+var openAPISchemaKeywords = map[string]struct{}{
+	"$ref": {}, "type": {}, "nullable": {}, "format": {}, "title": {}, "description": {}, "default": {},
+	"example": {}, "examples": {}, "enum": {}, "const": {}, "readOnly": {}, "writeOnly": {}, "deprecated": {},
+	"allOf": {}, "oneOf": {}, "anyOf": {}, "discriminator": {},
+	"minLength": {}, "maxLength": {}, "pattern": {},
+	"minimum": {}, "maximum": {}, "exclusiveMinimum": {}, "exclusiveMaximum": {}, "multipleOf": {},
+	"items": {}, "minItems": {}, "maxItems": {}, "uniqueItems": {},
+	"properties": {}, "required": {}, "additionalProperties": {}, "patternProperties": {},
+	"minProperties": {}, "maxProperties": {},
+}
+
+// openAPIStandardAnnotations are OpenAPI keywords that are imported as RAML annotations.
+var openAPIStandardAnnotations = []string{"readOnly", "writeOnly", "deprecated"}
+
+var invalidTypeNameChars = regexp.MustCompile(`[^0-9A-Za-z_-]`)
+
+// OpenAPIImporter converts OpenAPI 3.0 and 3.1 documents to RAML 1.0 libraries, or to API definitions
+// if documents have paths. Component schemas become types, anonymous schemas that cannot be declared inline
+// (union members, nullable complex schemas) are hoisted into named types.
+type OpenAPIImporter struct {
+	location string
+	// components is the components object of the document, references to it are resolved in paths.
+	components *yaml.Node
+	// securitySchemes holds names of imported security schemes.
+	securitySchemes map[string]struct{}
+
+	types       *orderedmap.OrderedMap[string, *yaml.Node]
+	names       map[string]string
+	annotations map[string]struct{}
+	// discriminatorValues holds values taken from discriminator mappings by type names.
+	discriminatorValues map[string]string
+	warnings            []*stacktrace.StackTrace
+}
+
+func NewOpenAPIImporter() *OpenAPIImporter {
+	return &OpenAPIImporter{}
+}
+
+// ImportOpenAPI parses an OpenAPI document into the RAML model.
+// The document is converted to a RAML library or API definition located at the document location,
+// which is then parsed with the regular parser and given options.
+func ImportOpenAPI(ctx context.Context, data []byte, location string, opts ...ParseOpt) (*RAML, []*stacktrace.StackTrace, error) {
+	if ctx == nil {
+		return nil, nil, fmt.Errorf("context is nil")
+	}
+	content, warnings, err := NewOpenAPIImporter().ConvertToRAML(data, location)
+	if err != nil {
+		return nil, warnings, err
+	}
+	rml := New(ctx)
+	err = rml.ParseFromString(string(content), filepath.Base(location), filepath.Dir(location), opts...)
+	return rml, warnings, err
+}
+
+// ConvertToRAML converts an OpenAPI document in YAML or JSON format to RAML 1.0 definition.
+// Documents with paths become API definitions with resources and security schemes,
+// other documents become libraries of component schemas.
+// Constructs that have no RAML counterpart are reported as warnings.
+func (i *OpenAPIImporter) ConvertToRAML(data []byte, location string) ([]byte, []*stacktrace.StackTrace, error) {
+	i.location = location
+	i.securitySchemes = make(map[string]struct{})
+	i.types = orderedmap.New[string, *yaml.Node]()
+	i.names = make(map[string]string)
+	i.annotations = make(map[string]struct{})
+	i.discriminatorValues = make(map[string]string)
+	i.warnings = nil
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, stacktrace.NewWrapped("unmarshal document", err, location, stacktrace.WithType(stacktrace.TypeParsing))
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, nil, stacktrace.New("document must be map", location, stacktrace.WithType(stacktrace.TypeParsing))
+	}
+	root := doc.Content[0]
+	version := yamlMapGet(root, "openapi")
+	if version == nil || !strings.HasPrefix(version.Value, "3.") {
+		return nil, nil, stacktrace.New("unsupported OpenAPI version", location, stacktrace.WithNodePosition(root),
+			stacktrace.WithType(stacktrace.TypeParsing), stacktrace.WithInfo("version", yamlNodeValue(version)))
+	}
+	paths := yamlMapGet(root, "paths")
+
+	hasPaths := paths != nil && len(paths.Content) > 0
+
+	var schemas *yaml.Node
+	i.components = yamlMapGet(root, "components")
+	if components := i.components; components != nil {
+		for j := 0; j+1 < len(components.Content); j += 2 {
+			switch components.Content[j].Value {
+			case "schemas":
+				schemas = components.Content[j+1]
+			case "securitySchemes", "parameters", "requestBodies", "responses", "headers":
+				// Imported with paths that use them.
+				if hasPaths {
+					continue
+				}
+				fallthrough
+			default:
+				i.warn(components.Content[j], "components are not imported", stacktrace.WithInfo("component", components.Content[j].Value))
+			}
+		}
+	}
+	if schemas != nil {
+		// Names are reserved first so that references can be resolved in any order.
+		for j := 0; j+1 < len(schemas.Content); j += 2 {
+			name := schemas.Content[j].Value
+			i.names[name] = i.reserveName(name)
+		}
+		for j := 0; j+1 < len(schemas.Content); j += 2 {
+			name := i.names[schemas.Content[j].Value]
+			i.types.Set(name, i.convertSchema(schemas.Content[j+1], name))
+		}
+	}
+	// Paths are converted after schemas, so that references are resolved and anonymous schemas are hoisted.
+	var api, resources *yaml.Node
+	if hasPaths {
+		api = i.convertAPI(root)
+		resources = yamlMapNode()
+		for j := 0; j+1 < len(paths.Content); j += 2 {
+			yamlMapSet(resources, paths.Content[j].Value, i.convertPathItem(paths.Content[j], paths.Content[j+1]))
+		}
+	}
+	for name, value := range i.discriminatorValues {
+		decl, ok := i.types.Get(name)
+		if !ok || decl.Kind != yaml.MappingNode {
+			continue
+		}
+		if yamlMapGet(decl, "discriminatorValue") == nil {
+			yamlMapSet(decl, "discriminatorValue", yamlStrNode(value))
+		}
+	}
+
+	head := "#%RAML 1.0 Library\n"
+	library := api
+	if api != nil {
+		head = "#%RAML 1.0\n"
+	} else {
+		library = yamlMapNode()
+		if info := yamlMapGet(root, "info"); info != nil {
+			if title := yamlMapGet(info, "title"); title != nil {
+				yamlMapSet(library, "usage", yamlStrNode(title.Value))
+			}
+		}
+	}
+	if len(i.annotations) > 0 {
+		annotationTypes := yamlMapNode()
+		for _, name := range openAPIStandardAnnotations {
+			if _, ok := i.annotations[name]; ok {
+				yamlMapSet(annotationTypes, name, yamlStrNode(TypeBoolean))
+			}
+		}
+		yamlMapSet(library, "annotationTypes", annotationTypes)
+	}
+	if i.types.Len() > 0 {
+		types := yamlMapNode()
+		for pair := i.types.Oldest(); pair != nil; pair = pair.Next() {
+			yamlMapSet(types, pair.Key, pair.Value)
+		}
+		yamlMapSet(library, "types", types)
+	}
+	if resources != nil {
+		// Resources follow declarations.
+		library.Content = append(library.Content, resources.Content...)
+	}
+	out, err := yaml.Marshal(library)
+	if err != nil {
+		return nil, i.warnings, stacktrace.NewWrapped("marshal library", err, location, stacktrace.WithType(stacktrace.TypeConverting))
+	}
+	return append([]byte(head), out...), i.warnings, nil
+}
+
+// reserveName returns a unique RAML type name for the given name.
+func (i *OpenAPIImporter) reserveName(name string) string {
+	return reserveTypeName(i.types, name)
+}
+
+// hoist declares an anonymous type declaration as a named type and returns its name.
+func (i *OpenAPIImporter) hoist(decl *yaml.Node, name string) string {
+	return hoistDeclaration(i.types, decl, name)
+}
+
+// reserveTypeName returns a unique RAML type name for the given name and reserves it in types.
+func reserveTypeName(types *orderedmap.OrderedMap[string, *yaml.Node], name string) string {
+	name = invalidTypeNameChars.ReplaceAllString(name, "_")
+	if name == "" {
+		name = "Type"
+	}
+	if _, ok := SetOfScalarTypes[name]; ok || name == TypeAny || name == TypeNil || name == TypeArray ||
+		name == TypeObject || name == TypeUnion {
+		name += "_"
+	}
+	candidate := name
+	for n := 1; ; n++ {
+		if _, ok := types.Get(candidate); !ok {
+			break
+		}
+		candidate = name + strconv.Itoa(n)
+	}
+	// Placeholder keeps the name reserved until the declaration is converted.
+	types.Set(candidate, nil)
+	return candidate
+}
+
+// hoistDeclaration declares an anonymous type declaration as a named type in types and returns its name.
+func hoistDeclaration(types *orderedmap.OrderedMap[string, *yaml.Node], decl *yaml.Node, name string) string {
+	if decl.Kind == yaml.ScalarNode {
+		return decl.Value
+	}
+	name = reserveTypeName(types, name)
+	types.Set(name, decl)
+	return name
+}
+
+func (i *OpenAPIImporter) resolveRef(ref *yaml.Node) string {
+	name, ok := strings.CutPrefix(ref.Value, OpenAPIComponentsSchemasPath)
+	if !ok {
+		i.warn(ref, "only references to component schemas are supported", stacktrace.WithInfo("ref", ref.Value))
+		return TypeAny
+	}
+	// JSON Pointer escaping
+	name = strings.NewReplacer("~1", "/", "~0", "~").Replace(name)
+	typeName, ok := i.names[name]
+	if !ok {
+		i.warn(ref, "reference not found", stacktrace.WithInfo("ref", ref.Value))
+		return TypeAny
+	}
+	return typeName
+}
+
+// resolveMappingValue resolves a value of discriminator mapping, which is either a reference
+// or a bare name of a component schema.
+func (i *OpenAPIImporter) resolveMappingValue(value *yaml.Node) string {
+	if strings.ContainsAny(value.Value, "#/") {
+		return i.resolveRef(value)
+	}
+	typeName, ok := i.names[value.Value]
+	if !ok {
+		i.warn(value, "schema of discriminator mapping not found", stacktrace.WithInfo("schema", value.Value))
+		return TypeAny
+	}
+	return typeName
+}
+
+// convertSchema converts a schema object to RAML type declaration.
+// The returned node is either a type expression or a map of facets.
+func (i *OpenAPIImporter) convertSchema(schema *yaml.Node, name string) *yaml.Node {
+	if schema.Kind == yaml.ScalarNode && schema.Tag == "!!bool" {
+		if schema.Value != "true" {
+			i.warn(schema, "false schema is imported as any")
+		}
+		return yamlStrNode(TypeAny)
+	}
+	if schema.Kind != yaml.MappingNode {
+		i.warn(schema, "schema must be map")
+		return yamlStrNode(TypeAny)
+	}
+	if ref := yamlMapGet(schema, "$ref"); ref != nil {
+		for j := 0; j+1 < len(schema.Content); j += 2 {
+			if k := schema.Content[j].Value; k != "$ref" && k != "description" && k != "summary" {
+				i.warn(schema.Content[j], "keywords next to $ref are ignored", stacktrace.WithInfo("keyword", k))
+			}
+		}
+		return yamlStrNode(i.resolveRef(ref))
+	}
+	for j := 0; j+1 < len(schema.Content); j += 2 {
+		k := schema.Content[j].Value
+		if _, ok := openAPISchemaKeywords[k]; !ok {
+			i.warn(schema.Content[j], "keyword is not supported", stacktrace.WithInfo("keyword", k))
+		}
+	}
+
+	types, nullable := i.schemaTypes(schema)
+	decl := yamlMapNode()
+	switch {
+	case yamlMapGet(schema, "allOf") != nil:
+		i.convertAllOf(schema, decl, name)
+	case yamlMapGet(schema, "oneOf") != nil:
+		i.convertUnion(yamlMapGet(schema, "oneOf"), decl, name)
+	case yamlMapGet(schema, "anyOf") != nil:
+		i.convertUnion(yamlMapGet(schema, "anyOf"), decl, name)
+	case len(types) > 1:
+		i.warn(schema, "multiple types are imported as union of types without facets")
+		yamlMapSet(decl, "type", yamlStrNode(strings.Join(i.ramlTypes(types, schema), " | ")))
+	case len(types) == 1:
+		i.convertTyped(schema, types[0], decl, name)
+	case nullable:
+		yamlMapSet(decl, "type", yamlStrNode(TypeNil))
+		nullable = false
+	default:
+		if yamlMapGet(schema, "properties") != nil || yamlMapGet(schema, "additionalProperties") != nil {
+			i.convertTyped(schema, "object", decl, name)
+		} else if yamlMapGet(schema, "items") != nil {
+			i.convertTyped(schema, "array", decl, name)
+		} else {
+			yamlMapSet(decl, "type", yamlStrNode(TypeAny))
+		}
+	}
+	i.convertCommons(schema, decl)
+
+	if nullable {
+		typeName := i.hoist(compactDeclaration(decl), name+"_value")
+		return yamlStrNode(groupTypeExpression(typeName) + " | " + TypeNil)
+	}
+	if yamlMapGet(schema, "allOf") != nil {
+		// Map form makes a subtype instead of an alias.
+		return decl
+	}
+	return compactDeclaration(decl)
+}
+
+// schemaTypes returns non-null types of the schema and whether the schema allows null.
+func (i *OpenAPIImporter) schemaTypes(schema *yaml.Node) ([]string, bool) {
+	var types []string
+	var nullable bool
+	if t := yamlMapGet(schema, "type"); t != nil {
+		if t.Kind == yaml.SequenceNode {
+			for _, item := range t.Content {
+				types = append(types, item.Value)
+			}
+		} else {
+			types = append(types, t.Value)
+		}
+	}
+	if n := yamlMapGet(schema, "nullable"); n != nil && n.Value == "true" {
+		nullable = true
+	}
+	filtered := types[:0]
+	for _, t := range types {
+		if t == "null" {
+			nullable = true
+			continue
+		}
+		filtered = append(filtered, t)
+	}
+	return filtered, nullable
+}
+
+// ramlTypes maps JSON Schema types to RAML types.
+func (i *OpenAPIImporter) ramlTypes(types []string, schema *yaml.Node) []string {
+	res := make([]string, len(types))
+	for j, t := range types {
+		switch t {
+		case "string", "integer", "number", "boolean", "array", "object":
+			res[j] = t
+		default:
+			i.warn(schema, "unknown type is imported as any", stacktrace.WithInfo("type", t))
+			res[j] = TypeAny
+		}
+	}
+	return res
+}
+
+func (i *OpenAPIImporter) convertAllOf(schema *yaml.Node, decl *yaml.Node, name string) {
+	// Type goes first to keep the declaration readable, it is replaced after parents are collected.
+	yamlMapSet(decl, "type", yamlStrNode(TypeObject))
+	var parents []*yaml.Node
+	for j, member := range yamlMapGet(schema, "allOf").Content {
+		if member.Kind == yaml.MappingNode && yamlMapGet(member, "$ref") != nil {
+			parents = append(parents, i.convertSchema(member, name))
+			continue
+		}
+		memberDecl := i.convertSchema(member, name+"_"+strconv.Itoa(j))
+		if memberDecl.Kind != yaml.MappingNode {
+			parents = append(parents, memberDecl)
+			continue
+		}
+		// Inline members are merged into the declaration.
+		for k := 0; k+1 < len(memberDecl.Content); k += 2 {
+			key, value := memberDecl.Content[k].Value, memberDecl.Content[k+1]
+			if key == "type" {
+				if value.Value != TypeObject {
+					parents = append(parents, value)
+				}
+				continue
+			}
+			if existing := yamlMapGet(decl, key); existing != nil && key == "properties" {
+				existing.Content = append(existing.Content, value.Content...)
+				continue
+			}
+			yamlMapSet(decl, key, value)
+		}
+	}
+	switch len(parents) {
+	case 0:
+		yamlMapSet(decl, "type", yamlStrNode(TypeObject))
+	case 1:
+		yamlMapSet(decl, "type", parents[0])
+	default:
+		yamlMapSet(decl, "type", &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: parents})
+	}
+}
+
+func (i *OpenAPIImporter) convertUnion(members *yaml.Node, decl *yaml.Node, name string) {
+	names := make([]string, len(members.Content))
+	for j, member := range members.Content {
+		memberDecl := i.convertSchema(member, name+"_"+strconv.Itoa(j))
+		names[j] = groupTypeExpression(i.hoist(memberDecl, name+"_"+strconv.Itoa(j)))
+	}
+	yamlMapSet(decl, "type", yamlStrNode(strings.Join(names, " | ")))
+}
+
+func (i *OpenAPIImporter) convertTyped(schema *yaml.Node, t string, decl *yaml.Node, name string) {
+	format := yamlMapGet(schema, "format")
+	switch t {
+	case "string":
+		t = TypeString
+		if format != nil {
+			switch format.Value {
+			case "date-time":
+				t = TypeDatetime
+			case "date":
+				t = TypeDateOnly
+			case "time":
+				t = TypeTimeOnly
+			case "binary":
+				t = TypeFile
+			default:
+				i.warn(format, "string format is not supported", stacktrace.WithInfo("format", format.Value))
+			}
+		}
+		yamlMapSet(decl, "type", yamlStrNode(t))
+		if t == TypeString || t == TypeFile {
+			yamlMapCopy(decl, schema, "minLength", "maxLength")
+		}
+		if t == TypeString {
+			yamlMapCopy(decl, schema, "pattern")
+		}
+	case "integer":
+		yamlMapSet(decl, "type", yamlStrNode(TypeInteger))
+		if format != nil {
+			if _, ok := SetOfIntegerFormats[format.Value]; ok {
+				yamlMapSet(decl, "format", yamlStrNode(format.Value))
+			} else {
+				i.warn(format, "integer format is not supported", stacktrace.WithInfo("format", format.Value))
+			}
+		}
+		i.convertIntegerBounds(schema, decl)
+		yamlMapCopy(decl, schema, "multipleOf")
+	case "number":
+		yamlMapSet(decl, "type", yamlStrNode(TypeNumber))
+		if format != nil {
+			if _, ok := SetOfNumberFormats[format.Value]; ok {
+				yamlMapSet(decl, "format", yamlStrNode(format.Value))
+			} else {
+				i.warn(format, "number format is not supported", stacktrace.WithInfo("format", format.Value))
+			}
+		}
+		yamlMapCopy(decl, schema, "minimum", "maximum", "multipleOf")
+		for _, k := range []string{"exclusiveMinimum", "exclusiveMaximum"} {
+			if v := yamlMapGet(schema, k); v != nil && v.Value != "false" {
+				i.warn(v, "exclusive bound is imported as inclusive", stacktrace.WithInfo("keyword", k))
+				if v.Tag != "!!bool" {
+					yamlMapSet(decl, strings.ToLower(k[9:10])+k[10:], v)
+				}
+			}
+		}
+	case "boolean":
+		yamlMapSet(decl, "type", yamlStrNode(TypeBoolean))
+	case "null":
+		yamlMapSet(decl, "type", yamlStrNode(TypeNil))
+	case "array":
+		yamlMapSet(decl, "type", yamlStrNode(TypeArray))
+		if items := yamlMapGet(schema, "items"); items != nil {
+			yamlMapSet(decl, "items", i.convertSchema(items, name+"_item"))
+		}
+		yamlMapCopy(decl, schema, "minItems", "maxItems", "uniqueItems")
+	case "object":
+		yamlMapSet(decl, "type", yamlStrNode(TypeObject))
+		i.convertObject(schema, decl, name)
+	default:
+		i.warn(schema, "unknown type is imported as any", stacktrace.WithInfo("type", t))
+		yamlMapSet(decl, "type", yamlStrNode(TypeAny))
+	}
+}
+
+// convertIntegerBounds converts integer bounds making exclusive bounds inclusive.
+func (i *OpenAPIImporter) convertIntegerBounds(schema *yaml.Node, decl *yaml.Node) {
+	bounds := []struct {
+		key       string
+		exclusive string
+		delta     int64
+	}{
+		{"minimum", "exclusiveMinimum", 1},
+		{"maximum", "exclusiveMaximum", -1},
+	}
+	for _, b := range bounds {
+		value := yamlMapGet(schema, b.key)
+		exclusive := yamlMapGet(schema, b.exclusive)
+		var delta int64
+		if exclusive != nil {
+			if exclusive.Tag == "!!bool" {
+				// OpenAPI 3.0 style
+				if exclusive.Value == "true" {
+					delta = b.delta
+				}
+			} else {
+				// OpenAPI 3.1 style
+				value = exclusive
+				delta = b.delta
+			}
+		}
+		if value == nil {
+			continue
+		}
+		num, ok := new(big.Float).SetString(value.Value)
+		if !ok {
+			i.warn(value, "invalid bound", stacktrace.WithInfo("keyword", b.key))
+			continue
+		}
+		bound, acc := num.Int(nil)
+		if acc != big.Exact {
+			// The closest integer inside the bound.
+			if b.delta > 0 && acc == big.Below {
+				bound.Add(bound, big.NewInt(1))
+			} else if b.delta < 0 && acc == big.Above {
+				bound.Sub(bound, big.NewInt(1))
+			}
+			delta = 0
+		}
+		bound.Add(bound, big.NewInt(delta))
+		yamlMapSet(decl, b.key, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: bound.String()})
+	}
+}
+
+func (i *OpenAPIImporter) convertObject(schema *yaml.Node, decl *yaml.Node, name string) {
+	required := make(map[string]struct{})
+	if r := yamlMapGet(schema, "required"); r != nil {
+		for _, item := range r.Content {
+			required[item.Value] = struct{}{}
+		}
+	}
+	properties := yamlMapNode()
+	if p := yamlMapGet(schema, "properties"); p != nil {
+		for j := 0; j+1 < len(p.Content); j += 2 {
+			key := p.Content[j]
+			if strings.HasPrefix(key.Value, "/") && strings.HasSuffix(key.Value, "/") {
+				i.warn(key, "property name is reserved for pattern properties and is skipped", stacktrace.WithInfo("property", key.Value))
+				continue
+			}
+			propDecl := i.convertSchema(p.Content[j+1], name+"_"+invalidTypeNameChars.ReplaceAllString(key.Value, "_"))
+			if _, ok := required[key.Value]; !ok {
+				if propDecl.Kind != yaml.MappingNode {
+					propDecl = yamlMapNode("type", propDecl)
+				}
+				yamlMapSet(propDecl, "required", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "false"})
+			}
+			yamlMapSet(properties, key.Value, propDecl)
+		}
+	}
+	if pp := yamlMapGet(schema, "patternProperties"); pp != nil {
+		for j := 0; j+1 < len(pp.Content); j += 2 {
+			pattern := pp.Content[j].Value
+			yamlMapSet(properties, "/"+pattern+"/", i.convertSchema(pp.Content[j+1], name+"_pattern"))
+		}
+	}
+	if ap := yamlMapGet(schema, "additionalProperties"); ap != nil {
+		if ap.Tag == "!!bool" {
+			yamlMapSet(decl, "additionalProperties", ap)
+		} else {
+			yamlMapSet(properties, "//", i.convertSchema(ap, name+"_additional"))
+		}
+	}
+	if len(properties.Content) > 0 {
+		yamlMapSet(decl, "properties", properties)
+	}
+	yamlMapCopy(decl, schema, "minProperties", "maxProperties")
+	if d := yamlMapGet(schema, "discriminator"); d != nil {
+		if pn := yamlMapGet(d, "propertyName"); pn != nil {
+			yamlMapSet(decl, "discriminator", yamlStrNode(pn.Value))
+		}
+		if mapping := yamlMapGet(d, "mapping"); mapping != nil {
+			for j := 0; j+1 < len(mapping.Content); j += 2 {
+				typeName := i.resolveMappingValue(mapping.Content[j+1])
+				i.discriminatorValues[typeName] = mapping.Content[j].Value
+			}
+		}
+	}
+}
+
+// convertCommons converts keywords that are applicable to any type.
+func (i *OpenAPIImporter) convertCommons(schema *yaml.Node, decl *yaml.Node) {
+	if title := yamlMapGet(schema, "title"); title != nil {
+		yamlMapSet(decl, "displayName", title)
+	}
+	yamlMapCopy(decl, schema, "description", "default", "example", "enum")
+	if c := yamlMapGet(schema, "const"); c != nil {
+		yamlMapSet(decl, "enum", &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{c}})
+	}
+	if examples := yamlMapGet(schema, "examples"); examples != nil && yamlMapGet(schema, "example") == nil {
+		if examples.Kind == yaml.SequenceNode {
+			m := yamlMapNode()
+			for j, ex := range examples.Content {
+				yamlMapSet(m, "example"+strconv.Itoa(j+1), ex)
+			}
+			yamlMapSet(decl, "examples", m)
+		} else {
+			i.warn(examples, "examples must be sequence")
+		}
+	}
+	for _, name := range openAPIStandardAnnotations {
+		if v := yamlMapGet(schema, name); v != nil && v.Value == "true" {
+			i.annotations[name] = struct{}{}
+			yamlMapSet(decl, "("+name+")", v)
+		}
+	}
+}
+
+// openAPIMethods lists operations of path items that have RAML counterparts.
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+// openAPIOAuthFlows maps OAuth flows to RAML authorization grants.
+var openAPIOAuthFlows = []struct{ flow, grant string }{
+	{"authorizationCode", "authorization_code"},
+	{"implicit", "implicit"},
+	{"password", "password"},
+	{"clientCredentials", "client_credentials"},
+}
+
+// convertAPI converts the root of the document to the root of RAML API definition.
+// Resources are converted separately since they may hoist types.
+func (i *OpenAPIImporter) convertAPI(root *yaml.Node) *yaml.Node {
+	api := yamlMapNode()
+	info := yamlMapGet(root, "info")
+	title := "API"
+	if t := yamlMapGet(info, "title"); t != nil && t.Value != "" {
+		title = t.Value
+	}
+	yamlMapSet(api, "title", yamlStrNode(title))
+	yamlMapCopy(api, info, "description")
+	if version := yamlMapGet(info, "version"); version != nil {
+		yamlMapSet(api, "version", yamlStrNode(version.Value))
+	}
+	if servers := yamlMapGet(root, "servers"); servers != nil && len(servers.Content) > 0 {
+		if len(servers.Content) > 1 {
+			i.warn(servers.Content[1], "only the first server is imported as base URI")
+		}
+		i.convertServer(api, servers.Content[0])
+	}
+	if schemes := yamlMapGet(i.components, "securitySchemes"); schemes != nil {
+		if decls := i.convertSecuritySchemes(schemes); len(decls.Content) > 0 {
+			yamlMapSet(api, "securitySchemes", decls)
+		}
+	}
+	if security := yamlMapGet(root, "security"); security != nil {
+		if securedBy := i.convertSecurity(security); len(securedBy.Content) > 0 {
+			yamlMapSet(api, "securedBy", securedBy)
+		}
+	}
+	return api
+}
+
+// convertServer converts a server object to base URI and its parameters.
+func (i *OpenAPIImporter) convertServer(api *yaml.Node, server *yaml.Node) {
+	url := yamlMapGet(server, "url")
+	if url == nil {
+		i.warn(server, "server must declare URL")
+		return
+	}
+	yamlMapSet(api, "baseUri", yamlStrNode(url.Value))
+	params := yamlMapNode()
+	if variables := yamlMapGet(server, "variables"); variables != nil {
+		for j := 0; j+1 < len(variables.Content); j += 2 {
+			decl := yamlMapNode("type", yamlStrNode(TypeString))
+			yamlMapCopy(decl, variables.Content[j+1], "description", "enum", "default")
+			yamlMapSet(params, variables.Content[j].Value, compactDeclaration(decl))
+		}
+	}
+	if len(params.Content) > 0 {
+		yamlMapSet(api, "baseUriParameters", params)
+	}
+}
+
+// convertSecuritySchemes converts security schemes that have RAML counterparts.
+func (i *OpenAPIImporter) convertSecuritySchemes(schemes *yaml.Node) *yaml.Node {
+	decls := yamlMapNode()
+	for j := 0; j+1 < len(schemes.Content); j += 2 {
+		key := schemes.Content[j]
+		scheme := i.resolveComponent(schemes.Content[j+1], "securitySchemes")
+		if scheme == nil {
+			continue
+		}
+		decl := i.convertSecurityScheme(key, scheme)
+		if decl == nil {
+			continue
+		}
+		yamlMapCopy(decl, scheme, "description")
+		i.securitySchemes[key.Value] = struct{}{}
+		yamlMapSet(decls, key.Value, decl)
+	}
+	return decls
+}
+
+func (i *OpenAPIImporter) convertSecurityScheme(key *yaml.Node, scheme *yaml.Node) *yaml.Node {
+	switch t := yamlNodeValue(yamlMapGet(scheme, "type")); t {
+	case "http":
+		switch httpScheme := strings.ToLower(yamlNodeValue(yamlMapGet(scheme, "scheme"))); httpScheme {
+		case "basic":
+			return yamlMapNode("type", yamlStrNode("Basic Authentication"))
+		case "digest":
+			return yamlMapNode("type", yamlStrNode("Digest Authentication"))
+		case "":
+			i.warn(key, "HTTP security scheme must declare scheme", stacktrace.WithInfo("name", key.Value))
+			return nil
+		default:
+			// Other HTTP schemes, such as bearer, become custom schemes that pass credentials in Authorization header.
+			return yamlMapNode(
+				"type", yamlStrNode("x-"+httpScheme),
+				"describedBy", yamlMapNode("headers", yamlMapNode("Authorization", yamlStrNode(TypeString))),
+			)
+		}
+	case "apiKey":
+		var params string
+		switch in := yamlNodeValue(yamlMapGet(scheme, "in")); in {
+		case "header":
+			params = "headers"
+		case "query":
+			params = "queryParameters"
+		default:
+			i.warn(key, "API key security scheme must pass key in header or query", stacktrace.WithInfo("name", key.Value),
+				stacktrace.WithInfo("in", in))
+			return nil
+		}
+		name := yamlNodeValue(yamlMapGet(scheme, "name"))
+		return yamlMapNode(
+			"type", yamlStrNode("Pass Through"),
+			"describedBy", yamlMapNode(params, yamlMapNode(name, yamlStrNode(TypeString))),
+		)
+	case "oauth2":
+		return yamlMapNode("type", yamlStrNode("OAuth 2.0"), "settings", i.convertOAuthFlows(yamlMapGet(scheme, "flows")))
+	default:
+		i.warn(key, "security scheme type has no RAML counterpart and is skipped", stacktrace.WithInfo("name", key.Value),
+			stacktrace.WithInfo("type", t))
+		return nil
+	}
+}
+
+// convertOAuthFlows converts OAuth flows to settings of OAuth 2.0 security scheme.
+// RAML settings have a single pair of URIs for all grants, so URIs of the first flow are kept.
+func (i *OpenAPIImporter) convertOAuthFlows(flows *yaml.Node) *yaml.Node {
+	var authorizationURL, tokenURL *yaml.Node
+	merge := func(current *yaml.Node, flow *yaml.Node, key string) *yaml.Node {
+		url := yamlMapGet(flow, key)
+		switch {
+		case url == nil:
+			return current
+		case current == nil:
+			return url
+		case current.Value != url.Value:
+			i.warn(url, "OAuth flows must share URLs, URL of the first flow is imported", stacktrace.WithInfo("url", url.Value))
+		}
+		return current
+	}
+	grants := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	scopes := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	seen := make(map[string]struct{})
+	for _, f := range openAPIOAuthFlows {
+		flow := yamlMapGet(flows, f.flow)
+		if flow == nil {
+			continue
+		}
+		grants.Content = append(grants.Content, yamlStrNode(f.grant))
+		authorizationURL = merge(authorizationURL, flow, "authorizationUrl")
+		tokenURL = merge(tokenURL, flow, "tokenUrl")
+		s := yamlMapGet(flow, "scopes")
+		for j := 0; s != nil && j+1 < len(s.Content); j += 2 {
+			if _, ok := seen[s.Content[j].Value]; !ok {
+				seen[s.Content[j].Value] = struct{}{}
+				scopes.Content = append(scopes.Content, yamlStrNode(s.Content[j].Value))
+			}
+		}
+	}
+	settings := yamlMapNode()
+	if authorizationURL != nil {
+		yamlMapSet(settings, "authorizationUri", yamlStrNode(authorizationURL.Value))
+	}
+	if tokenURL != nil {
+		yamlMapSet(settings, "accessTokenUri", yamlStrNode(tokenURL.Value))
+	}
+	yamlMapSet(settings, "authorizationGrants", grants)
+	if len(scopes.Content) > 0 {
+		yamlMapSet(settings, "scopes", scopes)
+	}
+	return settings
+}
+
+// convertSecurity converts security requirements to references to security schemes.
+// An empty requirement allows anonymous access and becomes null reference.
+func (i *OpenAPIImporter) convertSecurity(security *yaml.Node) *yaml.Node {
+	securedBy := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	for _, req := range security.Content {
+		switch {
+		case len(req.Content) == 0:
+			securedBy.Content = append(securedBy.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"})
+		case len(req.Content) > 2:
+			i.warn(req, "security requirement with several schemes has no RAML counterpart and is skipped")
+		default:
+			name := req.Content[0].Value
+			if _, ok := i.securitySchemes[name]; !ok {
+				i.warn(req.Content[0], "security requirement refers to security scheme that is not imported",
+					stacktrace.WithInfo("name", name))
+				continue
+			}
+			if scopes := req.Content[1]; len(scopes.Content) > 0 {
+				securedBy.Content = append(securedBy.Content, yamlMapNode(name, yamlMapNode("scopes", scopes)))
+			} else {
+				securedBy.Content = append(securedBy.Content, yamlStrNode(name))
+			}
+		}
+	}
+	return securedBy
+}
+
+// convertPathItem converts a path item to a resource. Paths are flat, so resources are not nested.
+func (i *OpenAPIImporter) convertPathItem(key *yaml.Node, item *yaml.Node) *yaml.Node {
+	resource := yamlMapNode()
+	if ref := yamlMapGet(item, "$ref"); ref != nil {
+		i.warn(ref, "references to path items are not supported", stacktrace.WithInfo("ref", ref.Value))
+		return resource
+	}
+	if summary := yamlMapGet(item, "summary"); summary != nil {
+		yamlMapSet(resource, "displayName", summary)
+	}
+	yamlMapCopy(resource, item, "description")
+
+	name := strings.Trim(invalidTypeNameChars.ReplaceAllString(key.Value, "_"), "_")
+	uriParameters := yamlMapNode()
+	common := i.resolveParameters(yamlMapGet(item, "parameters"))
+	for _, param := range common {
+		if yamlNodeValue(yamlMapGet(param, "in")) == "path" {
+			paramName := yamlNodeValue(yamlMapGet(param, "name"))
+			yamlMapSet(uriParameters, paramName, i.convertParameter(param, name+"_"+paramName))
+		}
+	}
+	methods := yamlMapNode()
+	for j := 0; j+1 < len(item.Content); j += 2 {
+		k := item.Content[j]
+		switch {
+		case slices.Contains(openAPIMethods, k.Value):
+			yamlMapSet(methods, k.Value, i.convertOperation(k.Value, name, item.Content[j+1], common, uriParameters))
+		case k.Value == "trace":
+			i.warn(k, "method has no RAML counterpart and is skipped", stacktrace.WithInfo("method", k.Value))
+		case k.Value == "servers":
+			i.warn(k, "servers of paths are not imported")
+		}
+	}
+	if len(uriParameters.Content) > 0 {
+		yamlMapSet(resource, "uriParameters", uriParameters)
+	}
+	resource.Content = append(resource.Content, methods.Content...)
+	return resource
+}
+
+// convertOperation converts an operation to a method. Path parameters are collected into URI parameters
+// of the resource.
+func (i *OpenAPIImporter) convertOperation(method string, path string, op *yaml.Node, common []*yaml.Node,
+	uriParameters *yaml.Node) *yaml.Node {
+	decl := yamlMapNode()
+	name := yamlNodeValue(yamlMapGet(op, "operationId"))
+	if name == "" {
+		name = method + "_" + path
+	}
+	if summary := yamlMapGet(op, "summary"); summary != nil {
+		yamlMapSet(decl, "displayName", summary)
+	}
+	yamlMapCopy(decl, op, "description")
+	if deprecated := yamlMapGet(op, "deprecated"); deprecated != nil && deprecated.Value == "true" {
+		i.annotations["deprecated"] = struct{}{}
+		yamlMapSet(decl, "(deprecated)", deprecated)
+	}
+
+	queryParameters := yamlMapNode()
+	headers := yamlMapNode()
+	for _, param := range i.operationParameters(common, yamlMapGet(op, "parameters")) {
+		paramName := yamlNodeValue(yamlMapGet(param, "name"))
+		switch in := yamlNodeValue(yamlMapGet(param, "in")); in {
+		case "path":
+			if yamlMapGet(uriParameters, paramName) == nil {
+				yamlMapSet(uriParameters, paramName, i.convertParameter(param, name+"_"+paramName))
+			}
+		case "query":
+			yamlMapSet(queryParameters, paramName, i.convertParameter(param, name+"_"+paramName))
+		case "header":
+			yamlMapSet(headers, paramName, i.convertParameter(param, name+"_"+paramName))
+		default:
+			i.warn(param, "parameter location has no RAML counterpart and is skipped", stacktrace.WithInfo("name", paramName),
+				stacktrace.WithInfo("in", in))
+		}
+	}
+	if len(queryParameters.Content) > 0 {
+		yamlMapSet(decl, "queryParameters", queryParameters)
+	}
+	if len(headers.Content) > 0 {
+		yamlMapSet(decl, "headers", headers)
+	}
+	if requestBody := yamlMapGet(op, "requestBody"); requestBody != nil {
+		if requestBody = i.resolveComponent(requestBody, "requestBodies"); requestBody != nil {
+			if body := i.convertContent(yamlMapGet(requestBody, "content"), name+"_request"); body != nil {
+				yamlMapSet(decl, "body", body)
+			}
+		}
+	}
+	if responses := yamlMapGet(op, "responses"); responses != nil {
+		if decls := i.convertResponses(responses, name); len(decls.Content) > 0 {
+			yamlMapSet(decl, "responses", decls)
+		}
+	}
+	if security := yamlMapGet(op, "security"); security != nil {
+		securedBy := i.convertSecurity(security)
+		if len(security.Content) == 0 {
+			// Empty security overrides security of the API.
+			securedBy.Content = append(securedBy.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"})
+		}
+		if len(securedBy.Content) > 0 {
+			yamlMapSet(decl, "securedBy", securedBy)
+		}
+	}
+	for _, k := range []string{"callbacks", "servers"} {
+		if v := yamlMapGet(op, k); v != nil {
+			i.warn(v, "operation "+k+" are not imported")
+		}
+	}
+	return decl
+}
+
+// operationParameters returns parameters of the path item followed by parameters of the operation.
+// Operation parameters override path item parameters with the same name and location.
+func (i *OpenAPIImporter) operationParameters(common []*yaml.Node, params *yaml.Node) []*yaml.Node {
+	own := i.resolveParameters(params)
+	var merged []*yaml.Node
+	for _, c := range common {
+		overridden := slices.ContainsFunc(own, func(p *yaml.Node) bool {
+			return yamlNodeValue(yamlMapGet(p, "name")) == yamlNodeValue(yamlMapGet(c, "name")) &&
+				yamlNodeValue(yamlMapGet(p, "in")) == yamlNodeValue(yamlMapGet(c, "in"))
+		})
+		if !overridden {
+			merged = append(merged, c)
+		}
+	}
+	return append(merged, own...)
+}
+
+func (i *OpenAPIImporter) resolveParameters(params *yaml.Node) []*yaml.Node {
+	if params == nil {
+		return nil
+	}
+	resolved := make([]*yaml.Node, 0, len(params.Content))
+	for _, param := range params.Content {
+		if param = i.resolveComponent(param, "parameters"); param != nil {
+			resolved = append(resolved, param)
+		}
+	}
+	return resolved
+}
+
+// convertParameter converts a parameter or header object to RAML parameter declaration.
+// Path parameters are always required, others are optional unless required.
+func (i *OpenAPIImporter) convertParameter(param *yaml.Node, name string) *yaml.Node {
+	var decl *yaml.Node
+	if schema := yamlMapGet(param, "schema"); schema != nil {
+		decl = i.convertSchema(schema, name)
+	} else {
+		i.warn(param, "parameter without schema is imported as string")
+		decl = yamlStrNode(TypeString)
+	}
+	description := yamlMapGet(param, "description")
+	required := yamlNodeValue(yamlMapGet(param, "in")) == "path" || yamlNodeValue(yamlMapGet(param, "required")) == "true"
+	if description == nil && required {
+		return decl
+	}
+	if decl.Kind != yaml.MappingNode {
+		decl = yamlMapNode("type", decl)
+	}
+	if description != nil {
+		yamlMapSet(decl, "description", description)
+	}
+	if !required {
+		yamlMapSet(decl, "required", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "false"})
+	}
+	return decl
+}
+
+// convertContent converts content of request body or response to bodies keyed by media type.
+func (i *OpenAPIImporter) convertContent(content *yaml.Node, name string) *yaml.Node {
+	if content == nil || len(content.Content) == 0 {
+		return nil
+	}
+	body := yamlMapNode()
+	for j := 0; j+1 < len(content.Content); j += 2 {
+		media := content.Content[j+1]
+		decl := yamlStrNode(TypeAny)
+		if schema := yamlMapGet(media, "schema"); schema != nil {
+			decl = i.convertSchema(schema, name)
+		}
+		if example := yamlMapGet(media, "example"); example != nil {
+			if decl.Kind != yaml.MappingNode {
+				decl = yamlMapNode("type", decl)
+			}
+			yamlMapSet(decl, "example", example)
+		}
+		yamlMapSet(body, content.Content[j].Value, decl)
+	}
+	return body
+}
+
+// convertResponses converts responses with numeric status codes.
+func (i *OpenAPIImporter) convertResponses(responses *yaml.Node, name string) *yaml.Node {
+	decls := yamlMapNode()
+	for j := 0; j+1 < len(responses.Content); j += 2 {
+		code := responses.Content[j]
+		if _, err := strconv.Atoi(code.Value); err != nil {
+			i.warn(code, "response code has no RAML counterpart and is skipped", stacktrace.WithInfo("code", code.Value))
+			continue
+		}
+		response := i.resolveComponent(responses.Content[j+1], "responses")
+		if response == nil {
+			continue
+		}
+		decl := yamlMapNode()
+		yamlMapCopy(decl, response, "description")
+		if headers := yamlMapGet(response, "headers"); headers != nil {
+			headerDecls := yamlMapNode()
+			for k := 0; k+1 < len(headers.Content); k += 2 {
+				if header := i.resolveComponent(headers.Content[k+1], "headers"); header != nil {
+					headerName := headers.Content[k].Value
+					yamlMapSet(headerDecls, headerName, i.convertParameter(header, name+"_"+code.Value+"_"+headerName))
+				}
+			}
+			if len(headerDecls.Content) > 0 {
+				yamlMapSet(decl, "headers", headerDecls)
+			}
+		}
+		if body := i.convertContent(yamlMapGet(response, "content"), name+"_"+code.Value); body != nil {
+			yamlMapSet(decl, "body", body)
+		}
+		if links := yamlMapGet(response, "links"); links != nil {
+			i.warn(links, "response links are not imported")
+		}
+		yamlMapSet(decls, code.Value, decl)
+	}
+	return decls
+}
+
+// resolveComponent resolves a reference to a component of the given kind.
+// Objects without references are returned as is, nil is returned if the reference cannot be resolved.
+func (i *OpenAPIImporter) resolveComponent(node *yaml.Node, kind string) *yaml.Node {
+	seen := make(map[string]struct{})
+	for {
+		ref := yamlMapGet(node, "$ref")
+		if ref == nil {
+			return node
+		}
+		name, ok := strings.CutPrefix(ref.Value, "#/components/"+kind+"/")
+		if !ok {
+			i.warn(ref, "only references to components of the same kind are supported", stacktrace.WithInfo("ref", ref.Value))
+			return nil
+		}
+		if _, ok := seen[name]; ok {
+			i.warn(ref, "circular reference", stacktrace.WithInfo("ref", ref.Value))
+			return nil
+		}
+		seen[name] = struct{}{}
+		// JSON Pointer escaping
+		name = strings.NewReplacer("~1", "/", "~0", "~").Replace(name)
+		if node = yamlMapGet(yamlMapGet(i.components, kind), name); node == nil {
+			i.warn(ref, "reference not found", stacktrace.WithInfo("ref", ref.Value))
+			return nil
+		}
+	}
+}
+
+func (i *OpenAPIImporter) warn(node *yaml.Node, message string, opts ...stacktrace.Option) {
+	opts = append(opts,
+		stacktrace.WithNodePosition(node),
+		stacktrace.WithSeverity(stacktrace.SeverityWarning),
+		stacktrace.WithType(stacktrace.TypeConverting),
+	)
+	i.warnings = append(i.warnings, stacktrace.New(message, i.location, opts...))
+}
+
+// compactDeclaration returns the type expression if the declaration has no facets.
+func compactDeclaration(decl *yaml.Node) *yaml.Node {
+	if len(decl.Content) == 2 && decl.Content[0].Value == "type" && decl.Content[1].Kind == yaml.ScalarNode {
+		return decl.Content[1]
+	}
+	return decl
+}
+
+func groupTypeExpression(expr string) string {
+	if strings.ContainsAny(expr, "| ") {
+		return "(" + expr + ")"
+	}
+	return expr
+}
+
+func yamlNodeValue(node *yaml.Node) string {
+	if node == nil {
+		return ""
+	}
+	return node.Value
+}
+
+func yamlStrNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// yamlMapNode creates a mapping node from key-value pairs.
+func yamlMapNode(pairs ...any) *yaml.Node {
+	m := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for j := 0; j+1 < len(pairs); j += 2 {
+		yamlMapSet(m, pairs[j].(string), pairs[j+1].(*yaml.Node))
+	}
+	return m
+}
+
+func yamlMapGet(m *yaml.Node, key string) *yaml.Node {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil
+	}
+	for j := 0; j+1 < len(m.Content); j += 2 {
+		if m.Content[j].Value == key {
+			return m.Content[j+1]
+		}
+	}
+	return nil
+}
+
+func yamlMapSet(m *yaml.Node, key string, value *yaml.Node) {
+	for j := 0; j+1 < len(m.Content); j += 2 {
+		if m.Content[j].Value == key {
+			m.Content[j+1] = value
+			return
+		}
+	}
+	m.Content = append(m.Content, yamlStrNode(key), value)
+}
+
+// yamlMapCopy copies given keys from source map to target map if present.
+func yamlMapCopy(target *yaml.Node, source *yaml.Node, keys ...string) {
+	for _, k := range keys {
+		if v := yamlMapGet(source, k); v != nil {
+			yamlMapSet(target, k, v)
+		}
+	}
+}
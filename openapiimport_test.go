@@ -0,0 +1,289 @@
+package raml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestOpenAPIImporter_ConvertToRAML(t *testing.T) {
+	tests := []struct {
+		name         string
+		doc          string
+		wantTypes    map[string]any
+		wantWarnings []string
+	}{
+		{
+			name: "scalar schemas",
+			doc: `openapi: 3.1.0
+components:
+  schemas:
+    Name:
+      type: string
+      minLength: 1
+    Age:
+      type: integer
+      format: int32
+      minimum: 0
+`,
+			wantTypes: map[string]any{
+				"Name": map[string]any{"type": "string", "minLength": 1},
+				"Age":  map[string]any{"type": "integer", "format": "int32", "minimum": 0},
+			},
+		},
+		{
+			name: "discriminator mapping with references",
+			doc: `openapi: 3.0.3
+components:
+  schemas:
+    Pet:
+      type: object
+      discriminator:
+        propertyName: kind
+        mapping:
+          cat: '#/components/schemas/Cat'
+      properties:
+        kind:
+          type: string
+    Cat:
+      allOf:
+        - $ref: '#/components/schemas/Pet'
+`,
+			wantTypes: map[string]any{
+				"Pet": map[string]any{
+					"type":          "object",
+					"discriminator": "kind",
+					"properties":    map[string]any{"kind": map[string]any{"type": "string", "required": false}},
+				},
+				"Cat": map[string]any{"type": "Pet", "discriminatorValue": "cat"},
+			},
+		},
+		{
+			name: "discriminator mapping with bare names",
+			doc: `openapi: 3.0.3
+components:
+  schemas:
+    Pet:
+      type: object
+      discriminator:
+        propertyName: kind
+        mapping:
+          cat: Cat
+          dog: Unknown
+      properties:
+        kind:
+          type: string
+    Cat:
+      allOf:
+        - $ref: '#/components/schemas/Pet'
+`,
+			wantTypes: map[string]any{
+				"Pet": map[string]any{
+					"type":          "object",
+					"discriminator": "kind",
+					"properties":    map[string]any{"kind": map[string]any{"type": "string", "required": false}},
+				},
+				"Cat": map[string]any{"type": "Pet", "discriminatorValue": "cat"},
+			},
+			wantWarnings: []string{"schema of discriminator mapping not found"},
+		},
+		{
+			name: "anonymous schemas of paths are hoisted",
+			doc: `openapi: 3.1.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - {name: session, in: cookie, schema: {type: string}}
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: [object, "null"]
+                properties:
+                  name: {type: string}
+        default:
+          description: error
+    trace: {}
+`,
+			wantTypes: map[string]any{
+				"listPets_200_value": map[string]any{
+					"type":       "object",
+					"properties": map[string]any{"name": map[string]any{"type": "string", "required": false}},
+				},
+			},
+			wantWarnings: []string{
+				"parameter location has no RAML counterpart and is skipped",
+				"response code has no RAML counterpart and is skipped",
+				"method has no RAML counterpart and is skipped",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, warnings, err := NewOpenAPIImporter().ConvertToRAML([]byte(tt.doc), "/tmp/openapi.yaml")
+			require.NoError(t, err)
+			var lib struct {
+				Types map[string]any `yaml:"types"`
+			}
+			require.NoError(t, yaml.Unmarshal(out, &lib))
+			if tt.wantTypes == nil {
+				require.Empty(t, lib.Types)
+			} else {
+				require.Equal(t, tt.wantTypes, lib.Types)
+			}
+			var messages []string
+			for _, w := range warnings {
+				messages = append(messages, w.Message)
+			}
+			require.Equal(t, tt.wantWarnings, messages)
+		})
+	}
+}
+
+const testOpenAPI = `openapi: 3.0.3
+info:
+  title: Pets
+  version: v1
+servers:
+  - url: https://api.example.com/v1
+security:
+  - oauth: [read]
+paths:
+  /pets:
+    get:
+      summary: List pets
+      parameters:
+        - $ref: '#/components/parameters/limit'
+      responses:
+        '200':
+          description: Pets
+          headers:
+            X-Total:
+              schema: {type: integer}
+          content:
+            application/json:
+              schema:
+                type: array
+                items: {$ref: '#/components/schemas/Pet'}
+    post:
+      security: []
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Pet'}
+      responses:
+        '201':
+          description: Created
+  /pets/{id}:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: string}}
+    get:
+      security:
+        - key: []
+      responses:
+        '200':
+          $ref: '#/components/responses/Pet'
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+  parameters:
+    limit:
+      name: limit
+      in: query
+      description: Page size
+      schema: {type: integer, maximum: 100}
+  responses:
+    Pet:
+      description: Pet
+      content:
+        application/json:
+          schema: {$ref: '#/components/schemas/Pet'}
+  securitySchemes:
+    oauth:
+      type: oauth2
+      flows:
+        authorizationCode:
+          authorizationUrl: https://auth.example.com/authorize
+          tokenUrl: https://auth.example.com/token
+          scopes: {read: Read pets}
+    key:
+      type: apiKey
+      in: header
+      name: X-Api-Key
+    cookie:
+      type: apiKey
+      in: cookie
+      name: session
+`
+
+func TestOpenAPIImporter_ConvertToRAML_API(t *testing.T) {
+	out, warnings, err := NewOpenAPIImporter().ConvertToRAML([]byte(testOpenAPI), "/tmp/openapi.yaml")
+	require.NoError(t, err)
+	var messages []string
+	for _, w := range warnings {
+		messages = append(messages, w.Message)
+	}
+	require.Equal(t, []string{"API key security scheme must pass key in header or query"}, messages)
+
+	rml := parseLibrary(t, string(out))
+	api, ok := rml.EntryPoint().(*API)
+	require.True(t, ok, "entry point must be an API")
+	require.Equal(t, "Pets", api.Title)
+	require.Equal(t, "https://api.example.com/v1", api.BaseURI)
+	require.Equal(t, []*SecuredBy{{Name: "oauth", Scopes: []string{"read"}, Position: api.SecuredBy[0].Position}}, api.SecuredBy)
+	oauth, ok := api.SecuritySchemes.Get("oauth")
+	require.True(t, ok)
+	require.Equal(t, "OAuth 2.0", oauth.Type)
+	require.Equal(t, []interface{}{"authorization_code"}, oauth.Settings["authorizationGrants"])
+	key, ok := api.SecuritySchemes.Get("key")
+	require.True(t, ok)
+	require.Equal(t, "Pass Through", key.Type)
+
+	pets, ok := api.Resources.Get("/pets")
+	require.True(t, ok)
+	list, ok := pets.Methods.Get("get")
+	require.True(t, ok)
+	require.Equal(t, "List pets", list.DisplayName)
+	limit, ok := list.QueryParameters.Get("limit")
+	require.True(t, ok)
+	require.False(t, limit.Required)
+	require.Equal(t, "Page size", *(*limit.Shape).Base().Description)
+	resp, ok := list.Responses.Get("200")
+	require.True(t, ok)
+	_, ok = resp.Headers.Get("X-Total")
+	require.True(t, ok)
+	body, ok := resp.Body.Get("application/json")
+	require.True(t, ok)
+	require.IsType(t, &ArrayShape{}, *body.Shape)
+	create, ok := pets.Methods.Get("post")
+	require.True(t, ok)
+	require.Equal(t, []*SecuredBy{{Position: create.SecuredBy[0].Position}}, create.SecuredBy)
+
+	pet, ok := api.Resources.Get("/pets/{id}")
+	require.True(t, ok)
+	_, ok = pet.URIParameters.Get("id")
+	require.True(t, ok)
+	get, ok := pet.Methods.Get("get")
+	require.True(t, ok)
+	require.Equal(t, "key", get.SecuredBy[0].Name)
+	resp, ok = get.Responses.Get("200")
+	require.True(t, ok)
+	require.Equal(t, "Pet", resp.Description)
+
+	doc, warnings := NewOpenAPISchemaExporter().ExportAPI(api)
+	require.Empty(t, warnings)
+	var paths []string
+	for pair := doc.Paths.Oldest(); pair != nil; pair = pair.Next() {
+		paths = append(paths, pair.Key)
+	}
+	require.Equal(t, []string{"/pets", "/pets/{id}"}, paths)
+}
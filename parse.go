@@ -42,6 +42,8 @@ func IdentifyFragment(head string) (FragmentKind, error) {
 		return FragmentDataType, nil
 	case "#%RAML 1.0 NamedExample":
 		return FragmentNamedExample, nil
+	case "#%RAML 1.0":
+		return FragmentAPI, nil
 	default:
 		return FragmentUnknown, fmt.Errorf("unknown fragment kind: head: %s", head)
 	}
@@ -71,6 +73,18 @@ func (r *RAML) decodeDataType(f io.Reader, path string) (*DataType, error) {
 		r.PutFragment(path, dt)
 		return dt, nil
 	}
+	if isXSDPath(path) {
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return nil, stacktrace.NewWrapped("read file", err, path, stacktrace.WithType(stacktrace.TypeReading))
+		}
+		dt, err := r.MakeXSDDataType(data, path)
+		if err != nil {
+			return nil, stacktrace.NewWrapped("make xsd data type", err, path, stacktrace.WithType(stacktrace.TypeParsing))
+		}
+		r.PutFragment(path, dt)
+		return dt, nil
+	}
 
 	decoder := yaml.NewDecoder(f)
 
@@ -94,9 +108,14 @@ func (r *RAML) decodeDataType(f io.Reader, path string) (*DataType, error) {
 	return dt, nil
 }
 
+// isXSDPath reports whether the path is an XML Schema file.
+func isXSDPath(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".xsd")
+}
+
 func CheckFragmentKind(f *os.File, kind FragmentKind) error {
-	// Allow JSON data types.
-	if kind == FragmentDataType && strings.HasSuffix(f.Name(), ".json") {
+	// Allow JSON and XML Schema data types.
+	if kind == FragmentDataType && (strings.HasSuffix(f.Name(), ".json") || isXSDPath(f.Name())) {
 		return nil
 	}
 	head, err := ReadHead(f)
@@ -173,10 +192,17 @@ func (r *RAML) decodeLibrary(f io.Reader, path string) (*Library, error) {
 		return nil, stacktrace.NewWrapped("decode fragment", err, path, stacktrace.WithType(stacktrace.TypeParsing))
 	}
 
-	var st *stacktrace.StackTrace
-
 	r.PutFragment(path, lib)
 
+	if st := r.linkLibraries(lib); st != nil {
+		return nil, st
+	}
+	return lib, nil
+}
+
+// linkLibraries parses libraries that the library uses.
+func (r *RAML) linkLibraries(lib *Library) *stacktrace.StackTrace {
+	var st *stacktrace.StackTrace
 	// Resolve included libraries in a separate stage.
 	baseDir := filepath.Dir(lib.Location)
 	for pair := lib.Uses.Oldest(); pair != nil; pair = pair.Next() {
@@ -184,7 +210,7 @@ func (r *RAML) decodeLibrary(f io.Reader, path string) (*Library, error) {
 
 		sublib, err := r.parseLibrary(filepath.Join(baseDir, include.Value))
 		if err != nil {
-			se := stacktrace.NewWrapped("parse uses library", err, path, stacktrace.WithType(stacktrace.TypeParsing), stacktrace.WithPosition(&include.Position))
+			se := stacktrace.NewWrapped("parse uses library", err, lib.Location, stacktrace.WithType(stacktrace.TypeParsing), stacktrace.WithPosition(&include.Position))
 			if st == nil {
 				st = se
 			} else {
@@ -193,10 +219,24 @@ func (r *RAML) decodeLibrary(f io.Reader, path string) (*Library, error) {
 		}
 		include.Link = sublib
 	}
-	if st != nil {
+	return st
+}
+
+func (r *RAML) decodeAPI(f io.Reader, path string) (*API, error) {
+	decoder := yaml.NewDecoder(f)
+
+	api := r.MakeAPI(path)
+	if err := decoder.Decode(&api); err != nil {
+		return nil, stacktrace.NewWrapped("decode fragment", err, path, stacktrace.WithType(stacktrace.TypeParsing))
+	}
+
+	// Declarations of the API are resolved, unwrapped and validated as the library at the API location.
+	r.PutFragment(path, api.Library)
+
+	if st := r.linkLibraries(api.Library); st != nil {
 		return nil, st
 	}
-	return lib, nil
+	return api, nil
 }
 
 func (r *RAML) parseLibrary(path string) (*Library, error) {
@@ -318,6 +358,9 @@ func (r *RAML) ParseFromString(content string, fileName string, baseDir string,
 }
 
 func (r *RAML) parseFragment(f io.ReadSeeker, fragmentPath string, pOpts *parserOptions) error {
+	if pOpts.regexpEngine != nil {
+		r.regexpEngine = pOpts.regexpEngine
+	}
 	head, err := ReadHead(f)
 	if err != nil {
 		return stacktrace.NewWrapped("read head", err, fragmentPath, stacktrace.WithType(stacktrace.TypeParsing))
@@ -345,6 +388,12 @@ func (r *RAML) parseFragment(f io.ReadSeeker, fragmentPath string, pOpts *parser
 			return stacktrace.NewWrapped("parse named example", err, fragmentPath, stacktrace.WithType(stacktrace.TypeParsing))
 		}
 		r.SetEntryPoint(ne)
+	case FragmentAPI:
+		api, err := r.decodeAPI(f, fragmentPath)
+		if err != nil {
+			return stacktrace.NewWrapped("parse api", err, fragmentPath, stacktrace.WithType(stacktrace.TypeParsing))
+		}
+		r.SetEntryPoint(api)
 	default:
 		return stacktrace.New("unknown fragment kind", fragmentPath, stacktrace.WithInfo("head", head), stacktrace.WithType(stacktrace.TypeParsing))
 	}
@@ -429,6 +478,7 @@ func ParseFromString(content string, fileName string, baseDir string, opts ...Pa
 type parserOptions struct {
 	withUnwrapOpt   bool
 	withValidateOpt bool
+	regexpEngine    RegexpEngine
 }
 
 type ParseOpt interface {
@@ -0,0 +1,713 @@
+package raml
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+
+	"github.com/acronis/go-raml/stacktrace"
+)
+
+const (
+	// ProtoFieldNumberAnnotation is the default annotation that sets stable field numbers of properties.
+	ProtoFieldNumberAnnotation = "protoField"
+
+	protoMaxFieldNumber      = 536870911
+	protoReservedFieldsStart = 19000
+	protoReservedFieldsEnd   = 19999
+)
+
+// protoType is a type of protobuf field.
+type protoType struct {
+	name     string
+	repeated bool
+	// optional reports whether the type supports the "optional" label.
+	optional bool
+}
+
+type ProtoConverterOpt interface {
+	Apply(*ProtoConverterOptions)
+}
+
+type optProtoPackage struct {
+	name string
+}
+
+func (o optProtoPackage) Apply(e *ProtoConverterOptions) {
+	e.packageName = o.name
+}
+
+// WithProtoPackage sets the package of the produced file. By default, it is derived from the library file name.
+func WithProtoPackage(name string) ProtoConverterOpt {
+	return optProtoPackage{name: name}
+}
+
+type optProtoFieldNumberAnnotation struct {
+	name string
+}
+
+func (o optProtoFieldNumberAnnotation) Apply(e *ProtoConverterOptions) {
+	e.fieldNumberAnnotation = o.name
+}
+
+// WithProtoFieldNumberAnnotation sets the annotation of properties that holds field numbers.
+// Annotations are matched by the name without the library prefix.
+func WithProtoFieldNumberAnnotation(name string) ProtoConverterOpt {
+	return optProtoFieldNumberAnnotation{name: name}
+}
+
+type ProtoConverterOptions struct {
+	packageName           string
+	fieldNumberAnnotation string
+}
+
+// ProtoConverter converts library types to proto3 messages and enums.
+// Visit methods return field types and declare messages and enums when necessary.
+type ProtoConverter struct {
+	opts ProtoConverterOptions
+
+	decls    *orderedmap.OrderedMap[string, string]
+	names    map[string]string
+	imports  map[string]struct{}
+	warnings []*stacktrace.StackTrace
+	// nameHint is the name of the next anonymous message or enum.
+	nameHint string
+}
+
+var _ ShapeVisitor[protoType] = (*ProtoConverter)(nil)
+
+func NewProtoConverter(opts ...ProtoConverterOpt) *ProtoConverter {
+	c := &ProtoConverter{
+		opts: ProtoConverterOptions{fieldNumberAnnotation: ProtoFieldNumberAnnotation},
+	}
+	for _, opt := range opts {
+		opt.Apply(&c.opts)
+	}
+	return c
+}
+
+// ConvertLibrary converts the library and the libraries it uses into a proto3 file.
+// Types of used libraries are prefixed with the "uses" alias, e.g. "CommonType".
+// Field numbers are assigned in the order of properties unless set by the field number annotation.
+// Constructs that cannot be represented in protobuf are reported as warnings.
+func (c *ProtoConverter) ConvertLibrary(lib *Library) ([]byte, []*stacktrace.StackTrace) {
+	c.decls = orderedmap.New[string, string]()
+	c.names = make(map[string]string)
+	c.imports = make(map[string]struct{})
+	c.warnings = nil
+
+	types := orderedmap.New[string, Shape]()
+	c.collectLibrary(lib, "", types, make(map[string]struct{}))
+	// Only messages and enums are declared, other types are inlined into fields.
+	for pair := types.Oldest(); pair != nil; pair = pair.Next() {
+		id := pair.Value.Base().Id
+		if target, ok := c.names[id]; ok {
+			c.warn(pair.Value.Base(), "alias is replaced with the referenced type", stacktrace.WithInfo("type", target))
+			continue
+		}
+		if isProtoDeclaration(pair.Value) {
+			c.names[id] = pair.Key
+			c.decls.Set(pair.Key, "")
+		}
+	}
+	for pair := types.Oldest(); pair != nil; pair = pair.Next() {
+		if c.names[pair.Value.Base().Id] != pair.Key {
+			continue
+		}
+		c.nameHint = pair.Key
+		c.visit(pair.Value)
+	}
+	return c.render(lib), c.warnings
+}
+
+func isProtoDeclaration(s Shape) bool {
+	switch s := s.(type) {
+	case *ObjectShape:
+		return s.Properties != nil && s.Properties.Len() > 0
+	case *StringShape:
+		return s.Enum != nil
+	case *UnionShape:
+		return len(protoUnionMembers(s)) > 1
+	}
+	return false
+}
+
+func (c *ProtoConverter) collectLibrary(lib *Library, prefix string, types *orderedmap.OrderedMap[string, Shape], visited map[string]struct{}) {
+	if _, ok := visited[lib.Location]; ok {
+		return
+	}
+	visited[lib.Location] = struct{}{}
+	for pair := lib.Types.Oldest(); pair != nil; pair = pair.Next() {
+		s := *pair.Value
+		if !s.Base().IsUnwrapped() {
+			us, err := lib.raml.UnwrapShape(pair.Value, make([]Shape, 0))
+			if err != nil {
+				c.warn(s.Base(), "type cannot be unwrapped and is skipped", stacktrace.WithInfo("error", err))
+				continue
+			}
+			s = us
+		}
+		types.Set(prefix+goIdentifier(pair.Key), s)
+	}
+	for pair := lib.Uses.Oldest(); pair != nil; pair = pair.Next() {
+		if pair.Value.Link != nil {
+			c.collectLibrary(pair.Value.Link, prefix+goIdentifier(pair.Key), types, visited)
+		}
+	}
+}
+
+func (c *ProtoConverter) render(lib *Library) []byte {
+	var b strings.Builder
+	b.WriteString("// Code generated by go-raml. DO NOT EDIT.\n\nsyntax = \"proto3\";\n\n")
+	pkg := c.opts.packageName
+	if pkg == "" {
+		pkg = protoFieldName(strings.TrimSuffix(filepath.Base(lib.Location), filepath.Ext(lib.Location)))
+	}
+	fmt.Fprintf(&b, "package %s;\n", pkg)
+	if len(c.imports) > 0 {
+		imports := make([]string, 0, len(c.imports))
+		for k := range c.imports {
+			imports = append(imports, k)
+		}
+		sort.Strings(imports)
+		b.WriteString("\n")
+		for _, i := range imports {
+			fmt.Fprintf(&b, "import %q;\n", i)
+		}
+	}
+	for pair := c.decls.Oldest(); pair != nil; pair = pair.Next() {
+		b.WriteString("\n")
+		b.WriteString(pair.Value)
+	}
+	return []byte(b.String())
+}
+
+// Visit returns the field type of the shape.
+func (c *ProtoConverter) Visit(s Shape) protoType {
+	if name, ok := c.names[s.Base().Id]; ok {
+		return protoType{name: name, optional: true}
+	}
+	return c.visit(s)
+}
+
+func (c *ProtoConverter) visit(s Shape) protoType {
+	switch s := s.(type) {
+	case *ObjectShape:
+		return c.VisitObjectShape(s)
+	case *ArrayShape:
+		return c.VisitArrayShape(s)
+	case *StringShape:
+		return c.VisitStringShape(s)
+	case *NumberShape:
+		return c.VisitNumberShape(s)
+	case *IntegerShape:
+		return c.VisitIntegerShape(s)
+	case *BooleanShape:
+		return c.VisitBooleanShape(s)
+	case *FileShape:
+		return c.VisitFileShape(s)
+	case *UnionShape:
+		return c.VisitUnionShape(s)
+	case *DateTimeShape:
+		return c.VisitDateTimeShape(s)
+	case *DateTimeOnlyShape:
+		return c.VisitDateTimeOnlyShape(s)
+	case *DateOnlyShape:
+		return c.VisitDateOnlyShape(s)
+	case *TimeOnlyShape:
+		return c.VisitTimeOnlyShape(s)
+	case *RecursiveShape:
+		return c.VisitRecursiveShape(s)
+	case *JSONShape:
+		return c.VisitJSONShape(s)
+	case *XMLShape:
+		return c.VisitXMLShape(s)
+	case *AnyShape:
+		return c.VisitAnyShape(s)
+	case *NilShape:
+		return c.VisitNilShape(s)
+	default:
+		c.warn(s.Base(), "unsupported type is replaced with google.protobuf.Value")
+		return c.value()
+	}
+}
+
+// takeName returns the name for the declared shape and registers it.
+func (c *ProtoConverter) takeName(s Shape) string {
+	if name, ok := c.names[s.Base().Id]; ok {
+		return name
+	}
+	name := c.nameHint
+	for n := 1; ; n++ {
+		if _, ok := c.decls.Get(name); !ok {
+			break
+		}
+		name = c.nameHint + strconv.Itoa(n)
+	}
+	c.names[s.Base().Id] = name
+	c.decls.Set(name, "")
+	return name
+}
+
+// protoMessage builds a message declaration and assigns field numbers.
+type protoMessage struct {
+	c      *ProtoConverter
+	body   strings.Builder
+	fields map[string]struct{}
+	// numbers maps used field numbers to field names.
+	numbers map[int]string
+	next    int
+}
+
+func (m *protoMessage) fieldName(name string) string {
+	field := protoFieldName(name)
+	candidate := field
+	for n := 1; ; n++ {
+		if _, ok := m.fields[candidate]; !ok {
+			break
+		}
+		candidate = field + "_" + strconv.Itoa(n)
+	}
+	m.fields[candidate] = struct{}{}
+	return candidate
+}
+
+// number returns the requested field number if it is valid and free, otherwise the next free number.
+func (m *protoMessage) number(base *BaseShape, field string, requested int) int {
+	if requested != 0 {
+		switch {
+		case requested < 1 || requested > protoMaxFieldNumber ||
+			requested >= protoReservedFieldsStart && requested <= protoReservedFieldsEnd:
+			m.c.warn(base, "field number is out of range and is reassigned", stacktrace.WithInfo("number", requested))
+		case m.numbers[requested] != "":
+			m.c.warn(base, "field number is already used and is reassigned",
+				stacktrace.WithInfo("number", requested), stacktrace.WithInfo("field", m.numbers[requested]))
+		default:
+			m.numbers[requested] = field
+			return requested
+		}
+	}
+	for m.numbers[m.next] != "" || m.next >= protoReservedFieldsStart && m.next <= protoReservedFieldsEnd {
+		m.next++
+	}
+	m.numbers[m.next] = field
+	return m.next
+}
+
+func (m *protoMessage) writeField(indent string, label string, typ string, name string, number int, jsonName string) {
+	m.body.WriteString(indent)
+	if label != "" {
+		m.body.WriteString(label + " ")
+	}
+	fmt.Fprintf(&m.body, "%s %s = %d", typ, name, number)
+	if jsonName != "" && jsonName != protoJSONName(name) {
+		fmt.Fprintf(&m.body, " [json_name = %q]", jsonName)
+	}
+	m.body.WriteString(";\n")
+}
+
+// fieldNumbers returns requested field numbers of properties. Numbers requested by the annotation
+// are reserved first so that properties without the annotation do not take them.
+func (c *ProtoConverter) fieldNumbers(properties *orderedmap.OrderedMap[string, Property]) map[string]int {
+	requested := make(map[string]int)
+	if properties == nil {
+		return requested
+	}
+	for pair := properties.Oldest(); pair != nil; pair = pair.Next() {
+		base := (*pair.Value.Shape).Base()
+		for de := base.CustomDomainProperties.Oldest(); de != nil; de = de.Next() {
+			name := de.Key
+			if i := strings.LastIndexByte(name, '.'); i != -1 {
+				name = name[i+1:]
+			}
+			if name != c.opts.fieldNumberAnnotation {
+				continue
+			}
+			n, ok := protoNumber(de.Value.Extension.Value)
+			if !ok {
+				c.warn(base, "field number annotation must be an integer", stacktrace.WithInfo("value", de.Value.Extension.Value))
+				continue
+			}
+			requested[pair.Key] = n
+		}
+	}
+	return requested
+}
+
+func protoNumber(v any) (int, bool) {
+	switch v := v.(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case uint64:
+		return int(v), true
+	case float64:
+		if v == float64(int(v)) {
+			return int(v), true
+		}
+	}
+	return 0, false
+}
+
+func (c *ProtoConverter) VisitObjectShape(s *ObjectShape) protoType {
+	var patternType *protoType
+	if s.PatternProperties != nil && s.PatternProperties.Len() > 0 {
+		hint := c.nameHint
+		var t protoType
+		if s.PatternProperties.Len() == 1 {
+			c.nameHint = hint + "Value"
+			t = c.Visit(*s.PatternProperties.Oldest().Value.Shape)
+			if t.repeated {
+				c.warn(s.Base(), "map values cannot be repeated and are wrapped into a message")
+				t = c.wrapRepeated(hint+"Value", t)
+			}
+		} else {
+			c.warn(s.Base(), "multiple pattern properties are mapped to google.protobuf.Value")
+			t = c.value()
+		}
+		c.nameHint = hint
+		patternType = &t
+		if s.Properties == nil || s.Properties.Len() == 0 {
+			return protoType{name: fmt.Sprintf("map<string, %s>", t.name)}
+		}
+	}
+	if s.Properties == nil || s.Properties.Len() == 0 {
+		c.imports["google/protobuf/struct.proto"] = struct{}{}
+		return protoType{name: "google.protobuf.Struct", optional: true}
+	}
+
+	name := c.takeName(s)
+	m := &protoMessage{c: c, fields: make(map[string]struct{}), numbers: make(map[int]string), next: 1}
+	requested := c.fieldNumbers(s.Properties)
+	// Requested numbers are reserved in the order of properties, so that the first property keeps a duplicate number.
+	for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+		if n := requested[pair.Key]; n > 0 && m.numbers[n] == "" {
+			m.numbers[n] = pair.Key
+		}
+	}
+	for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+		prop := pair.Value
+		ps := *prop.Shape
+		field := m.fieldName(prop.Name)
+		if n, ok := requested[prop.Name]; ok && m.numbers[n] == prop.Name {
+			delete(m.numbers, n)
+		}
+		// Declared types are documented at the declaration.
+		if d := ps.Base().Description; d != nil && c.names[ps.Base().Id] == "" {
+			m.body.WriteString(protoComment(*d, "  "))
+		}
+		c.nameHint = name + goIdentifier(prop.Name)
+		if u, ok := ps.(*UnionShape); ok && c.names[u.Id] == "" {
+			if members := protoUnionMembers(u); len(members) > 1 {
+				c.writeOneof(m, u, members, field, requested[prop.Name])
+				continue
+			}
+		}
+		t := c.Visit(ps)
+		label := ""
+		if t.repeated {
+			label = "repeated"
+		} else if !prop.Required && t.optional {
+			label = "optional"
+		}
+		m.writeField("  ", label, t.name, field, m.number(ps.Base(), field, requested[prop.Name]), prop.Name)
+	}
+	if patternType != nil {
+		field := m.fieldName("additionalProperties")
+		m.writeField("  ", "", fmt.Sprintf("map<string, %s>", patternType.name), field, m.number(s.Base(), field, 0), "")
+	}
+	c.declareMessage(s.Base(), name, m.body.String())
+	return protoType{name: name, optional: true}
+}
+
+// writeOneof writes a union property as oneof. The requested number is assigned to the first member.
+func (c *ProtoConverter) writeOneof(m *protoMessage, u *UnionShape, members []Shape, field string, requested int) {
+	if len(members) != len(u.AnyOf) {
+		c.warn(u.Base(), "nil member of union is represented by absence of oneof fields")
+	}
+	fmt.Fprintf(&m.body, "  oneof %s {\n", field)
+	hint := c.nameHint
+	for i, member := range members {
+		c.nameHint = hint + "Member" + strconv.Itoa(i)
+		t := c.Visit(member)
+		if t.repeated {
+			c.warn(u.Base(), "oneof fields cannot be repeated and are wrapped into a message")
+			t = c.wrapRepeated(c.nameHint, t)
+		}
+		if strings.HasPrefix(t.name, "map<") {
+			c.warn(u.Base(), "oneof fields cannot be maps and are wrapped into a message")
+			t = c.wrapMap(c.nameHint, t)
+		}
+		memberField := m.fieldName(field + "_" + protoFieldName(t.name[strings.LastIndexByte(t.name, '.')+1:]))
+		number := 0
+		if i == 0 {
+			number = requested
+		}
+		m.writeField("    ", "", t.name, memberField, m.number(u.Base(), memberField, number), "")
+	}
+	m.body.WriteString("  }\n")
+}
+
+// VisitUnionShape declares a message with oneof when the union cannot be placed into a field.
+// Unions with nil and a single other member are represented by optional fields.
+func (c *ProtoConverter) VisitUnionShape(s *UnionShape) protoType {
+	members := protoUnionMembers(s)
+	if len(members) == 0 {
+		return c.VisitNilShape(nil)
+	}
+	if len(members) == 1 {
+		return c.Visit(members[0])
+	}
+	name := c.takeName(s)
+	m := &protoMessage{c: c, fields: make(map[string]struct{}), numbers: make(map[int]string), next: 1}
+	c.nameHint = name
+	c.writeOneof(m, s, members, "value", 0)
+	c.declareMessage(s.Base(), name, m.body.String())
+	return protoType{name: name, optional: true}
+}
+
+func protoUnionMembers(s *UnionShape) []Shape {
+	members := make([]Shape, 0, len(s.AnyOf))
+	for _, item := range s.AnyOf {
+		if _, ok := (*item).(*NilShape); !ok {
+			members = append(members, *item)
+		}
+	}
+	return members
+}
+
+func (c *ProtoConverter) declareMessage(base *BaseShape, name string, body string) {
+	var b strings.Builder
+	if base.Description != nil {
+		b.WriteString(protoComment(*base.Description, ""))
+	}
+	fmt.Fprintf(&b, "message %s {\n%s}\n", name, body)
+	c.decls.Set(name, b.String())
+}
+
+func (c *ProtoConverter) wrapRepeated(name string, t protoType) protoType {
+	name = c.uniqueName(name)
+	c.decls.Set(name, fmt.Sprintf("message %s {\n  repeated %s values = 1;\n}\n", name, t.name))
+	return protoType{name: name, optional: true}
+}
+
+func (c *ProtoConverter) wrapMap(name string, t protoType) protoType {
+	name = c.uniqueName(name)
+	c.decls.Set(name, fmt.Sprintf("message %s {\n  %s values = 1;\n}\n", name, t.name))
+	return protoType{name: name, optional: true}
+}
+
+func (c *ProtoConverter) uniqueName(name string) string {
+	candidate := name
+	for n := 1; ; n++ {
+		if _, ok := c.decls.Get(candidate); !ok {
+			return candidate
+		}
+		candidate = name + strconv.Itoa(n)
+	}
+}
+
+func (c *ProtoConverter) VisitArrayShape(s *ArrayShape) protoType {
+	if s.Items == nil {
+		return protoType{name: c.value().name, repeated: true}
+	}
+	c.nameHint += "Item"
+	t := c.Visit(*s.Items)
+	if t.repeated {
+		c.warn(s.Base(), "nested arrays are wrapped into a message")
+		t = c.wrapRepeated(c.nameHint, t)
+	} else if strings.HasPrefix(t.name, "map<") {
+		c.warn(s.Base(), "arrays of maps are wrapped into a message")
+		t = c.wrapMap(c.nameHint, t)
+	}
+	return protoType{name: t.name, repeated: true}
+}
+
+func (c *ProtoConverter) VisitStringShape(s *StringShape) protoType {
+	if s.Enum == nil {
+		return protoType{name: "string", optional: true}
+	}
+	name := c.takeName(s)
+	prefix := protoEnumValueName(name)
+	var b strings.Builder
+	if s.Description != nil {
+		b.WriteString(protoComment(*s.Description, ""))
+	}
+	fmt.Fprintf(&b, "enum %s {\n  %s_UNSPECIFIED = 0;\n", name, prefix)
+	values := map[string]struct{}{prefix + "_UNSPECIFIED": {}}
+	for i, e := range s.Enum {
+		value := prefix + "_" + protoEnumValueName(fmt.Sprint(e.Value))
+		for n := 1; ; n++ {
+			if _, ok := values[value]; !ok {
+				break
+			}
+			value = prefix + "_" + protoEnumValueName(fmt.Sprint(e.Value)) + "_" + strconv.Itoa(n)
+		}
+		values[value] = struct{}{}
+		fmt.Fprintf(&b, "  %s = %d;\n", value, i+1)
+	}
+	b.WriteString("}\n")
+	c.decls.Set(name, b.String())
+	return protoType{name: name, optional: true}
+}
+
+func (c *ProtoConverter) VisitIntegerShape(s *IntegerShape) protoType {
+	if s.Enum != nil {
+		c.warn(s.Base(), "integer enum is replaced with integer type")
+	}
+	if s.Format != nil {
+		switch *s.Format {
+		case "int8", "int16", "int", "int32":
+			return protoType{name: "int32", optional: true}
+		}
+	}
+	return protoType{name: "int64", optional: true}
+}
+
+func (c *ProtoConverter) VisitNumberShape(s *NumberShape) protoType {
+	if s.Enum != nil {
+		c.warn(s.Base(), "number enum is replaced with number type")
+	}
+	if s.Format != nil && *s.Format == "float" {
+		return protoType{name: "float", optional: true}
+	}
+	return protoType{name: "double", optional: true}
+}
+
+func (c *ProtoConverter) VisitBooleanShape(s *BooleanShape) protoType {
+	return protoType{name: "bool", optional: true}
+}
+
+func (c *ProtoConverter) VisitFileShape(s *FileShape) protoType {
+	return protoType{name: "bytes", optional: true}
+}
+
+func (c *ProtoConverter) VisitDateTimeShape(s *DateTimeShape) protoType {
+	return c.timestamp()
+}
+
+func (c *ProtoConverter) VisitDateTimeOnlyShape(s *DateTimeOnlyShape) protoType {
+	c.warn(s.Base(), "datetime-only is mapped to google.protobuf.Timestamp and must be interpreted in UTC")
+	return c.timestamp()
+}
+
+// VisitDateOnlyShape maps date-only to a string in RFC3339 full-date format,
+// since google.protobuf.Timestamp is a point in time.
+func (c *ProtoConverter) VisitDateOnlyShape(s *DateOnlyShape) protoType {
+	c.warn(s.Base(), "date-only is mapped to string in full-date format instead of google.protobuf.Timestamp")
+	return protoType{name: "string", optional: true}
+}
+
+// VisitTimeOnlyShape maps time-only to a string in RFC3339 partial-time format,
+// since google.protobuf.Timestamp is a point in time.
+func (c *ProtoConverter) VisitTimeOnlyShape(s *TimeOnlyShape) protoType {
+	c.warn(s.Base(), "time-only is mapped to string in partial-time format instead of google.protobuf.Timestamp")
+	return protoType{name: "string", optional: true}
+}
+
+func (c *ProtoConverter) timestamp() protoType {
+	c.imports["google/protobuf/timestamp.proto"] = struct{}{}
+	return protoType{name: "google.protobuf.Timestamp", optional: true}
+}
+
+func (c *ProtoConverter) value() protoType {
+	c.imports["google/protobuf/struct.proto"] = struct{}{}
+	return protoType{name: "google.protobuf.Value", optional: true}
+}
+
+func (c *ProtoConverter) VisitRecursiveShape(s *RecursiveShape) protoType {
+	if name, ok := c.names[(*s.Head).Base().Id]; ok {
+		return protoType{name: name, optional: true}
+	}
+	c.warn(s.Base(), "recursion to undeclared type is replaced with google.protobuf.Value")
+	return c.value()
+}
+
+func (c *ProtoConverter) VisitJSONShape(s *JSONShape) protoType {
+	c.warn(s.Base(), "JSON schema is replaced with google.protobuf.Value")
+	return c.value()
+}
+
+func (c *ProtoConverter) VisitXMLShape(s *XMLShape) protoType {
+	return protoType{name: "string"}
+}
+
+func (c *ProtoConverter) VisitAnyShape(s *AnyShape) protoType {
+	return c.value()
+}
+
+func (c *ProtoConverter) VisitNilShape(s *NilShape) protoType {
+	c.imports["google/protobuf/struct.proto"] = struct{}{}
+	return protoType{name: "google.protobuf.NullValue", optional: true}
+}
+
+func (c *ProtoConverter) warn(base *BaseShape, message string, opts ...stacktrace.Option) {
+	opts = append(opts,
+		stacktrace.WithPosition(&base.Position),
+		stacktrace.WithSeverity(stacktrace.SeverityWarning),
+		stacktrace.WithType(stacktrace.TypeConverting),
+		stacktrace.WithInfo("shape", base.Name),
+	)
+	c.warnings = append(c.warnings, stacktrace.New(message, base.Location, opts...))
+}
+
+// protoFieldName converts a name to snake_case.
+func protoFieldName(name string) string {
+	var b strings.Builder
+	prev := '_'
+	for _, r := range name {
+		switch {
+		case unicode.IsUpper(r):
+			if prev != '_' && !unicode.IsUpper(prev) {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			b.WriteRune(r)
+		default:
+			if prev == '_' {
+				continue
+			}
+			r = '_'
+			b.WriteRune(r)
+		}
+		prev = r
+	}
+	field := strings.Trim(b.String(), "_")
+	if field == "" || unicode.IsDigit(rune(field[0])) {
+		field = "f_" + field
+	}
+	return field
+}
+
+// protoJSONName returns the JSON name that protoc derives from the field name.
+func protoJSONName(field string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range field {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func protoEnumValueName(name string) string {
+	return strings.ToUpper(protoFieldName(name))
+}
+
+func protoComment(text string, indent string) string {
+	return goComment(text, indent)
+}
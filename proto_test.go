@@ -0,0 +1,150 @@
+package raml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtoConverter_DateTypes(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0 Library
+types:
+  Event:
+    type: object
+    properties:
+      at: datetime
+      local: datetime-only
+      day: date-only
+      start: time-only
+`)
+	out, warnings := NewProtoConverter().ConvertLibrary(rml.EntryPoint().(*Library))
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "datetime", want: "google.protobuf.Timestamp at = 1;"},
+		{name: "datetime-only", want: "google.protobuf.Timestamp local = 2;"},
+		{name: "date-only", want: "string day = 3;"},
+		{name: "time-only", want: "string start = 4;"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Contains(t, string(out), tt.want)
+		})
+	}
+	var messages []string
+	for _, w := range warnings {
+		messages = append(messages, w.Message)
+	}
+	require.Equal(t, []string{
+		"datetime-only is mapped to google.protobuf.Timestamp and must be interpreted in UTC",
+		"date-only is mapped to string in full-date format instead of google.protobuf.Timestamp",
+		"time-only is mapped to string in partial-time format instead of google.protobuf.Timestamp",
+	}, messages)
+}
+
+func TestProtoConverter_ConvertLibrary(t *testing.T) {
+	tests := []struct {
+		name     string
+		types    string
+		want     string
+		warnings []string
+	}{
+		{
+			name: "field number annotation",
+			types: `
+  Item:
+    type: object
+    properties:
+      name:
+        type: string
+        (protoField): 5
+      first:
+        type: string
+        (protoField): 2
+      second:
+        type: string
+        (protoField): 2
+      count:
+        type: integer
+        format: int8
+`,
+			want: `message Item {
+  string name = 5;
+  string first = 2;
+  string second = 1;
+  int32 count = 3;
+}
+`,
+			warnings: []string{"field number is already used and is reassigned"},
+		},
+		{
+			name: "oneof from union",
+			types: `
+  Item:
+    type: object
+    properties:
+      id: integer
+      value:
+        type: string | integer | nil
+        (protoField): 10
+`,
+			want: `message Item {
+  int64 id = 1;
+  oneof value {
+    string value_string = 10;
+    int64 value_int64 = 2;
+  }
+}
+`,
+			warnings: []string{"nil member of union is represented by absence of oneof fields"},
+		},
+		{
+			name: "maps and repeated fields",
+			types: `
+  Item:
+    type: object
+    properties:
+      tags: string[]
+      counts:
+        type: object
+        properties:
+          //:
+            type: integer
+            format: int32
+      groups:
+        type: object
+        properties:
+          //: string[]
+`,
+			want: `message Item {
+  repeated string tags = 1;
+  map<string, int32> counts = 2;
+  map<string, ItemGroupsValue> groups = 3;
+}
+`,
+			warnings: []string{"map values cannot be repeated and are wrapped into a message"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rml := parseLibrary(t, "#%RAML 1.0 Library\nannotationTypes:\n  protoField: integer\ntypes:"+tt.types)
+			// Output does not depend on map iteration order.
+			var first string
+			for i := 0; i < 20; i++ {
+				out, warnings := NewProtoConverter().ConvertLibrary(rml.EntryPoint().(*Library))
+				if i == 0 {
+					first = string(out)
+					var messages []string
+					for _, w := range warnings {
+						messages = append(messages, w.Message)
+					}
+					require.Equal(t, tt.warnings, messages)
+				}
+				require.Equal(t, first, string(out))
+			}
+			require.Contains(t, first, tt.want)
+		})
+	}
+}
@@ -13,7 +13,7 @@ type RAML struct {
 	fragmentTypes           map[string]map[string]*Shape
 	fragmentAnnotationTypes map[string]map[string]*Shape
 	shapes                  []*Shape
-	// entryPoint is a Library, NamedExample or DataType fragment that is used as an entry point for the resolution.
+	// entryPoint is an API, Library, NamedExample or DataType fragment that is used as an entry point for the resolution.
 	entryPoint Fragment
 	// basePath   string
 
@@ -22,6 +22,9 @@ type RAML struct {
 	// Temporary storage for unresolved shapes.
 	unresolvedShapes list.List
 
+	// regexpEngine compiles pattern facets and pattern properties. RE2Engine is used if nil.
+	regexpEngine RegexpEngine
+
 	// ctx is a context of the RAML, for future use.
 	ctx context.Context
 }
@@ -0,0 +1,329 @@
+package raml
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonNumberType    = reflect.TypeOf(json.Number(""))
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+var (
+	// reflectPlans caches plans by reflect.Type.
+	reflectPlans sync.Map
+	// reflectPlansMu serializes building of plans so that plans of recursive types are stored complete.
+	reflectPlansMu sync.Mutex
+)
+
+// reflectPlan converts values of a Go type to values that Validate accepts.
+type reflectPlan struct {
+	convert func(v reflect.Value, path string) (interface{}, error)
+}
+
+// reflectField is a struct field that is converted to an object property.
+type reflectField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+	plan      *reflectPlan
+}
+
+// ValidateValue validates an arbitrary Go value against the shape.
+// Structs are validated as objects with property names taken from json tags following encoding/json rules,
+// nil pointers, maps, slices and interfaces in struct fields are treated as absent properties.
+// Typed slices, arrays and maps, integers and floats of all widths, json.Number and json.Marshaler
+// implementations are supported. time.Time is valid for any date type.
+// The value is converted to maps, slices and scalars that Validate accepts and then validated,
+// so it is copied once but not encoded to JSON. The conversion plan of each Go type is built once and cached.
+func ValidateValue(s Shape, v interface{}, ctxPath string) error {
+	val, err := reflectValue(v, ctxPath)
+	if err != nil {
+		return err
+	}
+	return s.Validate(val, ctxPath)
+}
+
+// reflectValue converts a Go value to a value of types that Validate accepts.
+func reflectValue(v interface{}, path string) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	rv := reflect.ValueOf(v)
+	return planOf(rv.Type()).convert(rv, path)
+}
+
+func planOf(t reflect.Type) *reflectPlan {
+	if p, ok := reflectPlans.Load(t); ok {
+		return p.(*reflectPlan)
+	}
+	reflectPlansMu.Lock()
+	defer reflectPlansMu.Unlock()
+	building := make(map[reflect.Type]*reflectPlan)
+	p := buildPlan(t, building)
+	for bt, bp := range building {
+		reflectPlans.Store(bt, bp)
+	}
+	return p
+}
+
+// buildPlan returns the plan of the type. Plans that are being built are kept in building
+// to resolve recursive types.
+func buildPlan(t reflect.Type, building map[reflect.Type]*reflectPlan) *reflectPlan {
+	if p, ok := reflectPlans.Load(t); ok {
+		return p.(*reflectPlan)
+	}
+	if p, ok := building[t]; ok {
+		return p
+	}
+	p := &reflectPlan{}
+	building[t] = p
+	p.convert = makeConverter(t, building)
+	return p
+}
+
+func makeConverter(t reflect.Type, building map[reflect.Type]*reflectPlan) func(reflect.Value, string) (interface{}, error) {
+	switch {
+	case t == timeType:
+		return func(v reflect.Value, _ string) (interface{}, error) {
+			return v.Interface(), nil
+		}
+	case t == jsonNumberType:
+		return convertJSONNumber
+	case t.Implements(jsonMarshalerType):
+		return convertJSONMarshaler
+	case t.Kind() != reflect.Pointer && reflect.PointerTo(t).Implements(jsonMarshalerType):
+		// Like encoding/json, MarshalJSON with pointer receiver is used only for addressable values.
+		convert := makeKindConverter(t, building)
+		return func(v reflect.Value, path string) (interface{}, error) {
+			if v.CanAddr() {
+				return convertJSONMarshaler(v.Addr(), path)
+			}
+			return convert(v, path)
+		}
+	}
+	return makeKindConverter(t, building)
+}
+
+func makeKindConverter(t reflect.Type, building map[reflect.Type]*reflectPlan) func(reflect.Value, string) (interface{}, error) {
+	switch t.Kind() {
+	case reflect.Bool:
+		return func(v reflect.Value, _ string) (interface{}, error) {
+			return v.Bool(), nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(v reflect.Value, _ string) (interface{}, error) {
+			return int(v.Int()), nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(v reflect.Value, _ string) (interface{}, error) {
+			return uint(v.Uint()), nil
+		}
+	case reflect.Float32:
+		// float32 is kept so that numbers are compared by their shortest float32 decimal as Validate does.
+		return func(v reflect.Value, _ string) (interface{}, error) {
+			return float32(v.Float()), nil
+		}
+	case reflect.Float64:
+		return func(v reflect.Value, _ string) (interface{}, error) {
+			return v.Float(), nil
+		}
+	case reflect.String:
+		return func(v reflect.Value, _ string) (interface{}, error) {
+			return v.String(), nil
+		}
+	case reflect.Interface:
+		return func(v reflect.Value, path string) (interface{}, error) {
+			if v.IsNil() {
+				return nil, nil
+			}
+			return planOf(v.Elem().Type()).convert(v.Elem(), path)
+		}
+	case reflect.Pointer:
+		elem := buildPlan(t.Elem(), building)
+		return func(v reflect.Value, path string) (interface{}, error) {
+			if v.IsNil() {
+				return nil, nil
+			}
+			return elem.convert(v.Elem(), path)
+		}
+	case reflect.Slice, reflect.Array:
+		return makeSliceConverter(t, building)
+	case reflect.Map:
+		return makeMapConverter(t, building)
+	case reflect.Struct:
+		return makeStructConverter(t, building)
+	}
+	return func(_ reflect.Value, path string) (interface{}, error) {
+		return nil, fmt.Errorf("%s: unsupported type %s", path, t)
+	}
+}
+
+func convertJSONNumber(v reflect.Value, path string) (interface{}, error) {
+	n, err := jsonNumberValue(json.Number(v.String()))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return n, nil
+}
+
+func convertJSONMarshaler(v reflect.Value, path string) (interface{}, error) {
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return nil, nil
+	}
+	data, err := v.Interface().(json.Marshaler).MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("%s: marshal json: %w", path, err)
+	}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var val interface{}
+	if err := d.Decode(&val); err != nil {
+		return nil, fmt.Errorf("%s: unmarshal json: %w", path, err)
+	}
+	return reflectValue(val, path)
+}
+
+func makeSliceConverter(t reflect.Type, building map[reflect.Type]*reflectPlan) func(reflect.Value, string) (interface{}, error) {
+	// Byte slices are encoded as base64 strings like encoding/json does.
+	if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+		return func(v reflect.Value, _ string) (interface{}, error) {
+			if v.IsNil() {
+				return nil, nil
+			}
+			return base64.StdEncoding.EncodeToString(v.Bytes()), nil
+		}
+	}
+	elem := buildPlan(t.Elem(), building)
+	return func(v reflect.Value, path string) (interface{}, error) {
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil, nil
+		}
+		items := make([]interface{}, v.Len())
+		for i := range items {
+			item, err := elem.convert(v.Index(i), path+"["+strconv.Itoa(i)+"]")
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	}
+}
+
+func makeMapConverter(t reflect.Type, building map[reflect.Type]*reflectPlan) func(reflect.Value, string) (interface{}, error) {
+	var key func(reflect.Value) string
+	switch t.Key().Kind() {
+	case reflect.String:
+		key = reflect.Value.String
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		key = func(k reflect.Value) string { return strconv.FormatInt(k.Int(), 10) }
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		key = func(k reflect.Value) string { return strconv.FormatUint(k.Uint(), 10) }
+	default:
+		return func(_ reflect.Value, path string) (interface{}, error) {
+			return nil, fmt.Errorf("%s: unsupported map key type %s", path, t.Key())
+		}
+	}
+	elem := buildPlan(t.Elem(), building)
+	return func(v reflect.Value, path string) (interface{}, error) {
+		if v.IsNil() {
+			return nil, nil
+		}
+		m := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			k := key(iter.Key())
+			item, err := elem.convert(iter.Value(), path+"."+k)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = item
+		}
+		return m, nil
+	}
+}
+
+func makeStructConverter(t reflect.Type, building map[reflect.Type]*reflectPlan) func(reflect.Value, string) (interface{}, error) {
+	var fields []reflectField
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		// Fields of embedded structs without name are promoted.
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				continue
+			}
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, reflectField{
+			name:      name,
+			index:     f.Index,
+			omitEmpty: strings.Contains(","+opts+",", ",omitempty,"),
+			plan:      buildPlan(f.Type, building),
+		})
+	}
+	return func(v reflect.Value, path string) (interface{}, error) {
+		m := make(map[string]interface{}, len(fields))
+		for _, f := range fields {
+			fv, err := v.FieldByIndexErr(f.index)
+			if err != nil {
+				// Field of nil embedded pointer.
+				continue
+			}
+			if isAbsentValue(fv, f.omitEmpty) {
+				continue
+			}
+			item, err := f.plan.convert(fv, path+"."+f.name)
+			if err != nil {
+				return nil, err
+			}
+			m[f.name] = item
+		}
+		return m, nil
+	}
+}
+
+// isAbsentValue reports whether the struct field value is treated as absent property.
+// Empty values of fields with omitempty option are defined as in encoding/json.
+func isAbsentValue(v reflect.Value, omitEmpty bool) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return true
+		}
+	}
+	if !omitEmpty {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return v.IsZero()
+	}
+	return false
+}
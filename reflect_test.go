@@ -0,0 +1,164 @@
+package raml
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type reflectTestCode string
+
+// MarshalJSON has pointer receiver, so it is used only for addressable values.
+func (c *reflectTestCode) MarshalJSON() ([]byte, error) {
+	return json.Marshal("code-" + string(*c))
+}
+
+type reflectTestEmbedded struct {
+	Owner string `json:"owner"`
+}
+
+type reflectTestPet struct {
+	reflectTestEmbedded
+	Name     string            `json:"name"`
+	Age      *int              `json:"age,omitempty"`
+	Tags     []string          `json:"tags,omitempty"`
+	Born     time.Time         `json:"born"`
+	Weight   json.Number       `json:"weight,omitempty"`
+	Counts   map[int]uint8     `json:"counts,omitempty"`
+	Code     reflectTestCode   `json:"code"`
+	Children []*reflectTestPet `json:"children,omitempty"`
+	Ignored  string            `json:"-"`
+}
+
+func TestValidateValue(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0 Library
+types:
+  Pet:
+    type: object
+    additionalProperties: false
+    properties:
+      owner: string
+      name:
+        type: string
+        minLength: 1
+      age?:
+        type: integer
+        minimum: 0
+      tags?: string[]
+      born: date-only
+      weight?: number
+      counts?:
+        type: object
+        properties:
+          //: integer
+      code:
+        type: string
+        pattern: ^code-
+      children?: Pet[]
+`)
+	s := libraryType(t, rml, "Pet")
+	age := 3
+	negative := -1
+	born := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		value   interface{}
+		wantErr bool
+	}{
+		{
+			name:  "pointer with all fields",
+			value: &reflectTestPet{Name: "Rex", Age: &age, Tags: []string{"a"}, Born: born, Weight: "1.5", Counts: map[int]uint8{1: 2}, Code: "x"},
+		},
+		{
+			name:  "nested addressable values",
+			value: &reflectTestPet{Name: "Rex", Born: born, Code: "x", Children: []*reflectTestPet{{Name: "Pup", Born: born, Code: "y"}}},
+		},
+		{
+			// Fields of a struct passed by value are not addressable, so the code is not marshalled.
+			name:    "not addressable pointer marshaler",
+			value:   reflectTestPet{Name: "Rex", Born: born, Code: "x"},
+			wantErr: true,
+		},
+		{name: "facet violation", value: &reflectTestPet{Name: "", Born: born, Code: "x"}, wantErr: true},
+		{name: "nested violation", value: &reflectTestPet{Name: "Rex", Age: &negative, Born: born, Code: "x"}, wantErr: true},
+		{name: "invalid number", value: &reflectTestPet{Name: "Rex", Born: born, Weight: "x", Code: "x"}, wantErr: true},
+		{name: "wrong type", value: []int{1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateValue(s, tt.value, "$")
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateValue_Float32(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0 Library
+types:
+  Ratio:
+    type: number
+    maximum: 0.1
+  Step:
+    type: number
+    multipleOf: 0.1
+`)
+	tests := []struct {
+		name    string
+		typ     string
+		value   interface{}
+		wantErr bool
+	}{
+		{name: "float32 at maximum", typ: "Ratio", value: float32(0.1)},
+		{name: "float32 above maximum", typ: "Ratio", value: float32(0.11), wantErr: true},
+		{name: "float32 multiple", typ: "Step", value: float32(0.3)},
+		{name: "float32 not a multiple", typ: "Step", value: float32(0.35), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := libraryType(t, rml, tt.typ)
+			// ValidateValue agrees with Validate of the same value.
+			for _, err := range []error{s.Validate(tt.value, "$"), ValidateValue(s, tt.value, "$")} {
+				if tt.wantErr {
+					require.Error(t, err)
+				} else {
+					require.NoError(t, err)
+				}
+			}
+		})
+	}
+}
+
+func Test_reflectValue(t *testing.T) {
+	code := reflectTestCode("x")
+	tests := []struct {
+		name  string
+		value interface{}
+		want  interface{}
+	}{
+		{name: "nil", value: nil, want: nil},
+		{name: "int8", value: int8(-3), want: -3},
+		{name: "uint16", value: uint16(3), want: uint(3)},
+		{name: "float32", value: float32(0.5), want: float32(0.5)},
+		{name: "float64", value: 0.5, want: 0.5},
+		{name: "bytes", value: []byte("hi"), want: "aGk="},
+		{name: "array", value: [2]bool{true, false}, want: []interface{}{true, false}},
+		{name: "nil slice", value: []string(nil), want: nil},
+		{name: "pointer receiver marshaler", value: &code, want: "code-x"},
+		{name: "addressable slice item", value: []reflectTestCode{"y"}, want: []interface{}{"code-y"}},
+		{name: "json number", value: json.Number("12"), want: 12},
+		{name: "map with interface values", value: map[string]interface{}{"a": int64(1), "b": nil}, want: map[string]interface{}{"a": 1, "b": nil}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := reflectValue(tt.value, "$")
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package raml
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/dlclark/regexp2"
+)
+
+// DefaultRegexpTimeout is the default match timeout of ECMAScriptEngine.
+const DefaultRegexpTimeout = 100 * time.Millisecond
+
+// ErrRegexpTimeout is returned by validation when matching a pattern exceeds the timeout of the engine.
+var ErrRegexpTimeout = errors.New("regular expression match timed out")
+
+// Regexp is a compiled regular expression of pattern facets and pattern properties.
+type Regexp interface {
+	MatchString(s string) bool
+	String() string
+}
+
+// RegexpWithError is implemented by regular expressions whose matching may fail, e.g. by timeout.
+// Validation uses it to report such failures instead of a mismatch.
+type RegexpWithError interface {
+	Regexp
+	MatchStringError(s string) (bool, error)
+}
+
+// matchRegexp reports whether the string matches the regular expression
+// or returns the error of RegexpWithError.
+func matchRegexp(re Regexp, s string) (bool, error) {
+	if r, ok := re.(RegexpWithError); ok {
+		return r.MatchStringError(s)
+	}
+	return re.MatchString(s), nil
+}
+
+// RegexpEngine compiles regular expressions of pattern facets and pattern properties.
+type RegexpEngine interface {
+	Compile(expr string) (Regexp, error)
+}
+
+// RE2Engine compiles regular expressions with the regexp package.
+// It is the default engine: matching is fast and runs in linear time, but ECMAScript features
+// such as lookarounds and backreferences are not supported.
+type RE2Engine struct{}
+
+func (RE2Engine) Compile(expr string) (Regexp, error) {
+	return regexp.Compile(expr)
+}
+
+// ECMAScriptEngine compiles regular expressions with ECMA-262 syntax and semantics as RAML defines them.
+// Backtracking may take exponential time, so every match is limited by Timeout. Validation reports
+// a match that times out as ErrRegexpTimeout, MatchString reports it as a mismatch.
+type ECMAScriptEngine struct {
+	Timeout time.Duration
+}
+
+// NewECMAScriptEngine creates an ECMAScript engine with DefaultRegexpTimeout.
+func NewECMAScriptEngine() *ECMAScriptEngine {
+	return &ECMAScriptEngine{Timeout: DefaultRegexpTimeout}
+}
+
+func (e *ECMAScriptEngine) Compile(expr string) (Regexp, error) {
+	re, err := regexp2.Compile(expr, regexp2.ECMAScript)
+	if err != nil {
+		return nil, err
+	}
+	if e.Timeout > 0 {
+		re.MatchTimeout = e.Timeout
+	}
+	return ecmaRegexp{re: re}, nil
+}
+
+type ecmaRegexp struct {
+	re *regexp2.Regexp
+}
+
+func (r ecmaRegexp) MatchString(s string) bool {
+	ok, err := r.MatchStringError(s)
+	return err == nil && ok
+}
+
+func (r ecmaRegexp) MatchStringError(s string) (bool, error) {
+	ok, err := r.re.MatchString(s)
+	if err != nil {
+		// regexp2 fails only when the timeout is exceeded.
+		return false, fmt.Errorf("%w: %v", ErrRegexpTimeout, err)
+	}
+	return ok, nil
+}
+
+func (r ecmaRegexp) String() string {
+	return r.re.String()
+}
+
+type parseOptWithRegexpEngine struct {
+	engine RegexpEngine
+}
+
+func (o parseOptWithRegexpEngine) Apply(opt *parserOptions) {
+	opt.regexpEngine = o.engine
+}
+
+// OptWithRegexpEngine sets the engine that compiles pattern facets and pattern properties.
+// RE2Engine is used by default.
+func OptWithRegexpEngine(engine RegexpEngine) ParseOpt {
+	return parseOptWithRegexpEngine{engine: engine}
+}
+
+// compileRegexp compiles the expression with the regexp engine of the RAML.
+func (r *RAML) compileRegexp(expr string) (Regexp, error) {
+	if r.regexpEngine == nil {
+		return RE2Engine{}.Compile(expr)
+	}
+	return r.regexpEngine.Compile(expr)
+}
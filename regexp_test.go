@@ -0,0 +1,75 @@
+package raml
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/acronis/go-raml/stacktrace"
+)
+
+func TestRegexpEngines(t *testing.T) {
+	tests := []struct {
+		name    string
+		engine  RegexpEngine
+		expr    string
+		input   string
+		want    bool
+		wantErr bool
+	}{
+		{name: "re2", engine: RE2Engine{}, expr: `^\d+$`, input: "123", want: true},
+		{name: "re2 lookahead", engine: RE2Engine{}, expr: `^(?=a)a$`, wantErr: true},
+		{name: "ecmascript lookahead", engine: NewECMAScriptEngine(), expr: `^(?=.*\d)[a-z\d]+$`, input: "abc1", want: true},
+		{name: "ecmascript negative lookahead", engine: NewECMAScriptEngine(), expr: `^(?!admin$)\w+$`, input: "admin", want: false},
+		{name: "ecmascript backreference", engine: NewECMAScriptEngine(), expr: `^(\w)\1$`, input: "aa", want: true},
+		{name: "ecmascript digits are ascii", engine: NewECMAScriptEngine(), expr: `^\d$`, input: "٣", want: false},
+		{name: "ecmascript invalid", engine: NewECMAScriptEngine(), expr: `(`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re, err := tt.engine.Compile(tt.expr)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expr, re.String())
+			require.Equal(t, tt.want, re.MatchString(tt.input))
+		})
+	}
+}
+
+func TestECMAScriptEngine_Timeout(t *testing.T) {
+	re, err := (&ECMAScriptEngine{Timeout: time.Millisecond}).Compile(`^(a+)+$`)
+	require.NoError(t, err)
+	input := strings.Repeat("a", 40) + "b"
+
+	require.False(t, re.MatchString(input))
+	_, err = matchRegexp(re, input)
+	require.ErrorIs(t, err, ErrRegexpTimeout)
+
+	s := &StringShape{StringFacets: StringFacets{Pattern: re}}
+	err = s.Validate(input, "$")
+	require.ErrorIs(t, err, ErrRegexpTimeout)
+	require.False(t, errors.Is(s.Validate("aaa", "$"), ErrRegexpTimeout))
+}
+
+func TestValidate_PatternPropertyTimeout(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0 Library
+types:
+  Labels:
+    type: object
+    properties:
+      /^(a+)+$/: string
+`, OptWithRegexpEngine(&ECMAScriptEngine{Timeout: time.Millisecond}))
+	s := libraryType(t, rml, "Labels")
+	key := strings.Repeat("a", 40) + "b"
+	require.ErrorIs(t, s.Validate(map[string]interface{}{key: "x"}, "$"), ErrRegexpTimeout)
+	st, ok := stacktrace.Unwrap(ValidateDocument(s, []byte(`{"`+key+`": "x"}`), DocumentFormatJSON))
+	require.True(t, ok)
+	require.ErrorIs(t, st.Err, ErrRegexpTimeout)
+	require.ErrorIs(t, ValidateStream(s, strings.NewReader(`{"`+key+`": "x"}`), "$"), ErrRegexpTimeout)
+}
@@ -3,7 +3,6 @@ package raml
 import (
 	"fmt"
 	"math/big"
-	"regexp"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -41,7 +40,7 @@ func isCompatibleEnum(source Nodes, target Nodes) bool {
 	for _, v := range target {
 		found := false
 		for _, e := range source {
-			if v.Value == e.Value {
+			if valuesEqual(v.Value, e.Value) {
 				found = true
 				break
 			}
@@ -85,18 +84,14 @@ func (s *IntegerShape) clone(history []Shape) Shape {
 }
 
 func (s *IntegerShape) Validate(v interface{}, ctxPath string) error {
-	var val big.Int
-	switch v := v.(type) {
-	case int:
-		val.SetInt64(int64(v))
-	case uint:
-		val.SetUint64(uint64(v))
-	// json unmarshals numbers as float64
-	case float64:
-		val.SetInt64(int64(v))
-	default:
-		return fmt.Errorf("invalid type, got %T, expected int, uint or float64", v)
+	r, err := numericValue(v)
+	if err != nil {
+		return err
 	}
+	if !r.IsInt() {
+		return fmt.Errorf("value must be an integer, got %v", v)
+	}
+	val := r.Num()
 
 	if s.Minimum != nil && val.Cmp(s.Minimum) < 0 {
 		return fmt.Errorf("value must be greater than %s", s.Minimum.String())
@@ -104,26 +99,14 @@ func (s *IntegerShape) Validate(v interface{}, ctxPath string) error {
 	if s.Maximum != nil && val.Cmp(s.Maximum) > 0 {
 		return fmt.Errorf("value must be less than %s", s.Maximum.String())
 	}
-	// TODO: Implement multipleOf validation
-	// TODO: Implement format validation
-	if s.Enum != nil {
-		// TODO: Probably enum values should be stored as big.Int to simplify validation
-		var num any
-		if val.IsInt64() {
-			num = int(val.Int64())
-		} else if val.IsUint64() {
-			num = uint(val.Uint64())
-		}
-		found := false
-		for _, e := range s.Enum {
-			if e.Value == num {
-				found = true
-				break
-			}
-		}
-		if !found {
-			return fmt.Errorf("value must be one of (%s)", s.Enum.String())
-		}
+	if s.MultipleOf != nil && !isMultipleOf(r, *s.MultipleOf) {
+		return fmt.Errorf("value must be a multiple of %v", *s.MultipleOf)
+	}
+	if lo, hi := integerFormatBounds(s.Format); lo != nil && (val.Cmp(lo) < 0 || val.Cmp(hi) > 0) {
+		return fmt.Errorf("value is out of range of format %s", *s.Format)
+	}
+	if s.Enum != nil && !enumContains(s.Enum, r) {
+		return fmt.Errorf("value must be one of (%s)", s.Enum.String())
 	}
 
 	return nil
@@ -149,7 +132,6 @@ func (s *IntegerShape) Inherit(source Shape) (Shape, error) {
 	}
 	// TODO: multipleOf validation
 	if s.MultipleOf == nil {
-		// TODO: Disallow multipleOf 0 to avoid division by zero during validation
 		s.MultipleOf = ss.MultipleOf
 	}
 	if s.Enum == nil {
@@ -171,6 +153,9 @@ func (s *IntegerShape) Check() error {
 	if s.Minimum != nil && s.Maximum != nil && s.Minimum.Cmp(s.Maximum) > 0 {
 		return stacktrace.New("minimum must be less than or equal to maximum", s.Location, stacktrace.WithPosition(&s.Position))
 	}
+	if s.MultipleOf != nil && *s.MultipleOf <= 0 {
+		return stacktrace.New("multipleOf must be greater than 0", s.Location, stacktrace.WithPosition(&s.Position))
+	}
 	if s.Enum != nil {
 		for _, e := range s.Enum {
 			switch e.Value.(type) {
@@ -265,38 +250,25 @@ func (s *NumberShape) clone(history []Shape) Shape {
 }
 
 func (s *NumberShape) Validate(v interface{}, ctxPath string) error {
-	var val float64
-	switch v := v.(type) {
-	// go-yaml unmarshals integers as int
-	case int:
-		val = float64(v)
-	case uint:
-		val = float64(v)
-	case float64:
-		val = v
-	default:
-		return fmt.Errorf("invalid type, got %T, expected int, uint, float64", v)
-	}
-
-	if s.Minimum != nil && val < *s.Minimum {
+	val, err := numericValue(v)
+	if err != nil {
+		return err
+	}
+
+	if s.Minimum != nil && val.Cmp(decimalValue(*s.Minimum)) < 0 {
 		return fmt.Errorf("value must be greater than %f", *s.Minimum)
 	}
-	if s.Maximum != nil && val > *s.Maximum {
+	if s.Maximum != nil && val.Cmp(decimalValue(*s.Maximum)) > 0 {
 		return fmt.Errorf("value must be less than %f", *s.Maximum)
 	}
-	// TODO: Implement multipleOf validation
-	// TODO: Implement format validation
-	if s.Enum != nil {
-		found := false
-		for _, e := range s.Enum {
-			if e.Value == val {
-				found = true
-				break
-			}
-		}
-		if !found {
-			return fmt.Errorf("value must be one of (%s)", s.Enum.String())
-		}
+	if s.MultipleOf != nil && !isMultipleOf(val, *s.MultipleOf) {
+		return fmt.Errorf("value must be a multiple of %v", *s.MultipleOf)
+	}
+	if lo, hi := numberFormatBounds(s.Format); lo != nil && (val.Cmp(lo) < 0 || val.Cmp(hi) > 0) {
+		return fmt.Errorf("value is out of range of format %s", *s.Format)
+	}
+	if s.Enum != nil && !enumContains(s.Enum, val) {
+		return fmt.Errorf("value must be one of (%s)", s.Enum.String())
 	}
 
 	return nil
@@ -322,7 +294,6 @@ func (s *NumberShape) Inherit(source Shape) (Shape, error) {
 	}
 	// TODO: multipleOf validation
 	if ss.MultipleOf != nil {
-		// TODO: Disallow multipleOf 0 to avoid division by zero during validation
 		s.MultipleOf = ss.MultipleOf
 	}
 	if s.Enum == nil {
@@ -344,6 +315,9 @@ func (s *NumberShape) Check() error {
 	if s.Minimum != nil && s.Maximum != nil && *s.Minimum > *s.Maximum {
 		return stacktrace.New("minimum must be less than or equal to maximum", s.Location, stacktrace.WithPosition(&s.Position))
 	}
+	if s.MultipleOf != nil && *s.MultipleOf <= 0 {
+		return stacktrace.New("multipleOf must be greater than 0", s.Location, stacktrace.WithPosition(&s.Position))
+	}
 	if s.Enum != nil {
 		for _, e := range s.Enum {
 			switch e.Value.(type) {
@@ -404,7 +378,7 @@ type LengthFacets struct {
 
 type StringFacets struct {
 	LengthFacets
-	Pattern *regexp.Regexp
+	Pattern Regexp
 }
 
 type StringShape struct {
@@ -440,21 +414,18 @@ func (s *StringShape) Validate(v interface{}, ctxPath string) error {
 	if s.MaxLength != nil && strLen > *s.MaxLength {
 		return fmt.Errorf("length must be less than %d", *s.MaxLength)
 	}
-	if s.Pattern != nil && !s.Pattern.MatchString(i) {
-		return fmt.Errorf("must match pattern %s", s.Pattern.String())
-	}
-	if s.Enum != nil {
-		found := false
-		for _, e := range s.Enum {
-			if e.Value == i {
-				found = true
-				break
-			}
+	if s.Pattern != nil {
+		matched, err := matchRegexp(s.Pattern, i)
+		if err != nil {
+			return fmt.Errorf("match pattern %s: %w", s.Pattern.String(), err)
 		}
-		if !found {
-			return fmt.Errorf("value must be one of (%s)", s.Enum.String())
+		if !matched {
+			return fmt.Errorf("must match pattern %s", s.Pattern.String())
 		}
 	}
+	if s.Enum != nil && !enumContains(s.Enum, i) {
+		return fmt.Errorf("value must be one of (%s)", s.Enum.String())
+	}
 
 	return nil
 }
@@ -522,7 +493,7 @@ func (s *StringShape) unmarshalYAMLNodes(v []*yaml.Node) error {
 				return stacktrace.New("pattern must be string", s.Location, stacktrace.WithNodePosition(valueNode))
 			}
 
-			re, err := regexp.Compile(valueNode.Value)
+			re, err := s.raml.compileRegexp(valueNode.Value)
 			if err != nil {
 				return stacktrace.NewWrapped("decode pattern", err, s.Location, stacktrace.WithNodePosition(valueNode))
 			}
@@ -568,21 +539,41 @@ func (s *FileShape) clone(history []Shape) Shape {
 	return s.Clone()
 }
 
+// Validate validates file content given as []byte, io.Reader or base64 encoded string.
+// Strings, including examples, must be base64 encoded. Lengths are compared with the size of the decoded content in bytes.
+// Readers are read up to the bytes needed for validation, see fileContent for how they are left.
 func (s *FileShape) Validate(v interface{}, ctxPath string) error {
-	i, ok := v.(string)
-	if !ok {
-		return fmt.Errorf("invalid type, got %T, expected string", v)
+	limit := int64(fileSniffLen)
+	if s.MaxLength != nil {
+		limit = max(int64(*s.MaxLength)+1, fileSniffLen)
+	} else if s.MinLength != nil {
+		limit = max(int64(*s.MinLength), fileSniffLen)
+	}
+	data, err := fileContent(v, limit)
+	if err != nil {
+		return err
 	}
 
-	// TODO: What is compared, byte size or base64 string size?
-	strLen := uint64(len(i))
-	if s.MinLength != nil && strLen < *s.MinLength {
+	dataLen := uint64(len(data))
+	if s.MinLength != nil && dataLen < *s.MinLength {
 		return fmt.Errorf("length must be greater than %d", *s.MinLength)
 	}
-	if s.MaxLength != nil && strLen > *s.MaxLength {
+	if s.MaxLength != nil && dataLen > *s.MaxLength {
 		return fmt.Errorf("length must be less than %d", *s.MaxLength)
 	}
-	// TODO: Validation against file types
+	if len(s.FileTypes) > 0 {
+		detected := detectFileType(data)
+		found := false
+		for _, e := range s.FileTypes {
+			if declared, ok := e.Value.(string); ok && matchFileType(declared, detected) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("content type %s must be one of (%s)", detected, s.FileTypes.String())
+		}
+	}
 
 	return nil
 }
@@ -693,17 +684,8 @@ func (s *BooleanShape) Validate(v interface{}, ctxPath string) error {
 		return fmt.Errorf("invalid type, got %T, expected bool", v)
 	}
 
-	if s.Enum != nil {
-		found := false
-		for _, e := range s.Enum {
-			if e.Value == i {
-				found = true
-				break
-			}
-		}
-		if !found {
-			return fmt.Errorf("value must be one of (%s)", s.Enum.String())
-		}
+	if s.Enum != nil && !enumContains(s.Enum, i) {
+		return fmt.Errorf("value must be one of (%s)", s.Enum.String())
 	}
 
 	return nil
@@ -780,25 +762,19 @@ func (s *DateTimeShape) clone(history []Shape) Shape {
 func (s *DateTimeShape) Validate(v interface{}, ctxPath string) error {
 	i, ok := v.(string)
 	if !ok {
-		return fmt.Errorf("invalid type, got %T, expected string", v)
+		// time.Time has no format and is representable by any date type.
+		if _, ok := v.(time.Time); ok {
+			return nil
+		}
+		return fmt.Errorf("invalid type, got %T, expected string or time.Time", v)
 	}
 
-	if s.Format == nil {
-		if _, err := time.Parse(time.RFC3339, i); err != nil {
-			return fmt.Errorf("value must match format %s", time.RFC3339)
-		}
-	} else {
-		switch *s.Format {
-		case "rfc3339":
-			if _, err := time.Parse(time.RFC3339, i); err != nil {
-				return fmt.Errorf("value must match format %s", time.RFC3339)
-			}
-		// TODO: https://www.rfc-editor.org/rfc/rfc7231#section-7.1.1.1
-		case "rfc2616":
-			if _, err := time.Parse(RFC2616, i); err != nil {
-				return fmt.Errorf("value must match format %s", RFC2616)
-			}
+	if s.Format != nil && *s.Format == "rfc2616" {
+		if _, err := parseHTTPDate(i); err != nil {
+			return fmt.Errorf("value must match format %s: %w", RFC2616, err)
 		}
+	} else if _, err := parseRFC3339(i); err != nil {
+		return fmt.Errorf("value must match format %s: %w", time.RFC3339, err)
 	}
 
 	return nil
@@ -868,11 +844,14 @@ func (s *DateTimeOnlyShape) clone(history []Shape) Shape {
 func (s *DateTimeOnlyShape) Validate(v interface{}, ctxPath string) error {
 	i, ok := v.(string)
 	if !ok {
-		return fmt.Errorf("invalid type, got %T, expected string", v)
+		if _, ok := v.(time.Time); ok {
+			return nil
+		}
+		return fmt.Errorf("invalid type, got %T, expected string or time.Time", v)
 	}
 
-	if _, err := time.Parse(DateTime, i); err != nil {
-		return fmt.Errorf("value must match format %s", DateTime)
+	if _, err := parseDateTimeOnly(i); err != nil {
+		return fmt.Errorf("value must match format %s: %w", DateTime, err)
 	}
 
 	return nil
@@ -925,11 +904,14 @@ func (s *DateOnlyShape) clone(history []Shape) Shape {
 func (s *DateOnlyShape) Validate(v interface{}, ctxPath string) error {
 	i, ok := v.(string)
 	if !ok {
-		return fmt.Errorf("invalid type, got %T, expected string", v)
+		if _, ok := v.(time.Time); ok {
+			return nil
+		}
+		return fmt.Errorf("invalid type, got %T, expected string or time.Time", v)
 	}
 
-	if _, err := time.Parse(time.DateOnly, i); err != nil {
-		return fmt.Errorf("value must match format %s", time.DateOnly)
+	if _, err := parseDateOnly(i); err != nil {
+		return fmt.Errorf("value must match format %s: %w", time.DateOnly, err)
 	}
 
 	return nil
@@ -982,11 +964,14 @@ func (s *TimeOnlyShape) clone(history []Shape) Shape {
 func (s *TimeOnlyShape) Validate(v interface{}, ctxPath string) error {
 	i, ok := v.(string)
 	if !ok {
-		return fmt.Errorf("invalid type, got %T, expected string", v)
+		if _, ok := v.(time.Time); ok {
+			return nil
+		}
+		return fmt.Errorf("invalid type, got %T, expected string or time.Time", v)
 	}
 
-	if _, err := time.Parse(time.TimeOnly, i); err != nil {
-		return fmt.Errorf("value must match format %s", time.TimeOnly)
+	if _, err := parseTimeOnly(i); err != nil {
+		return fmt.Errorf("value must match format %s: %w", time.TimeOnly, err)
 	}
 
 	return nil
@@ -1,7 +1,12 @@
 package raml
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 
 	orderedmap "github.com/wk8/go-ordered-map/v2"
 )
@@ -63,9 +68,12 @@ type JSONSchema struct {
 	Description string `json:"description,omitempty"`
 	Default     any    `json:"default,omitempty"`
 	Examples    []any  `json:"examples,omitempty"`
+	ReadOnly    bool   `json:"readOnly,omitempty"`
+	WriteOnly   bool   `json:"writeOnly,omitempty"`
+	Deprecated  bool   `json:"deprecated,omitempty"`
 
-	// TODO: There's no better way to serialize custom properties on the same level in Go.
-	Extras map[string]any `json:"x-custom,omitempty"`
+	// Extras holds extension keywords that are serialized on the same level as standard keywords.
+	Extras map[string]any `json:"-"`
 
 	// Special boolean representation of the Schema
 	boolean *bool
@@ -82,3 +90,94 @@ var (
 // http://json-schema.org/latest/json-schema-validation.html#rfc.section.5.26
 // RFC draft-wright-json-schema-validation-00, section 5.26
 type Definitions map[string]*JSONSchema
+
+// jsonSchemaKeywords is a set of keywords that are mapped to JSONSchema fields.
+var jsonSchemaKeywords = func() map[string]struct{} {
+	keywords := make(map[string]struct{})
+	t := reflect.TypeOf(JSONSchema{})
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("json")
+		name, _, _ := strings.Cut(tag, ",")
+		if name != "" && name != "-" {
+			keywords[name] = struct{}{}
+		}
+	}
+	return keywords
+}()
+
+// MarshalJSON implements json.Marshaler.
+// Extras are written as top-level keywords in a sorted order.
+func (s *JSONSchema) MarshalJSON() ([]byte, error) {
+	if s.boolean != nil {
+		return json.Marshal(*s.boolean)
+	}
+	type schemaAlias JSONSchema
+	b, err := json.Marshal((*schemaAlias)(s))
+	if err != nil {
+		return nil, err
+	}
+	if len(s.Extras) == 0 {
+		return b, nil
+	}
+	keys := make([]string, 0, len(s.Extras))
+	for k := range s.Extras {
+		if _, ok := jsonSchemaKeywords[k]; ok {
+			return nil, fmt.Errorf("extension keyword %q conflicts with standard keyword", k)
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.Write(b[:len(b)-1])
+	for _, k := range keys {
+		kb, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		vb, err := json.Marshal(s.Extras[k])
+		if err != nil {
+			return nil, fmt.Errorf("marshal extension keyword %q: %w", k, err)
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(kb)
+		buf.WriteByte(':')
+		buf.Write(vb)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// Unknown keywords are collected into Extras.
+func (s *JSONSchema) UnmarshalJSON(data []byte) error {
+	var b bool
+	if err := json.Unmarshal(data, &b); err == nil {
+		s.boolean = &b
+		return nil
+	}
+	type schemaAlias JSONSchema
+	if err := json.Unmarshal(data, (*schemaAlias)(s)); err != nil {
+		return err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	for k, v := range raw {
+		if _, ok := jsonSchemaKeywords[k]; ok {
+			continue
+		}
+		var val any
+		if err := json.Unmarshal(v, &val); err != nil {
+			return fmt.Errorf("unmarshal extension keyword %q: %w", k, err)
+		}
+		if s.Extras == nil {
+			s.Extras = make(map[string]any)
+		}
+		s.Extras[k] = val
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package raml
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONSchema_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  *JSONSchema
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "without extras",
+			schema: &JSONSchema{Type: "string"},
+			want:   `{"type":"string"}`,
+		},
+		{
+			name: "extras are sorted top-level keywords",
+			schema: &JSONSchema{
+				Type:   "object",
+				Extras: map[string]any{"x-b": 1, "x-a": map[string]any{"c": true}},
+			},
+			want: `{"type":"object","x-a":{"c":true},"x-b":1}`,
+		},
+		{
+			name:   "extras of empty schema",
+			schema: &JSONSchema{Extras: map[string]any{"x-a": "v"}},
+			want:   `{"x-a":"v"}`,
+		},
+		{
+			name:    "extra conflicts with standard keyword",
+			schema:  &JSONSchema{Type: "string", Extras: map[string]any{"type": "number"}},
+			wantErr: true,
+		},
+		{
+			name:   "boolean schema",
+			schema: TrueSchema,
+			want:   `true`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.schema)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestJSONSchema_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		wantType   string
+		wantExtras map[string]any
+		wantBool   *bool
+	}{
+		{
+			name:     "standard keywords only",
+			data:     `{"type":"string","minLength":1}`,
+			wantType: "string",
+		},
+		{
+			name:       "unknown keywords are extras",
+			data:       `{"type":"object","x-domainExt-a":"v","x-shapeExt-data-b":[1,2]}`,
+			wantType:   "object",
+			wantExtras: map[string]any{"x-domainExt-a": "v", "x-shapeExt-data-b": []any{float64(1), float64(2)}},
+		},
+		{
+			name:     "boolean schema",
+			data:     `false`,
+			wantBool: func() *bool { b := false; return &b }(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s JSONSchema
+			require.NoError(t, json.Unmarshal([]byte(tt.data), &s))
+			require.Equal(t, tt.wantType, s.Type)
+			require.Equal(t, tt.wantExtras, s.Extras)
+			require.Equal(t, tt.wantBool, s.boolean)
+
+			// The schema must survive a round-trip.
+			b, err := json.Marshal(&s)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.data, string(b))
+		})
+	}
+}
@@ -26,6 +26,7 @@ type ShapeVisitor[T any] interface {
 	VisitTimeOnlyShape(s *TimeOnlyShape) T
 	VisitRecursiveShape(s *RecursiveShape) T
 	VisitJSONShape(s *JSONShape) T
+	VisitXMLShape(s *XMLShape) T
 	VisitAnyShape(s *AnyShape) T
 	VisitNilShape(s *NilShape) T
 }
@@ -43,6 +44,7 @@ type BaseShape struct {
 	Alias       *Shape
 	Default     *Node
 	Required    *bool
+	XML         *XML
 	unwrapped   bool
 
 	// To support !include of DataType fragment
@@ -180,6 +182,17 @@ func (r *RAML) MakeJSONShape(base *BaseShape, rawSchema string) (Shape, error) {
 	return &JSONShape{BaseShape: *base, Raw: rawSchema, Schema: schema}, nil
 }
 
+func (r *RAML) MakeXMLShape(base *BaseShape, rawSchema string) (Shape, error) {
+	base.Type = TypeXML
+
+	schema, err := ParseXSD([]byte(rawSchema))
+	if err != nil {
+		return nil, stacktrace.NewWrapped("parse xsd", err, base.Location, stacktrace.WithPosition(&base.Position))
+	}
+
+	return &XMLShape{BaseShape: *base, Raw: rawSchema, Schema: schema}, nil
+}
+
 // MakeConcreteShape creates a new concrete shape.
 func (r *RAML) MakeConcreteShape(base *BaseShape, shapeType string, shapeFacets []*yaml.Node) (Shape, error) {
 	base.Type = shapeType
@@ -220,6 +233,8 @@ func (r *RAML) MakeConcreteShape(base *BaseShape, shapeType string, shapeFacets
 		shape = &UnionShape{BaseShape: *base}
 	case TypeJSON:
 		shape = &JSONShape{BaseShape: *base}
+	case TypeXML:
+		shape = &XMLShape{BaseShape: *base}
 	}
 
 	if err := shape.unmarshalYAMLNodes(shapeFacets); err != nil {
@@ -281,6 +296,12 @@ func (r *RAML) makeShape(v *yaml.Node, name string, location string) (*Shape, er
 						return nil, stacktrace.NewWrapped("make json shape", err, location, stacktrace.WithNodePosition(shapeTypeNode))
 					}
 					return &s, nil
+				} else if shapeType[0] == '<' {
+					s, err := r.MakeXMLShape(base, shapeType)
+					if err != nil {
+						return nil, stacktrace.NewWrapped("make xml shape", err, location, stacktrace.WithNodePosition(shapeTypeNode))
+					}
+					return &s, nil
 				}
 			} else if shapeTypeNode.Tag == "!include" {
 				baseDir := filepath.Dir(location)
@@ -416,6 +437,12 @@ func (s *BaseShape) decode(value *yaml.Node) (*yaml.Node, []*yaml.Node, error) {
 				return nil, nil, stacktrace.NewWrapped("make node default", err, s.Location, stacktrace.WithNodePosition(valueNode))
 			}
 			s.Default = n
+		} else if node.Value == "xml" {
+			x, err := s.raml.makeXML(valueNode, s.Location)
+			if err != nil {
+				return nil, nil, stacktrace.NewWrapped("make xml", err, s.Location, stacktrace.WithNodePosition(valueNode))
+			}
+			s.XML = x
 		} else if node.Value == "allowedTargets" {
 			// TODO: Included by annotationTypes
 		} else {
@@ -20,6 +20,7 @@ const (
 	TypeResolving  Type = "resolving"
 	TypeValidating Type = "validating"
 	TypeUnwrapping Type = "unwrapping"
+	TypeConverting Type = "converting"
 )
 
 // Severity is the severity of the error.
@@ -0,0 +1,429 @@
+package raml
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// errStreamLimit stops streaming validation when the maximum number of violations is reached.
+var errStreamLimit = errors.New("violation limit reached")
+
+// StreamError is a violation found by streaming validation.
+type StreamError struct {
+	// Path is the JSON path of the value, e.g. "$.a[0]".
+	Path string
+	// Offset is the byte offset of the value in the input.
+	Offset int64
+	Err    error
+}
+
+func (e *StreamError) Error() string {
+	return fmt.Sprintf("%s (offset %d): %v", e.Path, e.Offset, e.Err)
+}
+
+func (e *StreamError) Unwrap() error {
+	return e.Err
+}
+
+// StreamErrors is a list of violations found by streaming validation.
+type StreamErrors []*StreamError
+
+func (e StreamErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the violations, so that errors.Is and errors.As inspect each of them.
+func (e StreamErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+type StreamValidatorOpt interface {
+	Apply(*StreamValidatorOptions)
+}
+
+type optStreamMaxErrors struct {
+	n int
+}
+
+func (o optStreamMaxErrors) Apply(e *StreamValidatorOptions) {
+	e.maxErrors = o.n
+}
+
+// WithStreamMaxErrors stops validation after n violations. Zero means no limit.
+func WithStreamMaxErrors(n int) StreamValidatorOpt {
+	return optStreamMaxErrors{n: n}
+}
+
+type StreamValidatorOptions struct {
+	maxErrors int
+}
+
+// ValidateStream validates a JSON document read from r against the shape without decoding it as a whole.
+// The shape tree is walked in lockstep with the tokens of json.Decoder, so memory is proportional
+// to the nesting depth. Items of arrays with uniqueItems, values of unions and values that match
+// pattern properties are decoded to be validated with Validate.
+// Violations are returned as StreamErrors with JSON paths and byte offsets,
+// malformed JSON and read failures are returned as other errors.
+func ValidateStream(s Shape, r io.Reader, ctxPath string, opts ...StreamValidatorOpt) error {
+	or := &offsetReader{r: r}
+	v := &streamValidator{d: json.NewDecoder(or), r: or}
+	for _, opt := range opts {
+		opt.Apply(&v.opts)
+	}
+	v.d.UseNumber()
+	tok, err := v.token()
+	if err != nil {
+		return fmt.Errorf("read token: %w", err)
+	}
+	if err := v.validate(s, tok, ctxPath); err != nil && !errors.Is(err, errStreamLimit) {
+		return err
+	} else if err == nil && v.d.More() {
+		return fmt.Errorf("unexpected data after top-level value at offset %d", v.d.InputOffset())
+	}
+	if len(v.errs) > 0 {
+		return v.errs
+	}
+	return nil
+}
+
+type streamValidator struct {
+	opts StreamValidatorOptions
+	d    *json.Decoder
+	r    *offsetReader
+	errs StreamErrors
+	// start is the offset of the start of the token that was read last.
+	start int64
+}
+
+// token reads the next token and records its start offset.
+func (v *streamValidator) token() (json.Token, error) {
+	prev := v.d.InputOffset()
+	v.r.mark = prev
+	tok, err := v.d.Token()
+	if err == nil {
+		v.start = v.r.tokenStart(prev)
+	}
+	return tok, err
+}
+
+// fail records a violation. The returned error stops validation when the limit is reached.
+func (v *streamValidator) fail(path string, offset int64, err error) error {
+	v.errs = append(v.errs, &StreamError{Path: path, Offset: offset, Err: err})
+	if v.opts.maxErrors > 0 && len(v.errs) >= v.opts.maxErrors {
+		return errStreamLimit
+	}
+	return nil
+}
+
+// validate validates the value that starts with the token.
+func (v *streamValidator) validate(s Shape, tok json.Token, path string) error {
+	offset := v.start
+	switch s := s.(type) {
+	case *ObjectShape:
+		if tok == json.Delim('{') {
+			return v.validateObject(s, path, offset)
+		}
+	case *ArrayShape:
+		if tok == json.Delim('[') {
+			return v.validateArray(s, path, offset)
+		}
+	case *RecursiveShape:
+		return v.validate(*s.Head, tok, path)
+	case *AnyShape:
+		return v.skip(tok)
+	}
+	val, err := v.value(tok)
+	if err != nil {
+		return err
+	}
+	if err := s.Validate(val, path); err != nil {
+		return v.fail(path, offset, err)
+	}
+	return nil
+}
+
+func (v *streamValidator) validateObject(s *ObjectShape, path string, offset int64) error {
+	restrictedAdditionalProperties := s.AdditionalProperties != nil && !*s.AdditionalProperties
+	var count uint64
+	present := make(map[string]struct{})
+	for v.d.More() {
+		keyTok, err := v.token()
+		if err != nil {
+			return fmt.Errorf("read token: %w", err)
+		}
+		k := keyTok.(string)
+		keyOffset := v.start
+		count++
+		tok, err := v.token()
+		if err != nil {
+			return fmt.Errorf("read token: %w", err)
+		}
+		ctxPath := path + "." + k
+		// Explicitly defined properties have priority over pattern properties.
+		if s.Properties != nil {
+			if p, ok := s.Properties.Get(k); ok {
+				present[k] = struct{}{}
+				if err := v.validate(*p.Shape, tok, ctxPath); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		var patterns []Shape
+		if s.PatternProperties != nil {
+			for pair := s.PatternProperties.Oldest(); pair != nil; pair = pair.Next() {
+				matched, err := matchRegexp(pair.Value.Pattern, k)
+				if err != nil {
+					if err := v.fail(ctxPath, keyOffset, err); err != nil {
+						return err
+					}
+				}
+				if matched {
+					patterns = append(patterns, *pair.Value.Shape)
+				}
+			}
+		}
+		switch {
+		case len(patterns) > 0:
+			// The first pattern property the value is valid against prevails, so the value is decoded.
+			// A value that is valid against none of them is an additional property as in ObjectShape.Validate.
+			val, err := v.value(tok)
+			if err != nil {
+				return err
+			}
+			found := false
+			for _, ps := range patterns {
+				if ps.Validate(val, ctxPath) == nil {
+					found = true
+					break
+				}
+			}
+			if found {
+				continue
+			}
+		default:
+			if err := v.skip(tok); err != nil {
+				return err
+			}
+		}
+		if restrictedAdditionalProperties {
+			if err := v.fail(ctxPath, keyOffset, fmt.Errorf("unexpected additional property \"%s\"", k)); err != nil {
+				return err
+			}
+		}
+	}
+	// Closing delimiter.
+	if _, err := v.token(); err != nil {
+		return fmt.Errorf("read token: %w", err)
+	}
+	if s.MinProperties != nil && count < *s.MinProperties {
+		return v.fail(path, offset, fmt.Errorf("object must have at least %d properties", *s.MinProperties))
+	}
+	if s.MaxProperties != nil && count > *s.MaxProperties {
+		return v.fail(path, offset, fmt.Errorf("object must have not more than %d properties", *s.MaxProperties))
+	}
+	if s.Properties != nil {
+		for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+			if _, ok := present[pair.Key]; !ok && pair.Value.Required {
+				if err := v.fail(path, offset, fmt.Errorf("required property \"%s\" is missing", pair.Key)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (v *streamValidator) validateArray(s *ArrayShape, path string, offset int64) error {
+	validateUniqueItems := s.UniqueItems != nil && *s.UniqueItems
+	var uniqueItems map[string]struct{}
+	if validateUniqueItems {
+		uniqueItems = make(map[string]struct{})
+	}
+	var count uint64
+	for v.d.More() {
+		tok, err := v.token()
+		if err != nil {
+			return fmt.Errorf("read token: %w", err)
+		}
+		ctxPath := path + "[" + strconv.FormatUint(count, 10) + "]"
+		count++
+		if !validateUniqueItems {
+			if s.Items == nil {
+				err = v.skip(tok)
+			} else {
+				err = v.validate(*s.Items, tok, ctxPath)
+			}
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		itemOffset := v.start
+		val, err := v.value(tok)
+		if err != nil {
+			return err
+		}
+		if s.Items != nil {
+			if err := (*s.Items).Validate(val, ctxPath); err != nil {
+				if err := v.fail(ctxPath, itemOffset, err); err != nil {
+					return err
+				}
+			}
+		}
+		key := valueKey(val)
+		if _, ok := uniqueItems[key]; ok {
+			if err := v.fail(ctxPath, itemOffset, fmt.Errorf("array contains duplicate items")); err != nil {
+				return err
+			}
+		}
+		uniqueItems[key] = struct{}{}
+	}
+	// Closing delimiter.
+	if _, err := v.token(); err != nil {
+		return fmt.Errorf("read token: %w", err)
+	}
+	if s.MinItems != nil && count < *s.MinItems {
+		return v.fail(path, offset, fmt.Errorf("array must have at least %d items", *s.MinItems))
+	}
+	if s.MaxItems != nil && count > *s.MaxItems {
+		return v.fail(path, offset, fmt.Errorf("array must have not more than %d items", *s.MaxItems))
+	}
+	return nil
+}
+
+// value decodes the value that starts with the token.
+func (v *streamValidator) value(tok json.Token) (interface{}, error) {
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '{' {
+			m := make(map[string]interface{})
+			for v.d.More() {
+				k, err := v.token()
+				if err != nil {
+					return nil, fmt.Errorf("read token: %w", err)
+				}
+				item, err := v.next()
+				if err != nil {
+					return nil, err
+				}
+				m[k.(string)] = item
+			}
+			if _, err := v.token(); err != nil {
+				return nil, fmt.Errorf("read token: %w", err)
+			}
+			return m, nil
+		}
+		items := make([]interface{}, 0)
+		for v.d.More() {
+			item, err := v.next()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		if _, err := v.token(); err != nil {
+			return nil, fmt.Errorf("read token: %w", err)
+		}
+		return items, nil
+	}
+	// Numbers are kept as json.Number, so that Validate checks them exactly as in decoded documents.
+	return tok, nil
+}
+
+// next decodes the next value.
+func (v *streamValidator) next() (interface{}, error) {
+	tok, err := v.token()
+	if err != nil {
+		return nil, fmt.Errorf("read token: %w", err)
+	}
+	return v.value(tok)
+}
+
+// skip skips the value that starts with the token.
+func (v *streamValidator) skip(tok json.Token) error {
+	if tok != json.Delim('{') && tok != json.Delim('[') {
+		return nil
+	}
+	for depth := 1; depth > 0; {
+		t, err := v.token()
+		if err != nil {
+			return fmt.Errorf("read token: %w", err)
+		}
+		switch t {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+	return nil
+}
+
+// jsonNumberValue converts json.Number to int, uint or float64.
+func jsonNumberValue(n json.Number) (interface{}, error) {
+	if i, err := strconv.ParseInt(string(n), 10, 0); err == nil {
+		return int(i), nil
+	}
+	if u, err := strconv.ParseUint(string(n), 10, 0); err == nil {
+		return uint(u), nil
+	}
+	f, err := strconv.ParseFloat(string(n), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %q", n)
+	}
+	return f, nil
+}
+
+// offsetReader keeps the input read by json.Decoder from the mark on,
+// so that start offsets of tokens are found in the input as is, including escapes in strings.
+type offsetReader struct {
+	r io.Reader
+	// buf holds the input starting at offset base.
+	buf  []byte
+	base int64
+	// mark is the offset before which the input is not needed anymore.
+	mark int64
+}
+
+func (o *offsetReader) Read(p []byte) (int, error) {
+	if drop := o.mark - o.base; drop > 0 {
+		o.buf = append(o.buf[:0], o.buf[drop:]...)
+		o.base = o.mark
+	}
+	n, err := o.r.Read(p)
+	o.buf = append(o.buf, p[:n]...)
+	return n, err
+}
+
+// tokenStart returns the offset of the token that follows the offset prev.
+func (o *offsetReader) tokenStart(prev int64) int64 {
+	return o.base + jsonTokenStart(o.buf, prev-o.base)
+}
+
+// jsonTokenStart returns the offset of the token that follows the offset prev in the JSON data,
+// skipping whitespace and separators.
+func jsonTokenStart(data []byte, prev int64) int64 {
+	i := prev
+	for i < int64(len(data)) {
+		switch data[i] {
+		case ' ', '\t', '\n', '\r', ',', ':':
+			i++
+			continue
+		}
+		break
+	}
+	return i
+}
@@ -0,0 +1,179 @@
+package raml
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateStream(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0 Library
+types:
+  Item:
+    type: object
+    additionalProperties: false
+    properties:
+      name:
+        type: string
+        maxLength: 3
+      count?: integer
+  Order:
+    type: object
+    properties:
+      id: integer
+      note?: string
+      items:
+        type: array
+        items: Item
+        maxItems: 2
+      codes?:
+        type: array
+        items: string
+        uniqueItems: true
+`)
+	s := libraryType(t, rml, "Order")
+	type violation struct {
+		path   string
+		offset int64
+	}
+	tests := []struct {
+		name  string
+		input string
+		want  []violation
+	}{
+		{name: "valid", input: `{"id": 1, "items": [{"name": "a"}]}`},
+		{
+			name:  "scalar offset",
+			input: `{"id": "x", "items": []}`,
+			want:  []violation{{path: "$.id", offset: 7}},
+		},
+		{
+			name:  "offset after escapes",
+			input: `{"note": "a\/b\u00e9", "id": "x", "items": []}`,
+			want:  []violation{{path: "$.id", offset: 29}},
+		},
+		{
+			name:  "offset of escaped string value",
+			input: "{\"id\": 1,\n \"items\": [{\"name\": \"\\u0061bcd\"}]}",
+			want:  []violation{{path: "$.items[0].name", offset: 30}},
+		},
+		{
+			name:  "additional property offset is key offset",
+			input: `{"id": 1, "items": [{"name": "a", "x": 1}]}`,
+			want:  []violation{{path: "$.items[0].x", offset: 34}},
+		},
+		{
+			name:  "array offset",
+			input: `{"id": 1, "items": [{"name": "a"}, {"name": "b"}, {"name": "c"}]}`,
+			want:  []violation{{path: "$.items", offset: 19}},
+		},
+		{
+			name:  "required property",
+			input: `{"id": 1}`,
+			want:  []violation{{path: "$", offset: 0}},
+		},
+		{
+			name:  "duplicate item",
+			input: `{"id": 1, "items": [], "codes": ["a", "b", "a"]}`,
+			want:  []violation{{path: "$.codes[2]", offset: 43}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Reading one byte at a time checks offsets across reads of the decoder.
+			err := ValidateStream(s, iotest.OneByteReader(strings.NewReader(tt.input)), "$")
+			if len(tt.want) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			var errs StreamErrors
+			require.ErrorAs(t, err, &errs)
+			got := make([]violation, len(errs))
+			for i, e := range errs {
+				got[i] = violation{path: e.Path, offset: e.Offset}
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidateStream_MaxErrors(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0 Library
+types:
+  Numbers: integer[]
+`)
+	err := ValidateStream(libraryType(t, rml, "Numbers"), strings.NewReader(`["a", "b", "c"]`), "$", WithStreamMaxErrors(2))
+	var errs StreamErrors
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs, 2)
+}
+
+func TestValidateStream_MalformedInput(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0 Library
+types:
+  Numbers: integer[]
+`)
+	s := libraryType(t, rml, "Numbers")
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "empty", input: ""},
+		{name: "unterminated", input: "[1, 2"},
+		{name: "trailing data", input: "[1] [2]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateStream(s, strings.NewReader(tt.input), "$")
+			require.Error(t, err)
+			var errs StreamErrors
+			require.False(t, errors.As(err, &errs))
+		})
+	}
+}
+
+func TestValidateStream_AgreesWithValidate(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0 Library
+types:
+  Even:
+    type: integer
+    multipleOf: 2
+  Extensions:
+    type: object
+    properties:
+      /^x-/: integer
+`)
+	tests := []struct {
+		name    string
+		typ     string
+		input   string
+		wantErr bool
+	}{
+		{name: "big even integer", typ: "Even", input: `18446744073709551618`},
+		{name: "big odd integer", typ: "Even", input: `18446744073709551617`, wantErr: true},
+		{name: "integer with exponent", typ: "Even", input: `1e400`},
+		{name: "fraction", typ: "Even", input: `2.5`, wantErr: true},
+		{name: "matching pattern property", typ: "Extensions", input: `{"x-a": 1}`},
+		{name: "invalid pattern property is additional", typ: "Extensions", input: `{"x-a": "str"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := libraryType(t, rml, tt.typ)
+			d := json.NewDecoder(strings.NewReader(tt.input))
+			d.UseNumber()
+			var v interface{}
+			require.NoError(t, d.Decode(&v))
+			for _, err := range []error{s.Validate(v, "$"), ValidateStream(s, strings.NewReader(tt.input), "$")} {
+				if tt.wantErr {
+					require.Error(t, err)
+				} else {
+					require.NoError(t, err)
+				}
+			}
+		})
+	}
+}
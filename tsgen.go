@@ -0,0 +1,492 @@
+package raml
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+
+	"github.com/acronis/go-raml/stacktrace"
+)
+
+var tsIdentifierRegexp = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsDeclaration is a library type declared in a TypeScript module.
+type tsDeclaration struct {
+	module string
+	name   string
+}
+
+// tsModule is a TypeScript module generated from a library.
+type tsModule struct {
+	lib  *Library
+	path string
+	// imports maps module paths to import aliases.
+	imports *orderedmap.OrderedMap[string, string]
+	types   *orderedmap.OrderedMap[string, Shape]
+}
+
+// TypeScriptGenerator generates TypeScript declaration files from libraries.
+// Visit methods return TypeScript type expressions in the context of the current module.
+type TypeScriptGenerator struct {
+	modules *orderedmap.OrderedMap[string, *tsModule]
+	// declarations maps shape IDs to declared library types.
+	declarations map[string]tsDeclaration
+	current      *tsModule
+	// declaring is the ID of the shape being declared that must not reference itself.
+	declaring string
+	indent    string
+}
+
+var _ ShapeVisitor[string] = (*TypeScriptGenerator)(nil)
+
+func NewTypeScriptGenerator() *TypeScriptGenerator {
+	return &TypeScriptGenerator{}
+}
+
+// GenerateLibrary generates one ".d.ts" module per library, starting with the given one.
+// Modules are keyed by paths relative to the directory of the library, imports mirror "uses".
+func (g *TypeScriptGenerator) GenerateLibrary(lib *Library) (*orderedmap.OrderedMap[string, []byte], error) {
+	g.modules = orderedmap.New[string, *tsModule]()
+	g.declarations = make(map[string]tsDeclaration)
+	if err := g.collectLibrary(lib, filepath.Dir(lib.Location)); err != nil {
+		return nil, err
+	}
+	// Uses are resolved after all modules are known.
+	for pair := g.modules.Oldest(); pair != nil; pair = pair.Next() {
+		m := pair.Value
+		for use := m.lib.Uses.Oldest(); use != nil; use = use.Next() {
+			if use.Value.Link != nil {
+				m.imports.Set(g.modulePath(use.Value.Link, filepath.Dir(lib.Location)), use.Key)
+			}
+		}
+	}
+
+	result := orderedmap.New[string, []byte](g.modules.Len())
+	for pair := g.modules.Oldest(); pair != nil; pair = pair.Next() {
+		result.Set(pair.Key, g.generateModule(pair.Value))
+	}
+	return result, nil
+}
+
+func (g *TypeScriptGenerator) modulePath(lib *Library, baseDir string) string {
+	p, err := filepath.Rel(baseDir, lib.Location)
+	if err != nil {
+		p = filepath.Base(lib.Location)
+	}
+	return strings.TrimSuffix(filepath.ToSlash(p), path.Ext(p)) + ".d.ts"
+}
+
+func (g *TypeScriptGenerator) collectLibrary(lib *Library, baseDir string) error {
+	p := g.modulePath(lib, baseDir)
+	if _, ok := g.modules.Get(p); ok {
+		return nil
+	}
+	m := &tsModule{
+		lib:     lib,
+		path:    p,
+		imports: orderedmap.New[string, string](),
+		types:   orderedmap.New[string, Shape](),
+	}
+	g.modules.Set(p, m)
+	for pair := lib.Types.Oldest(); pair != nil; pair = pair.Next() {
+		s := *pair.Value
+		if !s.Base().IsUnwrapped() {
+			us, err := lib.raml.UnwrapShape(pair.Value, make([]Shape, 0))
+			if err != nil {
+				return stacktrace.NewWrapped("unwrap shape", err, lib.Location, stacktrace.WithPosition(&s.Base().Position),
+					stacktrace.WithType(stacktrace.TypeConverting))
+			}
+			s = us
+		}
+		m.types.Set(pair.Key, s)
+		if _, ok := g.declarations[s.Base().Id]; !ok {
+			g.declarations[s.Base().Id] = tsDeclaration{module: p, name: pair.Key}
+		}
+	}
+	for pair := lib.Uses.Oldest(); pair != nil; pair = pair.Next() {
+		if pair.Value.Link == nil {
+			continue
+		}
+		if err := g.collectLibrary(pair.Value.Link, baseDir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *TypeScriptGenerator) generateModule(m *tsModule) []byte {
+	g.current = m
+	var body strings.Builder
+	for pair := m.types.Oldest(); pair != nil; pair = pair.Next() {
+		s := pair.Value
+		body.WriteString("\n")
+		body.WriteString(tsComment(s.Base(), ""))
+		g.declaring = ""
+		if d := g.declarations[s.Base().Id]; d.module != m.path || d.name != pair.Key {
+			fmt.Fprintf(&body, "export type %s = %s;\n", pair.Key, g.Visit(s))
+			continue
+		}
+		g.declaring = s.Base().Id
+		if o, ok := s.(*ObjectShape); ok {
+			fmt.Fprintf(&body, "export interface %s %s\n", pair.Key, g.VisitObjectShape(o))
+		} else {
+			fmt.Fprintf(&body, "export type %s = %s;\n", pair.Key, g.Visit(s))
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("// Code generated by go-raml. DO NOT EDIT.\n")
+	if m.lib.Usage != "" {
+		fmt.Fprintf(&b, "// %s\n", m.lib.Usage)
+	}
+	if m.imports.Len() > 0 {
+		b.WriteString("\n")
+	}
+	for pair := m.imports.Oldest(); pair != nil; pair = pair.Next() {
+		fmt.Fprintf(&b, "import type * as %s from %q;\n", pair.Value, tsImportPath(m.path, pair.Key))
+	}
+	b.WriteString(body.String())
+	return []byte(b.String())
+}
+
+func tsImportPath(from string, to string) string {
+	rel, err := filepath.Rel(path.Dir(from), to)
+	if err != nil {
+		rel = to
+	}
+	rel = strings.TrimSuffix(filepath.ToSlash(rel), ".d.ts")
+	if !strings.HasPrefix(rel, ".") {
+		rel = "./" + rel
+	}
+	return rel
+}
+
+// Visit returns the TypeScript type expression of the shape.
+// Declared library types are referenced by name, qualified with the import alias for other modules.
+func (g *TypeScriptGenerator) Visit(s Shape) string {
+	id := s.Base().Id
+	if d, ok := g.declarations[id]; ok && id != g.declaring {
+		return g.reference(d)
+	}
+	g.declaring = ""
+	return g.visit(s)
+}
+
+func (g *TypeScriptGenerator) reference(d tsDeclaration) string {
+	if d.module == g.current.path {
+		return d.name
+	}
+	alias, ok := g.current.imports.Get(d.module)
+	if !ok {
+		// Types of transitively used libraries are imported under the module name.
+		alias = tsIdentifier(strings.TrimSuffix(path.Base(d.module), ".d.ts"))
+		for pair := g.current.imports.Oldest(); pair != nil; pair = pair.Next() {
+			if pair.Value == alias {
+				alias += "_"
+			}
+		}
+		g.current.imports.Set(d.module, alias)
+	}
+	return alias + "." + d.name
+}
+
+func (g *TypeScriptGenerator) visit(s Shape) string {
+	switch s := s.(type) {
+	case *ObjectShape:
+		return g.VisitObjectShape(s)
+	case *ArrayShape:
+		return g.VisitArrayShape(s)
+	case *StringShape:
+		return g.VisitStringShape(s)
+	case *NumberShape:
+		return g.VisitNumberShape(s)
+	case *IntegerShape:
+		return g.VisitIntegerShape(s)
+	case *BooleanShape:
+		return g.VisitBooleanShape(s)
+	case *FileShape:
+		return g.VisitFileShape(s)
+	case *UnionShape:
+		return g.VisitUnionShape(s)
+	case *DateTimeShape:
+		return g.VisitDateTimeShape(s)
+	case *DateTimeOnlyShape:
+		return g.VisitDateTimeOnlyShape(s)
+	case *DateOnlyShape:
+		return g.VisitDateOnlyShape(s)
+	case *TimeOnlyShape:
+		return g.VisitTimeOnlyShape(s)
+	case *RecursiveShape:
+		return g.VisitRecursiveShape(s)
+	case *JSONShape:
+		return g.VisitJSONShape(s)
+	case *XMLShape:
+		return g.VisitXMLShape(s)
+	case *AnyShape:
+		return g.VisitAnyShape(s)
+	case *NilShape:
+		return g.VisitNilShape(s)
+	default:
+		return "unknown"
+	}
+}
+
+// VisitObjectShape returns an object literal type. Discriminator property is typed
+// with the discriminator value so that unions of such objects are discriminated unions.
+func (g *TypeScriptGenerator) VisitObjectShape(s *ObjectShape) string {
+	indent := g.indent
+	g.indent += "  "
+	defer func() { g.indent = indent }()
+
+	var b strings.Builder
+	b.WriteString("{\n")
+	var valueTypes []string
+	if s.Properties != nil {
+		for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+			prop := pair.Value
+			var typ string
+			if s.Discriminator != nil && *s.Discriminator == prop.Name {
+				typ = tsLiteral(discriminatorValueOf(s))
+			} else {
+				typ = g.Visit(*prop.Shape)
+			}
+			valueTypes = append(valueTypes, typ)
+			// Declared types are documented at the declaration.
+			if _, ok := g.declarations[(*prop.Shape).Base().Id]; !ok {
+				b.WriteString(tsComment((*prop.Shape).Base(), g.indent))
+			}
+			optional := ""
+			if !prop.Required {
+				optional = "?"
+			}
+			fmt.Fprintf(&b, "%s%s%s: %s;\n", g.indent, tsPropertyName(prop.Name), optional, typ)
+		}
+	}
+	if s.PatternProperties != nil && s.PatternProperties.Len() > 0 {
+		var patternTypes []string
+		for pair := s.PatternProperties.Oldest(); pair != nil; pair = pair.Next() {
+			patternTypes = append(patternTypes, g.Visit(*pair.Value.Shape))
+		}
+		// Index signature must be compatible with types of all properties.
+		types := tsUnion(append(patternTypes, valueTypes...))
+		if s.Properties != nil {
+			for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+				if !pair.Value.Required {
+					types = tsUnion([]string{types, "undefined"})
+					break
+				}
+			}
+		}
+		fmt.Fprintf(&b, "%s[key: string]: %s;\n", g.indent, types)
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+func (g *TypeScriptGenerator) VisitArrayShape(s *ArrayShape) string {
+	if s.Items == nil {
+		return "unknown[]"
+	}
+	item := g.Visit(*s.Items)
+	if len(tsUnionMembers(item)) > 1 {
+		item = "(" + item + ")"
+	}
+	return item + "[]"
+}
+
+func (g *TypeScriptGenerator) VisitStringShape(s *StringShape) string {
+	if s.Enum == nil {
+		return "string"
+	}
+	return tsEnum(s.Enum)
+}
+
+func (g *TypeScriptGenerator) VisitNumberShape(s *NumberShape) string {
+	if s.Enum == nil {
+		return "number"
+	}
+	return tsEnum(s.Enum)
+}
+
+func (g *TypeScriptGenerator) VisitIntegerShape(s *IntegerShape) string {
+	if s.Enum == nil {
+		return "number"
+	}
+	return tsEnum(s.Enum)
+}
+
+func (g *TypeScriptGenerator) VisitBooleanShape(s *BooleanShape) string {
+	if s.Enum == nil {
+		return "boolean"
+	}
+	return tsEnum(s.Enum)
+}
+
+func (g *TypeScriptGenerator) VisitFileShape(s *FileShape) string {
+	return "string"
+}
+
+func (g *TypeScriptGenerator) VisitUnionShape(s *UnionShape) string {
+	members := make([]string, len(s.AnyOf))
+	for i, item := range s.AnyOf {
+		members[i] = g.Visit(*item)
+	}
+	return tsUnion(members)
+}
+
+func (g *TypeScriptGenerator) VisitDateTimeShape(s *DateTimeShape) string {
+	return "string"
+}
+
+func (g *TypeScriptGenerator) VisitDateTimeOnlyShape(s *DateTimeOnlyShape) string {
+	return "string"
+}
+
+func (g *TypeScriptGenerator) VisitDateOnlyShape(s *DateOnlyShape) string {
+	return "string"
+}
+
+func (g *TypeScriptGenerator) VisitTimeOnlyShape(s *TimeOnlyShape) string {
+	return "string"
+}
+
+func (g *TypeScriptGenerator) VisitRecursiveShape(s *RecursiveShape) string {
+	if d, ok := g.declarations[(*s.Head).Base().Id]; ok {
+		return g.reference(d)
+	}
+	// NOTE: Recursion to anonymous types cannot be expressed without a declaration.
+	return "unknown"
+}
+
+func (g *TypeScriptGenerator) VisitJSONShape(s *JSONShape) string {
+	return "unknown"
+}
+
+func (g *TypeScriptGenerator) VisitXMLShape(s *XMLShape) string {
+	return "string"
+}
+
+func (g *TypeScriptGenerator) VisitAnyShape(s *AnyShape) string {
+	return "unknown"
+}
+
+func (g *TypeScriptGenerator) VisitNilShape(s *NilShape) string {
+	return "null"
+}
+
+func tsEnum(enum Nodes) string {
+	members := make([]string, len(enum))
+	for i, e := range enum {
+		members[i] = tsLiteral(e.Value)
+	}
+	return tsUnion(members)
+}
+
+func tsLiteral(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "unknown"
+	}
+	return string(b)
+}
+
+// tsUnion joins distinct types into a union. Types that are unions themselves are flattened,
+// so that their members are not repeated.
+func tsUnion(types []string) string {
+	seen := make(map[string]struct{}, len(types))
+	result := make([]string, 0, len(types))
+	for _, t := range types {
+		for _, member := range tsUnionMembers(t) {
+			if _, ok := seen[member]; ok {
+				continue
+			}
+			seen[member] = struct{}{}
+			result = append(result, member)
+		}
+	}
+	return strings.Join(result, " | ")
+}
+
+// tsUnionMembers splits the type into members of its top-level union.
+// Separators inside object literals, parentheses, generics and string literals are skipped.
+func tsUnionMembers(t string) []string {
+	var members []string
+	depth, start := 0, 0
+	inString := false
+	for i := 0; i < len(t); i++ {
+		c := t[i]
+		switch {
+		case inString:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '{' || c == '(' || c == '[' || c == '<':
+			depth++
+		case c == '}' || c == ')' || c == ']' || c == '>':
+			depth--
+		case depth == 0 && strings.HasPrefix(t[i:], " | "):
+			members = append(members, t[start:i])
+			start = i + len(" | ")
+			i += len(" | ") - 1
+		}
+	}
+	return append(members, t[start:])
+}
+
+func tsPropertyName(name string) string {
+	if tsIdentifierRegexp.MatchString(name) {
+		return name
+	}
+	return tsLiteral(name)
+}
+
+func tsIdentifier(name string) string {
+	id := strings.Map(func(r rune) rune {
+		if r == '_' || r == '$' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, name)
+	if !tsIdentifierRegexp.MatchString(id) {
+		id = "_" + id
+	}
+	return id
+}
+
+// tsComment returns a JSDoc comment with display name and description of the shape.
+func tsComment(base *BaseShape, indent string) string {
+	var lines []string
+	if base.DisplayName != nil {
+		lines = append(lines, strings.Split(*base.DisplayName, "\n")...)
+	}
+	if base.Description != nil {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, strings.Split(strings.TrimSpace(*base.Description), "\n")...)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(indent + "/**\n")
+	for _, line := range lines {
+		line = strings.ReplaceAll(strings.TrimRight(line, " \t"), "*/", "*\\/")
+		if line == "" {
+			b.WriteString(indent + " *\n")
+		} else {
+			b.WriteString(indent + " * " + line + "\n")
+		}
+	}
+	b.WriteString(indent + " */\n")
+	return b.String()
+}
@@ -0,0 +1,216 @@
+package raml
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_tsUnion(t *testing.T) {
+	tests := []struct {
+		name  string
+		types []string
+		want  string
+	}{
+		{name: "distinct types", types: []string{"string", "number"}, want: "string | number"},
+		{name: "repeated types", types: []string{"string", "string", "number"}, want: "string | number"},
+		{name: "nested union", types: []string{"string", "string | number", "undefined"}, want: "string | number | undefined"},
+		{
+			name:  "separators in literals",
+			types: []string{`"a | b"`, "{ x: string | number }", "(string | boolean)[]", `"a | b"`},
+			want:  `"a | b" | { x: string | number } | (string | boolean)[]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tsUnion(tt.types))
+		})
+	}
+}
+
+func TestTypeScriptGenerator_IndexSignature(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0 Library
+types:
+  Labels:
+    type: object
+    properties:
+      name: string
+      size?: string | number
+      //: string
+`)
+	modules, err := NewTypeScriptGenerator().GenerateLibrary(rml.EntryPoint().(*Library))
+	require.NoError(t, err)
+	require.Equal(t, 1, modules.Len())
+	require.Contains(t, string(modules.Oldest().Value), "[key: string]: string | number | undefined;")
+}
+
+func TestTypeScriptGenerator_GenerateLibrary(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name: "arrays of unions",
+			content: `
+  Values:
+    type: object
+    properties:
+      names:
+        type: array
+        items: string | nil
+      nullable:
+        type: array
+        items: object | nil
+      plain: string[]
+`,
+			want: `
+export interface Values {
+  names: (string | null)[];
+  nullable: ({
+  } | null)[];
+  plain: string[];
+}
+`,
+		},
+		{
+			name: "enums",
+			content: `
+  Color:
+    enum: [red, "say \"hi\""]
+  Level:
+    type: integer
+    enum: [1, 2]
+`,
+			want: `
+export type Color = "red" | "say \"hi\"";
+
+export type Level = 1 | 2;
+`,
+		},
+		{
+			name: "discriminated union",
+			content: `
+  Cat:
+    type: object
+    discriminator: kind
+    properties:
+      kind: string
+      lives: integer
+  Dog:
+    type: object
+    discriminator: kind
+    discriminatorValue: doggo
+    properties:
+      kind: string
+  Pet: Cat | Dog
+`,
+			want: `
+export interface Cat {
+  kind: "Cat";
+  lives: number;
+}
+
+export interface Dog {
+  kind: "doggo";
+}
+
+export type Pet = Cat | Dog;
+`,
+		},
+		{
+			name: "doc comments",
+			content: `
+  Point:
+    displayName: Point
+    description: |
+      A point.
+      Ends with */ here.
+    type: object
+    properties:
+      x:
+        description: Abscissa.
+        type: number
+`,
+			want: `
+/**
+ * Point
+ *
+ * A point.
+ * Ends with *\/ here.
+ */
+export interface Point {
+  /**
+   * Abscissa.
+   */
+  x: number;
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rml := parseLibrary(t, "#%RAML 1.0 Library\ntypes:"+tt.content)
+			modules, err := NewTypeScriptGenerator().GenerateLibrary(rml.EntryPoint().(*Library))
+			require.NoError(t, err)
+			require.Equal(t, 1, modules.Len())
+			require.Equal(t, "// Code generated by go-raml. DO NOT EDIT.\n"+tt.want, string(modules.Oldest().Value))
+		})
+	}
+}
+
+func TestTypeScriptGenerator_Uses(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"library.raml": `#%RAML 1.0 Library
+usage: Orders
+uses:
+  common: common/types.raml
+types:
+  Order:
+    type: object
+    properties:
+      id: common.Id
+      tags: common.Tag[]
+`,
+		"common/types.raml": `#%RAML 1.0 Library
+types:
+  Id: string
+  Tag:
+    type: object
+    properties:
+      name: string
+`,
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o600))
+	}
+	rml, err := ParseFromPath(filepath.Join(dir, "library.raml"), OptWithUnwrap())
+	require.NoError(t, err)
+	modules, err := NewTypeScriptGenerator().GenerateLibrary(rml.EntryPoint().(*Library))
+	require.NoError(t, err)
+
+	var paths []string
+	for pair := modules.Oldest(); pair != nil; pair = pair.Next() {
+		paths = append(paths, pair.Key)
+	}
+	require.Equal(t, []string{"library.d.ts", "common/types.d.ts"}, paths)
+	main, _ := modules.Get("library.d.ts")
+	require.Equal(t, `// Code generated by go-raml. DO NOT EDIT.
+// Orders
+
+import type * as common from "./common/types";
+
+export interface Order {
+  id: common.Id;
+  tags: common.Tag[];
+}
+`, string(main))
+	common, _ := modules.Get("common/types.d.ts")
+	require.Contains(t, string(common), "export type Id = string;\n")
+	require.Contains(t, string(common), "export interface Tag {\n  name: string;\n}\n")
+}
@@ -95,6 +95,24 @@ func (r *RAML) UnwrapShapes() error {
 			r.PutShapePtr(ptr)
 		}
 	}
+	// Parameters and bodies of the API are unwrapped in place.
+	if api, ok := r.entryPoint.(*API); ok {
+		for _, shape := range api.shapes() {
+			position := (*shape).Base().Position
+			us, err := r.UnwrapShape(shape, make([]Shape, 0))
+			if err != nil {
+				se := stacktrace.NewWrapped("unwrap shape", err, api.Location, stacktrace.WithType(stacktrace.TypeUnwrapping), stacktrace.WithPosition(&position))
+				if st == nil {
+					st = se
+				} else {
+					st = st.Append(se)
+				}
+				continue
+			}
+			*shape = us
+			r.PutShapePtr(shape)
+		}
+	}
 	// Links to definedBy must be updated after unwrapping.
 	for _, item := range r.domainExtensions {
 		db := *item.DefinedBy
@@ -149,6 +167,10 @@ func (r *RAML) inheritBase(sourceBase *BaseShape, targetBase *BaseShape) {
 		}
 	}
 	targetBase.CustomDomainProperties = customDomainProperties
+
+	if targetBase.XML == nil {
+		targetBase.XML = sourceBase.XML
+	}
 	// TODO: CustomShapeFacetDefinitions are not inheritable in context of unwrapper. But maybe they can be inheritable in other context?
 }
 
@@ -277,6 +299,10 @@ func (r *RAML) UnwrapShape(s *Shape, history []Shape) (Shape, error) {
 			return nil, stacktrace.NewWrapped("alias unwrap", err, base.Location, stacktrace.WithPosition(&base.Position), stacktrace.WithType(stacktrace.TypeUnwrapping))
 		}
 		// Alias simply points to another shape, so we just change the name and return it as is.
+		// The name of the aliased type is kept as the default discriminator value.
+		if obj, ok := us.(*ObjectShape); ok && obj.typeName == "" {
+			obj.typeName = obj.Name
+		}
 		us.Base().Name = base.Name
 		return us, nil
 	} else if base.Link != nil {
@@ -111,6 +111,46 @@ func (r *RAML) ValidateShapes() error {
 			}
 		}
 	}
+	// Parameters and bodies of the API are not declared in fragments.
+	if api, ok := r.entryPoint.(*API); ok {
+		for _, shape := range api.shapes() {
+			s := *shape
+			if !s.Base().unwrapped {
+				us, err := r.UnwrapShape(shape, make([]Shape, 0))
+				if err != nil {
+					se := stacktrace.NewWrapped("unwrap shape", err, s.Base().Location, stacktrace.WithPosition(&s.Base().Position),
+						stacktrace.WithType(stacktrace.TypeValidating))
+					if st == nil {
+						st = se
+					} else {
+						st = st.Append(se)
+					}
+					continue
+				}
+				s = us
+			}
+			if err := s.Check(); err != nil {
+				se := stacktrace.NewWrapped("check api shape", err, s.Base().Location, stacktrace.WithPosition(&s.Base().Position),
+					stacktrace.WithType(stacktrace.TypeValidating))
+				if st == nil {
+					st = se
+				} else {
+					st = st.Append(se)
+				}
+				continue
+			}
+			if err := r.validateShapeCommons(s); err != nil {
+				se := stacktrace.NewWrapped("validate shape commons", err, s.Base().Location, stacktrace.WithPosition(&s.Base().Position),
+					stacktrace.WithType(stacktrace.TypeValidating))
+				if st == nil {
+					st = se
+				} else {
+					st = st.Append(se)
+				}
+				continue
+			}
+		}
+	}
 	for _, item := range r.domainExtensions {
 		db := *item.DefinedBy
 		if !db.Base().unwrapped {
@@ -148,6 +188,9 @@ func (r *RAML) validateShapeCommons(s Shape) error {
 	if err := r.validateShapeFacets(s); err != nil {
 		return err
 	}
+	if err := validateXMLFacet(s); err != nil {
+		return err
+	}
 	if err := r.validateExamples(s); err != nil {
 		return err
 	}
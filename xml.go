@@ -0,0 +1,432 @@
+package raml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/acronis/go-raml/stacktrace"
+)
+
+// XML represents the xml facet that configures XML serialization of the shape.
+//
+// https://github.com/raml-org/raml-spec/blob/master/versions/raml-10/raml-10.md#xml-serialization-of-type-instances
+type XML struct {
+	// Attribute serializes the property as an attribute instead of an element.
+	Attribute *bool
+	// Wrapped wraps array items into an element named after the property.
+	Wrapped *bool
+	// Name overrides the element or attribute name.
+	Name *string
+	// Namespace is the XML namespace of the element or attribute.
+	Namespace *string
+	// Prefix is the prefix of the namespace used in serialized documents.
+	Prefix *string
+
+	Location string
+	stacktrace.Position
+}
+
+// IsAttribute returns true if the shape is serialized as an attribute.
+func (x *XML) IsAttribute() bool {
+	return x != nil && x.Attribute != nil && *x.Attribute
+}
+
+// IsWrapped returns true if array items are wrapped into an element.
+func (x *XML) IsWrapped() bool {
+	return x != nil && x.Wrapped != nil && *x.Wrapped
+}
+
+// NameOr returns the configured name or the given default name.
+func (x *XML) NameOr(name string) string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return name
+}
+
+func (r *RAML) makeXML(node *yaml.Node, location string) (*XML, error) {
+	if node.Kind != yaml.MappingNode {
+		return nil, stacktrace.New("xml must be map", location, stacktrace.WithNodePosition(node))
+	}
+	x := &XML{Location: location, Position: stacktrace.Position{Line: node.Line, Column: node.Column}}
+	for i := 0; i != len(node.Content); i += 2 {
+		keyNode := node.Content[i]
+		valueNode := node.Content[i+1]
+		var err error
+		switch keyNode.Value {
+		case "attribute":
+			err = valueNode.Decode(&x.Attribute)
+		case "wrapped":
+			err = valueNode.Decode(&x.Wrapped)
+		case "name":
+			err = valueNode.Decode(&x.Name)
+		case "namespace":
+			err = valueNode.Decode(&x.Namespace)
+		case "prefix":
+			err = valueNode.Decode(&x.Prefix)
+		default:
+			return nil, stacktrace.New("unknown xml facet", location, stacktrace.WithNodePosition(keyNode),
+				stacktrace.WithInfo("facet", keyNode.Value))
+		}
+		if err != nil {
+			return nil, stacktrace.NewWrapped("decode xml facet", err, location, stacktrace.WithNodePosition(valueNode),
+				stacktrace.WithInfo("facet", keyNode.Value))
+		}
+	}
+	return x, nil
+}
+
+// validateXMLFacet checks that only scalar shapes are serialized as attributes.
+func validateXMLFacet(s Shape) error {
+	base := s.Base()
+	if !base.XML.IsAttribute() {
+		return nil
+	}
+	switch s.(type) {
+	case *ObjectShape, *ArrayShape, *UnionShape, *JSONShape, *XMLShape:
+		return stacktrace.New("xml attribute is allowed for scalar types only", base.XML.Location,
+			stacktrace.WithPosition(&base.XML.Position))
+	}
+	return nil
+}
+
+// xmlElement is a node of decoded XML document.
+type xmlElement struct {
+	Name     xml.Name
+	Attrs    []xml.Attr
+	Children []*xmlElement
+	Text     string
+	Path     string
+}
+
+func parseXMLDocument(r io.Reader) (*xmlElement, error) {
+	d := xml.NewDecoder(r)
+	var stack []*xmlElement
+	var root *xmlElement
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decode xml: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			e := &xmlElement{Name: t.Name, Attrs: t.Attr}
+			if len(stack) == 0 {
+				if root != nil {
+					return nil, fmt.Errorf("xml document must have a single root element")
+				}
+				root = e
+				e.Path = "/" + t.Name.Local
+			} else {
+				parent := stack[len(stack)-1]
+				n := 1
+				for _, c := range parent.Children {
+					if c.Name == t.Name {
+						n++
+					}
+				}
+				e.Path = fmt.Sprintf("%s/%s[%d]", parent.Path, t.Name.Local, n)
+				parent.Children = append(parent.Children, e)
+			}
+			stack = append(stack, e)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].Text += string(t)
+			}
+		}
+	}
+	if root == nil {
+		return nil, fmt.Errorf("xml document has no root element")
+	}
+	return root, nil
+}
+
+// DecodeXML decodes the XML document into a value according to xml facets of the unwrapped shape.
+// The value has the same representation as JSON values, so it can be validated by the shape.
+func DecodeXML(s Shape, r io.Reader) (interface{}, error) {
+	return decodeXMLDocument(s, r, false)
+}
+
+// ValidateXML decodes the XML document according to xml facets of the unwrapped shape and validates it.
+// Errors are reported with paths of elements, e.g. "/Pet/tags[1]".
+func ValidateXML(s Shape, r io.Reader) error {
+	_, err := decodeXMLDocument(s, r, true)
+	return err
+}
+
+func decodeXMLDocument(s Shape, r io.Reader, validate bool) (interface{}, error) {
+	root, err := parseXMLDocument(r)
+	if err != nil {
+		return nil, err
+	}
+	if x := s.Base().XML; x != nil && x.Name != nil && !xmlNameMatches(x, *x.Name, root.Name, false) {
+		return nil, fmt.Errorf("%s: unexpected root element, expected %s", root.Path, *x.Name)
+	}
+	d := xmlDecoder{validate: validate}
+	if arr, ok := s.(*ArrayShape); ok {
+		return d.decodeItems(arr, root.Children, root.Path)
+	}
+	return d.decode(s, root)
+}
+
+type xmlDecoder struct {
+	validate bool
+}
+
+// decode decodes the element as the shape. Children are validated before parents,
+// so validation errors are reported at the deepest element.
+func (d xmlDecoder) decode(s Shape, e *xmlElement) (interface{}, error) {
+	var v interface{}
+	var err error
+	switch s := s.(type) {
+	case *ObjectShape:
+		v, err = d.decodeObject(s, e)
+	case *ArrayShape:
+		// Arrays without property context are always wrapped.
+		v, err = d.decodeItems(s, e.Children, e.Path)
+	case *UnionShape:
+		return d.decodeUnion(s, e)
+	case *RecursiveShape:
+		return d.decode(*s.Head, e)
+	default:
+		v, err = decodeXMLScalar(s, e.Text)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Path, err)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return v, d.check(s, v, e.Path)
+}
+
+func (d xmlDecoder) check(s Shape, v interface{}, path string) error {
+	if !d.validate {
+		return nil
+	}
+	if err := s.Validate(v, "$"); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+func (d xmlDecoder) decodeUnion(s *UnionShape, e *xmlElement) (interface{}, error) {
+	var errs []string
+	for _, item := range s.AnyOf {
+		v, err := xmlDecoder{validate: true}.decode(*item, e)
+		if err == nil {
+			return v, nil
+		}
+		errs = append(errs, err.Error())
+	}
+	if !d.validate && len(s.AnyOf) > 0 {
+		return d.decode(*s.AnyOf[0], e)
+	}
+	return nil, fmt.Errorf("%s: value does not match any type of union: %s", e.Path, strings.Join(errs, "; "))
+}
+
+func (d xmlDecoder) decodeObject(s *ObjectShape, e *xmlElement) (interface{}, error) {
+	result := make(map[string]interface{})
+	consumed := make(map[*xmlElement]struct{})
+	consumedAttrs := make(map[int]struct{})
+	if s.Properties != nil {
+		for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+			prop := pair.Value
+			ps := *prop.Shape
+			x := ps.Base().XML
+			name := x.NameOr(prop.Name)
+			if x.IsAttribute() {
+				for i, a := range e.Attrs {
+					if xmlNameMatches(x, name, a.Name, true) {
+						consumedAttrs[i] = struct{}{}
+						v, err := decodeXMLScalar(ps, a.Value)
+						if err != nil {
+							return nil, fmt.Errorf("%s/@%s: %w", e.Path, name, err)
+						}
+						if err := d.check(ps, v, e.Path+"/@"+name); err != nil {
+							return nil, err
+						}
+						result[prop.Name] = v
+					}
+				}
+				if _, ok := result[prop.Name]; !ok && d.validate && prop.Required {
+					return nil, fmt.Errorf("%s: required attribute %s is missing", e.Path, name)
+				}
+				continue
+			}
+			var matched []*xmlElement
+			for _, c := range e.Children {
+				// NOTE: Unwrapped properties share xml facet with the referenced type,
+				// so the property name is accepted as well as the name from the facet.
+				if xmlNameMatches(x, name, c.Name, false) || xmlNameMatches(x, prop.Name, c.Name, false) {
+					matched = append(matched, c)
+				}
+			}
+			if len(matched) == 0 {
+				if d.validate && prop.Required {
+					return nil, fmt.Errorf("%s: required element %s is missing", e.Path, name)
+				}
+				continue
+			}
+			if arr, ok := ps.(*ArrayShape); ok {
+				var items []*xmlElement
+				path := e.Path + "/" + name
+				if x.IsWrapped() {
+					if len(matched) > 1 {
+						return nil, fmt.Errorf("%s: wrapper element must occur once", matched[1].Path)
+					}
+					items, path = matched[0].Children, matched[0].Path
+				} else {
+					items = matched
+				}
+				for _, m := range matched {
+					consumed[m] = struct{}{}
+				}
+				v, err := d.decodeItems(arr, items, path)
+				if err != nil {
+					return nil, err
+				}
+				result[prop.Name] = v
+				continue
+			}
+			if len(matched) > 1 {
+				return nil, fmt.Errorf("%s: element must occur once", matched[1].Path)
+			}
+			consumed[matched[0]] = struct{}{}
+			v, err := d.decode(ps, matched[0])
+			if err != nil {
+				return nil, err
+			}
+			result[prop.Name] = v
+		}
+	}
+	// Attributes cannot be matched by pattern properties, so undeclared ones are only checked against
+	// additionalProperties. Namespace declarations and XML Schema instance attributes are always allowed.
+	if d.validate && s.AdditionalProperties != nil && !*s.AdditionalProperties {
+		for i, a := range e.Attrs {
+			if _, ok := consumedAttrs[i]; ok || isXMLReservedAttr(a.Name) {
+				continue
+			}
+			return nil, fmt.Errorf("%s/@%s: unexpected attribute", e.Path, a.Name.Local)
+		}
+	}
+	// Unknown elements are kept as text so that pattern and additional properties are validated.
+	for _, c := range e.Children {
+		if _, ok := consumed[c]; ok {
+			continue
+		}
+		var v interface{} = strings.TrimSpace(c.Text)
+		if s.PatternProperties != nil {
+			for pair := s.PatternProperties.Oldest(); pair != nil; pair = pair.Next() {
+				matched, err := matchRegexp(pair.Value.Pattern, c.Name.Local)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", c.Path, err)
+				}
+				if matched {
+					dv, err := d.decode(*pair.Value.Shape, c)
+					if err != nil {
+						return nil, err
+					}
+					v = dv
+					break
+				}
+			}
+		}
+		if prev, ok := result[c.Name.Local]; ok {
+			if list, ok := prev.([]interface{}); ok {
+				v = append(list, v)
+			} else {
+				v = []interface{}{prev, v}
+			}
+		}
+		result[c.Name.Local] = v
+	}
+	return result, nil
+}
+
+func (d xmlDecoder) decodeItems(s *ArrayShape, items []*xmlElement, path string) (interface{}, error) {
+	result := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		if s.Items == nil {
+			result = append(result, strings.TrimSpace(item.Text))
+			continue
+		}
+		is := *s.Items
+		if x := is.Base().XML; x != nil && x.Name != nil && !xmlNameMatches(x, *x.Name, item.Name, false) {
+			return nil, fmt.Errorf("%s: unexpected element, expected %s", item.Path, *x.Name)
+		}
+		v, err := d.decode(is, item)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, v)
+	}
+	return result, d.check(s, result, path)
+}
+
+// isXMLReservedAttr reports whether the attribute is a namespace declaration or belongs to XML Schema instance.
+func isXMLReservedAttr(n xml.Name) bool {
+	return n.Space == "xmlns" || n.Local == "xmlns" && n.Space == "" || n.Space == xsiNamespace
+}
+
+func xmlNameMatches(x *XML, name string, n xml.Name, attribute bool) bool {
+	if n.Local != name {
+		return false
+	}
+	if x != nil && x.Namespace != nil {
+		return n.Space == *x.Namespace
+	}
+	// Unqualified attributes have no namespace, elements may inherit the default one.
+	return !attribute || n.Space == ""
+}
+
+// decodeXMLScalar converts the text to the value type of the scalar shape.
+func decodeXMLScalar(s Shape, text string) (interface{}, error) {
+	switch s.(type) {
+	case *IntegerShape:
+		text = strings.TrimSpace(text)
+		if i, err := strconv.ParseInt(text, 10, 0); err == nil {
+			return int(i), nil
+		}
+		if u, err := strconv.ParseUint(text, 10, 0); err == nil {
+			return uint(u), nil
+		}
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %q", text)
+		}
+		return f, nil
+	case *NumberShape:
+		text = strings.TrimSpace(text)
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", text)
+		}
+		return f, nil
+	case *BooleanShape:
+		text = strings.TrimSpace(text)
+		b, err := strconv.ParseBool(text)
+		if err != nil || text != "true" && text != "false" {
+			return nil, fmt.Errorf("invalid boolean %q", text)
+		}
+		return b, nil
+	case *NilShape:
+		if strings.TrimSpace(text) != "" {
+			return nil, fmt.Errorf("element must be empty")
+		}
+		return nil, nil
+	case *DateTimeShape, *DateTimeOnlyShape, *DateOnlyShape, *TimeOnlyShape:
+		return strings.TrimSpace(text), nil
+	}
+	return text, nil
+}
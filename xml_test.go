@@ -0,0 +1,105 @@
+package raml
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateXML(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0 Library
+types:
+  Pet:
+    type: object
+    additionalProperties: false
+    xml:
+      name: pet
+    properties:
+      id:
+        type: integer
+        xml:
+          attribute: true
+      name: string
+      nick?: string
+      tags:
+        type: string[]
+        xml:
+          wrapped: true
+  Open:
+    type: object
+    properties:
+      id:
+        type: integer
+        xml:
+          attribute: true
+`)
+	tests := []struct {
+		name     string
+		typeName string
+		doc      string
+		wantErr  string
+	}{
+		{
+			name:     "valid document",
+			typeName: "Pet",
+			doc:      `<pet id="1"><name>Rex</name><tags><tag>a</tag><tag>b</tag></tags></pet>`,
+		},
+		{
+			name:     "namespace declarations are allowed",
+			typeName: "Pet",
+			doc:      `<pet xmlns="urn:pets" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" id="1"><name>Rex</name><tags/></pet>`,
+		},
+		{
+			name:     "missing required element",
+			typeName: "Pet",
+			doc:      `<pet id="1"><tags/></pet>`,
+			wantErr:  "/pet: required element name is missing",
+		},
+		{
+			name:     "missing required attribute",
+			typeName: "Pet",
+			doc:      `<pet><name>Rex</name><tags/></pet>`,
+			wantErr:  "/pet: required attribute id is missing",
+		},
+		{
+			name:     "missing required wrapped array",
+			typeName: "Pet",
+			doc:      `<pet id="1"><name>Rex</name></pet>`,
+			wantErr:  "/pet: required element tags is missing",
+		},
+		{
+			name:     "undeclared attribute",
+			typeName: "Pet",
+			doc:      `<pet id="1" color="red"><name>Rex</name><tags/></pet>`,
+			wantErr:  "/pet/@color: unexpected attribute",
+		},
+		{
+			name:     "undeclared attribute of open object",
+			typeName: "Open",
+			doc:      `<Open id="1" color="red"/>`,
+		},
+		{
+			name:     "repeated wrapper element",
+			typeName: "Pet",
+			doc:      `<pet id="1"><name>Rex</name><tags><tag>a</tag></tags><tags><tag>b</tag></tags></pet>`,
+			wantErr:  "/pet/tags[2]: wrapper element must occur once",
+		},
+		{
+			name:     "invalid attribute value",
+			typeName: "Pet",
+			doc:      `<pet id="x"><name>Rex</name><tags/></pet>`,
+			wantErr:  `/pet/@id: invalid integer "x"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateXML(libraryType(t, rml, tt.typeName), strings.NewReader(tt.doc))
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
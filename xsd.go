@@ -0,0 +1,1020 @@
+package raml
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// XSDNamespace is the namespace of XML Schema definitions.
+const XSDNamespace = "http://www.w3.org/2001/XMLSchema"
+
+const xsiNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+
+// XSDSchema is a parsed XML Schema.
+//
+// Only a subset of XML Schema 1.0 that is required to validate documents is supported:
+// global and local elements, element references, named and anonymous simple and complex types,
+// sequence, choice, all and any particles, attributes, simple and complex content extensions,
+// restrictions with enumeration, pattern, length and range facets, lists, unions and built-in types.
+// Other constructs are ignored.
+type XSDSchema struct {
+	TargetNamespace string
+	Elements        map[string]*xsdElement
+	ComplexTypes    map[string]*xsdComplexType
+	SimpleTypes     map[string]*xsdSimpleType
+}
+
+type xsdQName struct {
+	Space string
+	Local string
+}
+
+type xsdElement struct {
+	Name      string
+	Type      xsdQName
+	Ref       xsdQName
+	MinOccurs int
+	// MaxOccurs is -1 for unbounded.
+	MaxOccurs   int
+	Nillable    bool
+	ComplexType *xsdComplexType
+	SimpleType  *xsdSimpleType
+}
+
+type xsdAttribute struct {
+	Name       string
+	Type       xsdQName
+	Required   bool
+	SimpleType *xsdSimpleType
+}
+
+type xsdParticleKind int
+
+const (
+	xsdParticleElement xsdParticleKind = iota
+	xsdParticleSequence
+	xsdParticleChoice
+	xsdParticleAll
+	xsdParticleAny
+)
+
+type xsdParticle struct {
+	Kind      xsdParticleKind
+	Element   *xsdElement
+	Particles []*xsdParticle
+	MinOccurs int
+	MaxOccurs int
+}
+
+type xsdComplexType struct {
+	Name         string
+	Mixed        bool
+	Particle     *xsdParticle
+	Attributes   []*xsdAttribute
+	AnyAttribute bool
+	// Base is the extended type of simple or complex content.
+	Base          xsdQName
+	SimpleContent bool
+}
+
+type xsdSimpleType struct {
+	Name         string
+	Base         xsdQName
+	BaseType     *xsdSimpleType
+	Enumeration  []string
+	Patterns     []*regexp.Regexp
+	Length       *int
+	MinLength    *int
+	MaxLength    *int
+	MinInclusive *float64
+	MaxInclusive *float64
+	MinExclusive *float64
+	MaxExclusive *float64
+	// ItemType is set for lists.
+	ItemType    *xsdQName
+	ItemSimple  *xsdSimpleType
+	MemberTypes []xsdQName
+	Members     []*xsdSimpleType
+}
+
+// ParseXSD parses the XML Schema document.
+func ParseXSD(data []byte) (*XSDSchema, error) {
+	d := xml.NewDecoder(strings.NewReader(string(data)))
+	p := &xsdParser{
+		schema: &XSDSchema{
+			Elements:     make(map[string]*xsdElement),
+			ComplexTypes: make(map[string]*xsdComplexType),
+			SimpleTypes:  make(map[string]*xsdSimpleType),
+		},
+	}
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, fmt.Errorf("decode xsd: %w", err)
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			if start.Name.Space != XSDNamespace || start.Name.Local != "schema" {
+				return nil, fmt.Errorf("root element must be xs:schema, got %s", start.Name.Local)
+			}
+			if err := p.parseSchema(d, start); err != nil {
+				return nil, err
+			}
+			return p.schema, nil
+		}
+	}
+}
+
+type xsdParser struct {
+	schema *XSDSchema
+	// prefixes maps namespace prefixes to namespaces, used to resolve type references.
+	prefixes map[string]string
+}
+
+func xsdAttr(e xml.StartElement, name string) (string, bool) {
+	for _, a := range e.Attr {
+		if a.Name.Space == "" && a.Name.Local == name {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+func (p *xsdParser) qname(v string) xsdQName {
+	if v == "" {
+		return xsdQName{}
+	}
+	prefix, local, ok := strings.Cut(v, ":")
+	if !ok {
+		return xsdQName{Space: p.prefixes[""], Local: v}
+	}
+	return xsdQName{Space: p.prefixes[prefix], Local: local}
+}
+
+func xsdOccurs(e xml.StartElement) (int, int, error) {
+	minOccurs, maxOccurs := 1, 1
+	if v, ok := xsdAttr(e, "minOccurs"); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid minOccurs %q", v)
+		}
+		minOccurs = n
+	}
+	if v, ok := xsdAttr(e, "maxOccurs"); ok {
+		if v == "unbounded" {
+			maxOccurs = -1
+		} else {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return 0, 0, fmt.Errorf("invalid maxOccurs %q", v)
+			}
+			maxOccurs = n
+		}
+	}
+	return minOccurs, maxOccurs, nil
+}
+
+// xsdChildren calls fn for each child element of XML Schema namespace and skips others.
+func xsdChildren(d *xml.Decoder, fn func(start xml.StartElement) error) error {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return fmt.Errorf("decode xsd: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Space != XSDNamespace {
+				if err := d.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := fn(t); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+func (p *xsdParser) parseSchema(d *xml.Decoder, start xml.StartElement) error {
+	p.prefixes = make(map[string]string)
+	for _, a := range start.Attr {
+		if a.Name.Space == "xmlns" {
+			p.prefixes[a.Name.Local] = a.Value
+		} else if a.Name.Space == "" && a.Name.Local == "xmlns" {
+			p.prefixes[""] = a.Value
+		}
+	}
+	p.schema.TargetNamespace, _ = xsdAttr(start, "targetNamespace")
+	return xsdChildren(d, func(child xml.StartElement) error {
+		switch child.Name.Local {
+		case "element":
+			e, err := p.parseElement(d, child)
+			if err != nil {
+				return err
+			}
+			p.schema.Elements[e.Name] = e
+		case "complexType":
+			t, err := p.parseComplexType(d, child)
+			if err != nil {
+				return err
+			}
+			p.schema.ComplexTypes[t.Name] = t
+		case "simpleType":
+			t, err := p.parseSimpleType(d, child)
+			if err != nil {
+				return err
+			}
+			p.schema.SimpleTypes[t.Name] = t
+		default:
+			return d.Skip()
+		}
+		return nil
+	})
+}
+
+func (p *xsdParser) parseElement(d *xml.Decoder, start xml.StartElement) (*xsdElement, error) {
+	e := &xsdElement{}
+	e.Name, _ = xsdAttr(start, "name")
+	if v, ok := xsdAttr(start, "type"); ok {
+		e.Type = p.qname(v)
+	}
+	if v, ok := xsdAttr(start, "ref"); ok {
+		e.Ref = p.qname(v)
+		e.Name = e.Ref.Local
+	}
+	if v, ok := xsdAttr(start, "nillable"); ok {
+		e.Nillable = v == "true" || v == "1"
+	}
+	var err error
+	if e.MinOccurs, e.MaxOccurs, err = xsdOccurs(start); err != nil {
+		return nil, fmt.Errorf("element %s: %w", e.Name, err)
+	}
+	err = xsdChildren(d, func(child xml.StartElement) error {
+		var err error
+		switch child.Name.Local {
+		case "complexType":
+			e.ComplexType, err = p.parseComplexType(d, child)
+		case "simpleType":
+			e.SimpleType, err = p.parseSimpleType(d, child)
+		default:
+			err = d.Skip()
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("element %s: %w", e.Name, err)
+	}
+	return e, nil
+}
+
+func (p *xsdParser) parseComplexType(d *xml.Decoder, start xml.StartElement) (*xsdComplexType, error) {
+	t := &xsdComplexType{}
+	t.Name, _ = xsdAttr(start, "name")
+	if v, ok := xsdAttr(start, "mixed"); ok {
+		t.Mixed = v == "true" || v == "1"
+	}
+	err := xsdChildren(d, func(child xml.StartElement) error {
+		return p.parseComplexTypeContent(d, child, t)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("complex type %s: %w", t.Name, err)
+	}
+	return t, nil
+}
+
+func (p *xsdParser) parseComplexTypeContent(d *xml.Decoder, child xml.StartElement, t *xsdComplexType) error {
+	switch child.Name.Local {
+	case "sequence", "choice", "all":
+		particle, err := p.parseGroup(d, child)
+		if err != nil {
+			return err
+		}
+		t.Particle = particle
+	case "attribute":
+		a, err := p.parseAttribute(d, child)
+		if err != nil {
+			return err
+		}
+		t.Attributes = append(t.Attributes, a)
+	case "anyAttribute":
+		t.AnyAttribute = true
+		return d.Skip()
+	case "simpleContent", "complexContent":
+		t.SimpleContent = child.Name.Local == "simpleContent"
+		return xsdChildren(d, func(ext xml.StartElement) error {
+			if ext.Name.Local != "extension" && ext.Name.Local != "restriction" {
+				return d.Skip()
+			}
+			if v, ok := xsdAttr(ext, "base"); ok {
+				t.Base = p.qname(v)
+			}
+			return xsdChildren(d, func(c xml.StartElement) error {
+				return p.parseComplexTypeContent(d, c, t)
+			})
+		})
+	default:
+		return d.Skip()
+	}
+	return nil
+}
+
+func (p *xsdParser) parseGroup(d *xml.Decoder, start xml.StartElement) (*xsdParticle, error) {
+	particle := &xsdParticle{}
+	switch start.Name.Local {
+	case "sequence":
+		particle.Kind = xsdParticleSequence
+	case "choice":
+		particle.Kind = xsdParticleChoice
+	case "all":
+		particle.Kind = xsdParticleAll
+	}
+	var err error
+	if particle.MinOccurs, particle.MaxOccurs, err = xsdOccurs(start); err != nil {
+		return nil, err
+	}
+	err = xsdChildren(d, func(child xml.StartElement) error {
+		switch child.Name.Local {
+		case "element":
+			e, err := p.parseElement(d, child)
+			if err != nil {
+				return err
+			}
+			particle.Particles = append(particle.Particles, &xsdParticle{
+				Kind: xsdParticleElement, Element: e, MinOccurs: e.MinOccurs, MaxOccurs: e.MaxOccurs,
+			})
+		case "sequence", "choice", "all":
+			g, err := p.parseGroup(d, child)
+			if err != nil {
+				return err
+			}
+			particle.Particles = append(particle.Particles, g)
+		case "any":
+			minOccurs, maxOccurs, err := xsdOccurs(child)
+			if err != nil {
+				return err
+			}
+			particle.Particles = append(particle.Particles, &xsdParticle{Kind: xsdParticleAny, MinOccurs: minOccurs, MaxOccurs: maxOccurs})
+			return d.Skip()
+		default:
+			return d.Skip()
+		}
+		return nil
+	})
+	return particle, err
+}
+
+func (p *xsdParser) parseAttribute(d *xml.Decoder, start xml.StartElement) (*xsdAttribute, error) {
+	a := &xsdAttribute{}
+	a.Name, _ = xsdAttr(start, "name")
+	if v, ok := xsdAttr(start, "ref"); ok {
+		a.Name = p.qname(v).Local
+	}
+	if v, ok := xsdAttr(start, "type"); ok {
+		a.Type = p.qname(v)
+	}
+	if v, ok := xsdAttr(start, "use"); ok {
+		a.Required = v == "required"
+	}
+	err := xsdChildren(d, func(child xml.StartElement) error {
+		if child.Name.Local != "simpleType" {
+			return d.Skip()
+		}
+		var err error
+		a.SimpleType, err = p.parseSimpleType(d, child)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("attribute %s: %w", a.Name, err)
+	}
+	return a, nil
+}
+
+func (p *xsdParser) parseSimpleType(d *xml.Decoder, start xml.StartElement) (*xsdSimpleType, error) {
+	t := &xsdSimpleType{}
+	t.Name, _ = xsdAttr(start, "name")
+	err := xsdChildren(d, func(child xml.StartElement) error {
+		switch child.Name.Local {
+		case "restriction":
+			if v, ok := xsdAttr(child, "base"); ok {
+				t.Base = p.qname(v)
+			}
+			return xsdChildren(d, func(facet xml.StartElement) error {
+				return p.parseFacet(d, facet, t)
+			})
+		case "list":
+			if v, ok := xsdAttr(child, "itemType"); ok {
+				q := p.qname(v)
+				t.ItemType = &q
+			}
+			return xsdChildren(d, func(c xml.StartElement) error {
+				if c.Name.Local != "simpleType" {
+					return d.Skip()
+				}
+				var err error
+				t.ItemSimple, err = p.parseSimpleType(d, c)
+				return err
+			})
+		case "union":
+			if v, ok := xsdAttr(child, "memberTypes"); ok {
+				for _, m := range strings.Fields(v) {
+					t.MemberTypes = append(t.MemberTypes, p.qname(m))
+				}
+			}
+			return xsdChildren(d, func(c xml.StartElement) error {
+				if c.Name.Local != "simpleType" {
+					return d.Skip()
+				}
+				m, err := p.parseSimpleType(d, c)
+				if err != nil {
+					return err
+				}
+				t.Members = append(t.Members, m)
+				return nil
+			})
+		default:
+			return d.Skip()
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("simple type %s: %w", t.Name, err)
+	}
+	return t, nil
+}
+
+func (p *xsdParser) parseFacet(d *xml.Decoder, facet xml.StartElement, t *xsdSimpleType) error {
+	if facet.Name.Local == "simpleType" {
+		var err error
+		t.BaseType, err = p.parseSimpleType(d, facet)
+		return err
+	}
+	value, _ := xsdAttr(facet, "value")
+	intValue := func() (*int, error) {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("facet %s: invalid value %q", facet.Name.Local, value)
+		}
+		return &n, nil
+	}
+	floatValue := func() (*float64, error) {
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("facet %s: invalid value %q", facet.Name.Local, value)
+		}
+		return &f, nil
+	}
+	var err error
+	switch facet.Name.Local {
+	case "enumeration":
+		t.Enumeration = append(t.Enumeration, value)
+	case "pattern":
+		var re *regexp.Regexp
+		re, err = regexp.Compile("^(?:" + value + ")$")
+		if err != nil {
+			err = fmt.Errorf("facet pattern: %w", err)
+		}
+		t.Patterns = append(t.Patterns, re)
+	case "length":
+		t.Length, err = intValue()
+	case "minLength":
+		t.MinLength, err = intValue()
+	case "maxLength":
+		t.MaxLength, err = intValue()
+	case "minInclusive":
+		t.MinInclusive, err = floatValue()
+	case "maxInclusive":
+		t.MaxInclusive, err = floatValue()
+	case "minExclusive":
+		t.MinExclusive, err = floatValue()
+	case "maxExclusive":
+		t.MaxExclusive, err = floatValue()
+	}
+	if err != nil {
+		return err
+	}
+	return d.Skip()
+}
+
+// Validate validates the XML document against the schema. The root element must be declared globally.
+func (s *XSDSchema) Validate(doc string) error {
+	root, err := parseXMLDocument(strings.NewReader(doc))
+	if err != nil {
+		return err
+	}
+	e, ok := s.Elements[root.Name.Local]
+	if !ok {
+		return fmt.Errorf("%s: element is not declared", root.Path)
+	}
+	if s.TargetNamespace != "" && root.Name.Space != s.TargetNamespace {
+		return fmt.Errorf("%s: element must be in namespace %s", root.Path, s.TargetNamespace)
+	}
+	return s.validateElement(e, root)
+}
+
+func (s *XSDSchema) validateElement(e *xsdElement, el *xmlElement) error {
+	if e.Ref.Local != "" {
+		ref, ok := s.Elements[e.Ref.Local]
+		if !ok {
+			return fmt.Errorf("%s: referenced element %s is not declared", el.Path, e.Ref.Local)
+		}
+		e = ref
+	}
+	if e.Nillable {
+		for _, a := range el.Attrs {
+			if a.Name.Space == xsiNamespace && a.Name.Local == "nil" && (a.Value == "true" || a.Value == "1") {
+				if len(el.Children) > 0 || strings.TrimSpace(el.Text) != "" {
+					return fmt.Errorf("%s: nil element must be empty", el.Path)
+				}
+				return nil
+			}
+		}
+	}
+	switch {
+	case e.ComplexType != nil:
+		return s.validateComplex(e.ComplexType, el)
+	case e.SimpleType != nil:
+		return s.validateSimpleElement(e.SimpleType, el)
+	case e.Type.Local == "":
+		// Elements without type are of xs:anyType.
+		return nil
+	}
+	if e.Type.Space == XSDNamespace {
+		if e.Type.Local == "anyType" {
+			return nil
+		}
+		return s.validateSimpleElement(&xsdSimpleType{Base: e.Type}, el)
+	}
+	if t, ok := s.ComplexTypes[e.Type.Local]; ok {
+		return s.validateComplex(t, el)
+	}
+	if t, ok := s.SimpleTypes[e.Type.Local]; ok {
+		return s.validateSimpleElement(t, el)
+	}
+	return fmt.Errorf("%s: type %s is not declared", el.Path, e.Type.Local)
+}
+
+func (s *XSDSchema) validateSimpleElement(t *xsdSimpleType, el *xmlElement) error {
+	if len(el.Children) > 0 {
+		return fmt.Errorf("%s: element must not have child elements", el.Path)
+	}
+	if err := s.validateAttributes(nil, false, el); err != nil {
+		return err
+	}
+	if err := s.validateSimple(t, el.Text); err != nil {
+		return fmt.Errorf("%s: %w", el.Path, err)
+	}
+	return nil
+}
+
+// flatten returns the content model and attributes of the complex type including extended base types.
+func (s *XSDSchema) flatten(t *xsdComplexType) (*xsdParticle, []*xsdAttribute, bool, *xsdSimpleType) {
+	particle, attributes, anyAttribute := t.Particle, t.Attributes, t.AnyAttribute
+	var simple *xsdSimpleType
+	if t.Base.Local != "" {
+		if t.Base.Space == XSDNamespace {
+			simple = &xsdSimpleType{Base: t.Base}
+		} else if st, ok := s.SimpleTypes[t.Base.Local]; ok {
+			simple = st
+		} else if base, ok := s.ComplexTypes[t.Base.Local]; ok && base != t {
+			bp, ba, bany, bsimple := s.flatten(base)
+			attributes = append(append([]*xsdAttribute{}, ba...), attributes...)
+			anyAttribute = anyAttribute || bany
+			simple = bsimple
+			if bp != nil && particle != nil && !t.SimpleContent {
+				particle = &xsdParticle{Kind: xsdParticleSequence, Particles: []*xsdParticle{bp, particle}, MinOccurs: 1, MaxOccurs: 1}
+			} else if particle == nil {
+				particle = bp
+			}
+		}
+	}
+	return particle, attributes, anyAttribute, simple
+}
+
+func (s *XSDSchema) validateComplex(t *xsdComplexType, el *xmlElement) error {
+	particle, attributes, anyAttribute, simple := s.flatten(t)
+	if err := s.validateAttributes(attributes, anyAttribute, el); err != nil {
+		return err
+	}
+	if simple != nil && t.SimpleContent {
+		return s.validateSimpleElement(simple, &xmlElement{Name: el.Name, Text: el.Text, Children: el.Children, Path: el.Path})
+	}
+	if !t.Mixed && strings.TrimSpace(el.Text) != "" {
+		return fmt.Errorf("%s: element must not have text content", el.Path)
+	}
+	m := xsdMatcher{schema: s, children: el.Children}
+	if particle != nil {
+		matched, err := m.particle(particle)
+		if err != nil {
+			return err
+		}
+		if !matched && particle.MinOccurs > 0 {
+			if name := xsdFirstElement(particle); name != "" {
+				return fmt.Errorf("%s: expected element %s", el.Path, name)
+			}
+			return fmt.Errorf("%s: missing child elements", el.Path)
+		}
+	}
+	if m.pos < len(el.Children) {
+		return fmt.Errorf("%s: unexpected element", el.Children[m.pos].Path)
+	}
+	return nil
+}
+
+// xsdFirstElement returns the name of the first required element of the particle.
+func xsdFirstElement(p *xsdParticle) string {
+	switch p.Kind {
+	case xsdParticleElement:
+		return p.Element.Name
+	case xsdParticleSequence, xsdParticleAll:
+		for _, child := range p.Particles {
+			if child.MinOccurs > 0 {
+				return xsdFirstElement(child)
+			}
+		}
+	}
+	return ""
+}
+
+func (s *XSDSchema) validateAttributes(attributes []*xsdAttribute, anyAttribute bool, el *xmlElement) error {
+	declared := make(map[string]*xsdAttribute, len(attributes))
+	for _, a := range attributes {
+		declared[a.Name] = a
+	}
+	present := make(map[string]struct{}, len(el.Attrs))
+	for _, a := range el.Attrs {
+		if a.Name.Space == "xmlns" || a.Name.Space == "" && a.Name.Local == "xmlns" || a.Name.Space == xsiNamespace {
+			continue
+		}
+		decl, ok := declared[a.Name.Local]
+		if !ok {
+			if anyAttribute {
+				continue
+			}
+			return fmt.Errorf("%s/@%s: attribute is not declared", el.Path, a.Name.Local)
+		}
+		present[a.Name.Local] = struct{}{}
+		t := decl.SimpleType
+		if t == nil {
+			t = s.simpleType(decl.Type)
+		}
+		if err := s.validateSimple(t, a.Value); err != nil {
+			return fmt.Errorf("%s/@%s: %w", el.Path, a.Name.Local, err)
+		}
+	}
+	for _, a := range attributes {
+		if _, ok := present[a.Name]; !ok && a.Required {
+			return fmt.Errorf("%s/@%s: attribute is required", el.Path, a.Name)
+		}
+	}
+	return nil
+}
+
+func (s *XSDSchema) simpleType(q xsdQName) *xsdSimpleType {
+	if q.Local == "" {
+		return &xsdSimpleType{Base: xsdQName{Space: XSDNamespace, Local: "anySimpleType"}}
+	}
+	if q.Space != XSDNamespace {
+		if t, ok := s.SimpleTypes[q.Local]; ok {
+			return t
+		}
+	}
+	return &xsdSimpleType{Base: q}
+}
+
+// xsdMatcher matches child elements against particles. Schemas are expected to follow
+// the unique particle attribution constraint, so elements are matched greedily.
+type xsdMatcher struct {
+	schema   *XSDSchema
+	children []*xmlElement
+	pos      int
+}
+
+// particle matches the particle with its occurrences and reports whether anything is matched.
+func (m *xsdMatcher) particle(p *xsdParticle) (bool, error) {
+	count := 0
+	for p.MaxOccurs < 0 || count < p.MaxOccurs {
+		start := m.pos
+		matched, err := m.once(p)
+		if err != nil {
+			return false, err
+		}
+		if !matched || m.pos == start {
+			if matched {
+				count++
+			}
+			break
+		}
+		count++
+	}
+	if count < p.MinOccurs {
+		if count == 0 {
+			return false, nil
+		}
+		return false, m.missing(p)
+	}
+	return true, nil
+}
+
+func (m *xsdMatcher) missing(p *xsdParticle) error {
+	path := "element"
+	if m.pos < len(m.children) {
+		path = m.children[m.pos].Path
+	} else if len(m.children) > 0 {
+		path = m.children[len(m.children)-1].Path
+	}
+	name := "content"
+	switch p.Kind {
+	case xsdParticleElement:
+		name = "element " + p.Element.Name
+	case xsdParticleChoice:
+		var names []string
+		for _, child := range p.Particles {
+			if child.Kind == xsdParticleElement {
+				names = append(names, child.Element.Name)
+			}
+		}
+		if len(names) > 0 {
+			name = "one of elements " + strings.Join(names, ", ")
+		}
+	}
+	return fmt.Errorf("%s: expected %s", path, name)
+}
+
+func (m *xsdMatcher) once(p *xsdParticle) (bool, error) {
+	switch p.Kind {
+	case xsdParticleElement:
+		if m.pos >= len(m.children) || m.children[m.pos].Name.Local != p.Element.Name {
+			return false, nil
+		}
+		if err := m.schema.validateElement(p.Element, m.children[m.pos]); err != nil {
+			return false, err
+		}
+		m.pos++
+		return true, nil
+	case xsdParticleAny:
+		if m.pos >= len(m.children) {
+			return false, nil
+		}
+		m.pos++
+		return true, nil
+	case xsdParticleSequence:
+		start := m.pos
+		for _, child := range p.Particles {
+			matched, err := m.particle(child)
+			if err != nil {
+				return false, err
+			}
+			if !matched && child.MinOccurs > 0 {
+				if m.pos == start {
+					return false, nil
+				}
+				return false, m.missing(child)
+			}
+		}
+		return true, nil
+	case xsdParticleChoice:
+		// Branches that consume elements are preferred, a branch that matches nothing is taken only if none does.
+		start := m.pos
+		empty := false
+		for _, child := range p.Particles {
+			matched, err := m.particle(child)
+			if err != nil {
+				return false, err
+			}
+			if matched && m.pos > start {
+				return true, nil
+			}
+			empty = empty || matched
+			m.pos = start
+		}
+		return empty, nil
+	case xsdParticleAll:
+		seen := make(map[*xsdParticle]struct{})
+		for m.pos < len(m.children) {
+			found := false
+			for _, child := range p.Particles {
+				if _, ok := seen[child]; ok {
+					continue
+				}
+				matched, err := m.once(child)
+				if err != nil {
+					return false, err
+				}
+				if matched {
+					seen[child] = struct{}{}
+					found = true
+					break
+				}
+			}
+			if !found {
+				break
+			}
+		}
+		if len(seen) == 0 {
+			for _, child := range p.Particles {
+				if child.MinOccurs > 0 {
+					return false, nil
+				}
+			}
+		}
+		for _, child := range p.Particles {
+			if _, ok := seen[child]; !ok && child.MinOccurs > 0 {
+				return false, m.missing(child)
+			}
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func (s *XSDSchema) validateSimple(t *xsdSimpleType, value string) error {
+	switch {
+	case t.ItemType != nil || t.ItemSimple != nil:
+		item := t.ItemSimple
+		if item == nil {
+			item = s.simpleType(*t.ItemType)
+		}
+		items := strings.Fields(value)
+		for _, v := range items {
+			if err := s.validateSimple(item, v); err != nil {
+				return err
+			}
+		}
+		return checkXSDLength(t, len(items))
+	case len(t.MemberTypes) > 0 || len(t.Members) > 0:
+		members := append([]*xsdSimpleType{}, t.Members...)
+		for _, q := range t.MemberTypes {
+			members = append(members, s.simpleType(q))
+		}
+		for _, m := range members {
+			if s.validateSimple(m, value) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("value %q does not match any member type", value)
+	}
+
+	base := t.BaseType
+	if base == nil && t.Base.Local != "" && t.Base.Space != XSDNamespace {
+		var ok bool
+		if base, ok = s.SimpleTypes[t.Base.Local]; !ok {
+			return fmt.Errorf("type %s is not declared", t.Base.Local)
+		}
+	}
+	if base != nil && base != t {
+		if err := s.validateSimple(base, value); err != nil {
+			return err
+		}
+	} else if t.Base.Space == XSDNamespace {
+		if err := validateXSDBuiltin(t.Base.Local, value); err != nil {
+			return err
+		}
+	}
+	if xsdWhitespaceCollapsed(t.Base.Local) || base != nil {
+		value = strings.TrimSpace(value)
+	}
+
+	if len(t.Enumeration) > 0 {
+		found := false
+		for _, e := range t.Enumeration {
+			if e == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("value %q must be one of (%s)", value, strings.Join(t.Enumeration, ", "))
+		}
+	}
+	for _, re := range t.Patterns {
+		if !re.MatchString(value) {
+			return fmt.Errorf("value %q must match pattern %s", value, re.String())
+		}
+	}
+	length := len([]rune(value))
+	// Length of binary types is measured in octets.
+	switch t.Base.Local {
+	case "base64Binary":
+		if b, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(value), "")); err == nil {
+			length = len(b)
+		}
+	case "hexBinary":
+		length = len(value) / 2
+	}
+	if err := checkXSDLength(t, length); err != nil {
+		return err
+	}
+	if t.MinInclusive != nil || t.MaxInclusive != nil || t.MinExclusive != nil || t.MaxExclusive != nil {
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("value %q must be numeric", value)
+		}
+		switch {
+		case t.MinInclusive != nil && f < *t.MinInclusive:
+			return fmt.Errorf("value must be greater than or equal to %v", *t.MinInclusive)
+		case t.MaxInclusive != nil && f > *t.MaxInclusive:
+			return fmt.Errorf("value must be less than or equal to %v", *t.MaxInclusive)
+		case t.MinExclusive != nil && f <= *t.MinExclusive:
+			return fmt.Errorf("value must be greater than %v", *t.MinExclusive)
+		case t.MaxExclusive != nil && f >= *t.MaxExclusive:
+			return fmt.Errorf("value must be less than %v", *t.MaxExclusive)
+		}
+	}
+	return nil
+}
+
+func checkXSDLength(t *xsdSimpleType, n int) error {
+	switch {
+	case t.Length != nil && n != *t.Length:
+		return fmt.Errorf("length must be %d", *t.Length)
+	case t.MinLength != nil && n < *t.MinLength:
+		return fmt.Errorf("length must be at least %d", *t.MinLength)
+	case t.MaxLength != nil && n > *t.MaxLength:
+		return fmt.Errorf("length must be at most %d", *t.MaxLength)
+	}
+	return nil
+}
+
+func xsdWhitespaceCollapsed(name string) bool {
+	switch name {
+	case "string", "normalizedString", "anySimpleType", "":
+		return false
+	}
+	return true
+}
+
+// validateXSDBuiltin validates the value of the built-in type. Unknown built-in types accept any value.
+func validateXSDBuiltin(name string, value string) error {
+	v := strings.TrimSpace(value)
+	var err error
+	switch name {
+	case "boolean":
+		if v != "true" && v != "false" && v != "1" && v != "0" {
+			err = fmt.Errorf("invalid boolean")
+		}
+	case "decimal":
+		if _, e := strconv.ParseFloat(v, 64); e != nil || strings.ContainsAny(v, "eEnN") {
+			err = fmt.Errorf("invalid decimal")
+		}
+	case "float", "double":
+		if v != "INF" && v != "-INF" && v != "NaN" {
+			if _, e := strconv.ParseFloat(v, 64); e != nil || strings.ContainsAny(v, "nN") {
+				err = fmt.Errorf("invalid %s", name)
+			}
+		}
+	case "integer", "long", "int", "short", "byte", "nonNegativeInteger", "positiveInteger",
+		"nonPositiveInteger", "negativeInteger", "unsignedLong", "unsignedInt", "unsignedShort", "unsignedByte":
+		err = validateXSDInteger(name, v)
+	case "date":
+		_, err = time.Parse(time.DateOnly, strings.TrimSuffix(v, "Z"))
+	case "time":
+		_, err = time.Parse("15:04:05.999999999", strings.TrimSuffix(v, "Z"))
+	case "dateTime":
+		if _, e := time.Parse(time.RFC3339Nano, v); e != nil {
+			_, err = time.Parse("2006-01-02T15:04:05.999999999", v)
+		}
+	case "base64Binary":
+		_, err = base64.StdEncoding.DecodeString(strings.Join(strings.Fields(v), ""))
+	case "hexBinary":
+		_, err = hex.DecodeString(v)
+	}
+	if err != nil {
+		return fmt.Errorf("value %q is not a valid %s", value, name)
+	}
+	return nil
+}
+
+func validateXSDInteger(name string, v string) error {
+	bounds := map[string][2]float64{
+		"long":               {math.MinInt64, math.MaxInt64},
+		"int":                {math.MinInt32, math.MaxInt32},
+		"short":              {math.MinInt16, math.MaxInt16},
+		"byte":               {math.MinInt8, math.MaxInt8},
+		"nonNegativeInteger": {0, math.Inf(1)},
+		"positiveInteger":    {1, math.Inf(1)},
+		"nonPositiveInteger": {math.Inf(-1), 0},
+		"negativeInteger":    {math.Inf(-1), -1},
+		"unsignedLong":       {0, math.MaxUint64},
+		"unsignedInt":        {0, math.MaxUint32},
+		"unsignedShort":      {0, math.MaxUint16},
+		"unsignedByte":       {0, math.MaxUint8},
+	}
+	if !regexp.MustCompile(`^[+-]?[0-9]+$`).MatchString(v) {
+		return fmt.Errorf("invalid integer")
+	}
+	if b, ok := bounds[name]; ok {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < b[0] || f > b[1] {
+			return fmt.Errorf("integer is out of range")
+		}
+	}
+	return nil
+}
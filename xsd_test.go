@@ -0,0 +1,141 @@
+package raml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const xsdTestSchema = `<?xml version="1.0"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+  <xs:element name="seq">
+    <xs:complexType>
+      <xs:sequence>
+        <xs:element name="a" type="xs:string"/>
+        <xs:element name="b" type="xs:int" minOccurs="0"/>
+        <xs:element name="c" type="xs:string" maxOccurs="2"/>
+      </xs:sequence>
+    </xs:complexType>
+  </xs:element>
+  <xs:element name="choice">
+    <xs:complexType>
+      <xs:choice>
+        <xs:element name="a" type="xs:string" minOccurs="0"/>
+        <xs:element name="b" type="xs:string"/>
+      </xs:choice>
+    </xs:complexType>
+  </xs:element>
+  <xs:element name="repeated">
+    <xs:complexType>
+      <xs:choice minOccurs="1" maxOccurs="unbounded">
+        <xs:element name="a" type="xs:string"/>
+        <xs:element name="b" type="xs:string"/>
+      </xs:choice>
+    </xs:complexType>
+  </xs:element>
+  <xs:element name="all">
+    <xs:complexType>
+      <xs:all>
+        <xs:element name="x" type="xs:string"/>
+        <xs:element name="y" type="xs:string" minOccurs="0"/>
+      </xs:all>
+    </xs:complexType>
+  </xs:element>
+  <xs:element name="attrs">
+    <xs:complexType>
+      <xs:attribute name="id" type="xs:positiveInteger" use="required"/>
+      <xs:attribute name="lang" type="xs:language"/>
+    </xs:complexType>
+  </xs:element>
+  <xs:element name="code" type="Code"/>
+  <xs:element name="price" type="Price"/>
+  <xs:element name="size">
+    <xs:simpleType>
+      <xs:restriction base="xs:string">
+        <xs:enumeration value="S"/>
+        <xs:enumeration value="M"/>
+      </xs:restriction>
+    </xs:simpleType>
+  </xs:element>
+  <xs:element name="list">
+    <xs:simpleType>
+      <xs:list itemType="xs:int"/>
+    </xs:simpleType>
+  </xs:element>
+  <xs:element name="text">
+    <xs:complexType>
+      <xs:simpleContent>
+        <xs:extension base="xs:string">
+          <xs:attribute name="lang" type="xs:string"/>
+        </xs:extension>
+      </xs:simpleContent>
+    </xs:complexType>
+  </xs:element>
+  <xs:simpleType name="Code">
+    <xs:restriction base="xs:string">
+      <xs:pattern value="[A-Z]{2}\d"/>
+      <xs:maxLength value="3"/>
+    </xs:restriction>
+  </xs:simpleType>
+  <xs:simpleType name="Price">
+    <xs:restriction base="xs:decimal">
+      <xs:minExclusive value="0"/>
+      <xs:maxInclusive value="100"/>
+    </xs:restriction>
+  </xs:simpleType>
+</xs:schema>`
+
+func TestXSDSchema_Validate(t *testing.T) {
+	schema, err := ParseXSD([]byte(xsdTestSchema))
+	require.NoError(t, err)
+	tests := []struct {
+		name    string
+		doc     string
+		wantErr string
+	}{
+		{name: "sequence", doc: `<seq><a>x</a><b>1</b><c>y</c><c>z</c></seq>`},
+		{name: "sequence without optional element", doc: `<seq><a>x</a><c>y</c></seq>`},
+		{name: "sequence missing element", doc: `<seq><a>x</a></seq>`, wantErr: "expected element c"},
+		{name: "sequence out of order", doc: `<seq><c>y</c><a>x</a></seq>`, wantErr: "/seq: expected element a"},
+		{name: "sequence exceeds maxOccurs", doc: `<seq><a>x</a><c>1</c><c>2</c><c>3</c></seq>`, wantErr: "/seq/c[3]: unexpected element"},
+		{name: "sequence invalid child", doc: `<seq><a>x</a><b>one</b><c>y</c></seq>`, wantErr: "/seq/b[1]"},
+		{name: "choice of required branch", doc: `<choice><b>x</b></choice>`},
+		{name: "choice of optional branch", doc: `<choice><a>x</a></choice>`},
+		{name: "choice matches nothing", doc: `<choice/>`},
+		{name: "choice of both", doc: `<choice><a>x</a><b>y</b></choice>`, wantErr: "/choice/b[1]: unexpected element"},
+		{name: "repeated choice", doc: `<repeated><b>1</b><a>2</a><b>3</b></repeated>`},
+		{name: "repeated choice is required", doc: `<repeated/>`, wantErr: "missing child elements"},
+		{name: "all in any order", doc: `<all><y>1</y><x>2</x></all>`},
+		{name: "all without optional element", doc: `<all><x>2</x></all>`},
+		{name: "all missing element", doc: `<all><y>1</y></all>`, wantErr: "expected element x"},
+		{name: "all repeated element", doc: `<all><x>1</x><x>2</x></all>`, wantErr: "/all/x[2]: unexpected element"},
+		{name: "attributes", doc: `<attrs id="3" lang="en-US"/>`},
+		{name: "missing required attribute", doc: `<attrs lang="en"/>`, wantErr: "/attrs/@id: attribute is required"},
+		{name: "invalid attribute value", doc: `<attrs id="0"/>`, wantErr: "/attrs/@id"},
+		{name: "undeclared attribute", doc: `<attrs id="1" extra="x"/>`, wantErr: "/attrs/@extra: attribute is not declared"},
+		{name: "pattern", doc: `<code>AB1</code>`},
+		{name: "pattern mismatch", doc: `<code>ab1</code>`, wantErr: "/code"},
+		{name: "length facet", doc: `<code>AB12</code>`, wantErr: "/code"},
+		{name: "bounds", doc: `<price>99.99</price>`},
+		{name: "exclusive bound", doc: `<price>0</price>`, wantErr: "/price"},
+		{name: "inclusive bound", doc: `<price>100.01</price>`, wantErr: "/price: value must be less than or equal to 100"},
+		{name: "enumeration", doc: `<size>M</size>`},
+		{name: "enumeration mismatch", doc: `<size>L</size>`, wantErr: "/size"},
+		{name: "list", doc: `<list>1 2 3</list>`},
+		{name: "list item", doc: `<list>1 x</list>`, wantErr: "/list"},
+		{name: "simple content", doc: `<text lang="en">hello</text>`},
+		{name: "simple content child", doc: `<text><b/></text>`, wantErr: "/text"},
+		{name: "undeclared root", doc: `<other/>`, wantErr: "/other: element is not declared"},
+		{name: "malformed", doc: `<seq>`, wantErr: "XML"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.Validate(tt.doc)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,651 @@
+package raml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+
+	"github.com/acronis/go-raml/stacktrace"
+)
+
+// xsdType is a type of XSD element or attribute. It is either a reference to a named type
+// or an anonymous type definition.
+type xsdType struct {
+	ref    string
+	inline string
+	// simple reports whether the type can be used for attributes.
+	simple bool
+}
+
+var xsdAnyType = xsdType{ref: "xs:anyType"}
+
+type XSDConverterOpt interface {
+	Apply(*XSDConverterOptions)
+}
+
+type optXSDTargetNamespace struct {
+	namespace string
+	prefix    string
+}
+
+func (o optXSDTargetNamespace) Apply(e *XSDConverterOptions) {
+	e.targetNamespace = o.namespace
+	e.prefix = o.prefix
+}
+
+// WithXSDTargetNamespace sets the target namespace and its prefix. By default, the namespace
+// is taken from the xml facet of the library types.
+func WithXSDTargetNamespace(namespace string, prefix string) XSDConverterOpt {
+	return optXSDTargetNamespace{namespace: namespace, prefix: prefix}
+}
+
+type XSDConverterOptions struct {
+	targetNamespace string
+	prefix          string
+}
+
+// XSDConverter converts library types to XML Schema using their xml facets.
+// Visit methods return element types and declare named types when necessary.
+type XSDConverter struct {
+	opts XSDConverterOptions
+
+	decls    *orderedmap.OrderedMap[string, string]
+	names    map[string]string
+	xmls     map[string]*XML
+	elements []string
+	prefix   string
+	warnings []*stacktrace.StackTrace
+	// warned holds shape IDs with reported messages, so that shapes visited several times are reported once.
+	warned map[string]struct{}
+}
+
+var _ ShapeVisitor[xsdType] = (*XSDConverter)(nil)
+
+func NewXSDConverter(opts ...XSDConverterOpt) *XSDConverter {
+	c := &XSDConverter{}
+	for _, opt := range opts {
+		opt.Apply(&c.opts)
+	}
+	return c
+}
+
+// ConvertLibrary converts the library and the libraries it uses into an XML Schema document.
+// Every type is declared as a named XSD type and every object type is also declared as a global element
+// named after the xml facet or the type name. Types of used libraries are prefixed with the "uses" alias.
+// Constructs that cannot be represented in XML Schema are reported as warnings.
+func (c *XSDConverter) ConvertLibrary(lib *Library) ([]byte, []*stacktrace.StackTrace) {
+	c.decls = orderedmap.New[string, string]()
+	c.names = make(map[string]string)
+	c.xmls = make(map[string]*XML)
+	c.elements = nil
+	c.warnings = nil
+	c.warned = make(map[string]struct{})
+
+	types := orderedmap.New[string, Shape]()
+	c.collectLibrary(lib, "", types, make(map[string]struct{}))
+	namespace, prefix := c.opts.targetNamespace, c.opts.prefix
+	for pair := types.Oldest(); pair != nil; pair = pair.Next() {
+		base := pair.Value.Base()
+		if target, ok := c.names[base.Id]; ok {
+			c.warn(base, "alias is replaced with the referenced type", stacktrace.WithInfo("type", target))
+			continue
+		}
+		c.names[base.Id] = pair.Key
+		c.xmls[base.Id] = base.XML
+		c.decls.Set(pair.Key, "")
+		if base.XML == nil || base.XML.Namespace == nil || c.opts.targetNamespace != "" {
+			continue
+		}
+		if namespace == "" {
+			namespace = *base.XML.Namespace
+			if base.XML.Prefix != nil {
+				prefix = *base.XML.Prefix
+			}
+		} else if namespace != *base.XML.Namespace {
+			c.warn(base, "only one target namespace is supported, namespace is ignored",
+				stacktrace.WithInfo("namespace", *base.XML.Namespace))
+		}
+	}
+	if namespace != "" {
+		if prefix == "" {
+			prefix = "tns"
+		}
+		c.prefix = prefix + ":"
+	} else {
+		c.prefix = ""
+	}
+
+	for pair := types.Oldest(); pair != nil; pair = pair.Next() {
+		s := pair.Value
+		if c.names[s.Base().Id] != pair.Key {
+			continue
+		}
+		c.decls.Set(pair.Key, c.declare(pair.Key, s))
+		if _, ok := s.(*ObjectShape); ok {
+			c.elements = append(c.elements, fmt.Sprintf("<xs:element name=%s type=%s/>\n",
+				xsdQuote(s.Base().XML.NameOr(pair.Key)), xsdQuote(c.prefix+pair.Key)))
+		}
+	}
+	return c.render(namespace, prefix), c.warnings
+}
+
+func (c *XSDConverter) collectLibrary(lib *Library, prefix string, types *orderedmap.OrderedMap[string, Shape], visited map[string]struct{}) {
+	if _, ok := visited[lib.Location]; ok {
+		return
+	}
+	visited[lib.Location] = struct{}{}
+	for pair := lib.Types.Oldest(); pair != nil; pair = pair.Next() {
+		s := *pair.Value
+		if !s.Base().IsUnwrapped() {
+			us, err := lib.raml.UnwrapShape(pair.Value, make([]Shape, 0))
+			if err != nil {
+				c.warn(s.Base(), "type cannot be unwrapped and is skipped", stacktrace.WithInfo("error", err))
+				continue
+			}
+			s = us
+		}
+		types.Set(prefix+goIdentifier(pair.Key), s)
+	}
+	for pair := lib.Uses.Oldest(); pair != nil; pair = pair.Next() {
+		if pair.Value.Link != nil {
+			c.collectLibrary(pair.Value.Link, prefix+goIdentifier(pair.Key), types, visited)
+		}
+	}
+}
+
+func (c *XSDConverter) render(namespace string, prefix string) []byte {
+	var b strings.Builder
+	b.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	b.WriteString("<!-- Code generated by go-raml. DO NOT EDIT. -->\n")
+	fmt.Fprintf(&b, "<xs:schema xmlns:xs=%s", xsdQuote(XSDNamespace))
+	if namespace != "" {
+		fmt.Fprintf(&b, " xmlns:%s=%s targetNamespace=%s elementFormDefault=\"qualified\"",
+			prefix, xsdQuote(namespace), xsdQuote(namespace))
+	}
+	b.WriteString(">\n")
+	for _, e := range c.elements {
+		b.WriteString(xsdIndent(e, "  "))
+	}
+	for pair := c.decls.Oldest(); pair != nil; pair = pair.Next() {
+		b.WriteString(xsdIndent(pair.Value, "  "))
+	}
+	b.WriteString("</xs:schema>\n")
+	return []byte(b.String())
+}
+
+// declare returns the named declaration of the shape.
+func (c *XSDConverter) declare(name string, s Shape) string {
+	t := c.visit(s)
+	if t.inline == "" {
+		if t.simple {
+			t.inline = fmt.Sprintf("<xs:simpleType>\n  <xs:restriction base=%s/>\n</xs:simpleType>\n", xsdQuote(t.ref))
+		} else {
+			t.inline = xsdAnyContent()
+		}
+	}
+	// Anonymous definitions start with "<xs:simpleType" or "<xs:complexType".
+	i := strings.IndexAny(t.inline, " >")
+	return t.inline[:i] + " name=" + xsdQuote(name) + t.inline[i:]
+}
+
+func xsdAnyContent() string {
+	return "<xs:complexType mixed=\"true\">\n" +
+		"  <xs:sequence>\n" +
+		"    <xs:any minOccurs=\"0\" maxOccurs=\"unbounded\" processContents=\"lax\"/>\n" +
+		"  </xs:sequence>\n" +
+		"  <xs:anyAttribute processContents=\"lax\"/>\n" +
+		"</xs:complexType>\n"
+}
+
+// Visit returns the type of the shape.
+func (c *XSDConverter) Visit(s Shape) xsdType {
+	if name, ok := c.names[s.Base().Id]; ok {
+		return xsdType{ref: c.prefix + name, simple: isXSDSimple(s)}
+	}
+	return c.visit(s)
+}
+
+func (c *XSDConverter) visit(s Shape) xsdType {
+	switch s := s.(type) {
+	case *ObjectShape:
+		return c.VisitObjectShape(s)
+	case *ArrayShape:
+		return c.VisitArrayShape(s)
+	case *StringShape:
+		return c.VisitStringShape(s)
+	case *NumberShape:
+		return c.VisitNumberShape(s)
+	case *IntegerShape:
+		return c.VisitIntegerShape(s)
+	case *BooleanShape:
+		return c.VisitBooleanShape(s)
+	case *FileShape:
+		return c.VisitFileShape(s)
+	case *UnionShape:
+		return c.VisitUnionShape(s)
+	case *DateTimeShape:
+		return c.VisitDateTimeShape(s)
+	case *DateTimeOnlyShape:
+		return c.VisitDateTimeOnlyShape(s)
+	case *DateOnlyShape:
+		return c.VisitDateOnlyShape(s)
+	case *TimeOnlyShape:
+		return c.VisitTimeOnlyShape(s)
+	case *RecursiveShape:
+		return c.VisitRecursiveShape(s)
+	case *JSONShape:
+		return c.VisitJSONShape(s)
+	case *XMLShape:
+		return c.VisitXMLShape(s)
+	case *AnyShape:
+		return c.VisitAnyShape(s)
+	case *NilShape:
+		return c.VisitNilShape(s)
+	default:
+		c.warn(s.Base(), "unsupported type is replaced with xs:anyType")
+		return xsdAnyType
+	}
+}
+
+// isXSDSimple reports whether the shape is converted to a simple type.
+func isXSDSimple(s Shape) bool {
+	switch s := s.(type) {
+	case *StringShape, *NumberShape, *IntegerShape, *BooleanShape, *FileShape, *DateTimeShape,
+		*DateTimeOnlyShape, *DateOnlyShape, *TimeOnlyShape, *NilShape, *JSONShape:
+		return true
+	case *UnionShape:
+		for _, item := range s.AnyOf {
+			if !isXSDSimple(*item) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// elementName returns the name of the element of the property. The xml facet inherited
+// from a declared type names the root element of that type only, so it is ignored here.
+func (c *XSDConverter) elementName(s Shape, name string) string {
+	base := s.Base()
+	if _, ok := c.names[base.Id]; ok && c.xmls[base.Id] == base.XML {
+		return name
+	}
+	return base.XML.NameOr(name)
+}
+
+// VisitObjectShape returns a complex type with a sequence of property elements.
+// Properties with the xml attribute facet are converted to attributes.
+func (c *XSDConverter) VisitObjectShape(s *ObjectShape) xsdType {
+	var elements, attributes strings.Builder
+	if s.Properties != nil {
+		for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+			prop := pair.Value
+			ps := *prop.Shape
+			name := c.elementName(ps, prop.Name)
+			if ps.Base().XML.IsAttribute() {
+				t := c.Visit(ps)
+				if t.simple {
+					fmt.Fprintf(&attributes, "<xs:attribute name=%s", xsdQuote(name))
+					if prop.Required {
+						attributes.WriteString(" use=\"required\"")
+					}
+					writeXSDTyped(&attributes, "xs:attribute", t)
+					continue
+				}
+				c.warn(ps.Base(), "complex property cannot be an attribute and is converted to element")
+			}
+			c.writeProperty(&elements, name, ps, prop.Required)
+		}
+	}
+	if s.PatternProperties != nil && s.PatternProperties.Len() > 0 {
+		c.warn(s.Base(), "pattern properties are converted to any elements")
+		elements.WriteString("<xs:any minOccurs=\"0\" maxOccurs=\"unbounded\" processContents=\"lax\"/>\n")
+	}
+	var b strings.Builder
+	b.WriteString("<xs:complexType>\n")
+	if elements.Len() > 0 {
+		b.WriteString("  <xs:sequence>\n")
+		b.WriteString(xsdIndent(elements.String(), "    "))
+		b.WriteString("  </xs:sequence>\n")
+	}
+	b.WriteString(xsdIndent(attributes.String(), "  "))
+	b.WriteString("</xs:complexType>\n")
+	return xsdType{inline: b.String()}
+}
+
+// writeProperty writes the element of the property. Arrays are written as repeated elements
+// unless they are wrapped, and unions with nil are written as nillable elements.
+func (c *XSDConverter) writeProperty(b *strings.Builder, name string, s Shape, required bool) {
+	minOccurs := "1"
+	if !required {
+		minOccurs = "0"
+	}
+	if a, ok := s.(*ArrayShape); ok && !a.XML.IsWrapped() {
+		if required && a.MinItems != nil {
+			minOccurs = strconv.FormatUint(*a.MinItems, 10)
+		} else {
+			minOccurs = "0"
+		}
+		fmt.Fprintf(b, "<xs:element name=%s minOccurs=%s maxOccurs=%s", xsdQuote(name), xsdQuote(minOccurs), xsdQuote(xsdMaxItems(a)))
+		writeXSDTyped(b, "xs:element", c.items(a))
+		return
+	}
+	nillable := false
+	if u, ok := s.(*UnionShape); ok && c.names[u.Id] == "" {
+		var members []*Shape
+		for _, item := range u.AnyOf {
+			if _, ok := (*item).(*NilShape); ok {
+				nillable = true
+			} else {
+				members = append(members, item)
+			}
+		}
+		if nillable && len(members) == 1 {
+			s = *members[0]
+		} else {
+			nillable = false
+		}
+	}
+	fmt.Fprintf(b, "<xs:element name=%s", xsdQuote(name))
+	if minOccurs != "1" {
+		fmt.Fprintf(b, " minOccurs=%s", xsdQuote(minOccurs))
+	}
+	if nillable {
+		b.WriteString(" nillable=\"true\"")
+	}
+	writeXSDTyped(b, "xs:element", c.Visit(s))
+}
+
+func xsdMaxItems(a *ArrayShape) string {
+	if a.MaxItems != nil {
+		return strconv.FormatUint(*a.MaxItems, 10)
+	}
+	return "unbounded"
+}
+
+func (c *XSDConverter) items(a *ArrayShape) xsdType {
+	if a.Items == nil {
+		return xsdAnyType
+	}
+	if _, ok := (*a.Items).(*ArrayShape); ok && c.names[(*a.Items).Base().Id] == "" {
+		c.warn(a.Base(), "nested array items are replaced with xs:anyType")
+		return xsdAnyType
+	}
+	return c.Visit(*a.Items)
+}
+
+// writeXSDTyped completes the open element or attribute tag with the type.
+func writeXSDTyped(b *strings.Builder, tag string, t xsdType) {
+	if t.inline == "" {
+		fmt.Fprintf(b, " type=%s/>\n", xsdQuote(t.ref))
+		return
+	}
+	b.WriteString(">\n")
+	b.WriteString(xsdIndent(t.inline, "  "))
+	fmt.Fprintf(b, "</%s>\n", tag)
+}
+
+// VisitArrayShape returns a complex type with a sequence of item elements.
+// Items are named after their xml facet, their declared type or "item".
+func (c *XSDConverter) VisitArrayShape(s *ArrayShape) xsdType {
+	name := "item"
+	if s.Items != nil {
+		ib := (*s.Items).Base()
+		if n, ok := c.names[ib.Id]; ok {
+			name = n
+		}
+		name = ib.XML.NameOr(name)
+	}
+	minOccurs := "0"
+	if s.MinItems != nil {
+		minOccurs = strconv.FormatUint(*s.MinItems, 10)
+	}
+	var b strings.Builder
+	b.WriteString("<xs:complexType>\n  <xs:sequence>\n")
+	var item strings.Builder
+	fmt.Fprintf(&item, "<xs:element name=%s minOccurs=%s maxOccurs=%s", xsdQuote(name), xsdQuote(minOccurs), xsdQuote(xsdMaxItems(s)))
+	writeXSDTyped(&item, "xs:element", c.items(s))
+	b.WriteString(xsdIndent(item.String(), "    "))
+	b.WriteString("  </xs:sequence>\n</xs:complexType>\n")
+	return xsdType{inline: b.String()}
+}
+
+// xsdRestriction returns the simple type that restricts the built-in type with facets.
+func xsdRestriction(base string, facets []string) xsdType {
+	if len(facets) == 0 {
+		return xsdType{ref: base, simple: true}
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "<xs:simpleType>\n  <xs:restriction base=%s>\n", xsdQuote(base))
+	for _, f := range facets {
+		b.WriteString("    " + f + "\n")
+	}
+	b.WriteString("  </xs:restriction>\n</xs:simpleType>\n")
+	return xsdType{inline: b.String(), simple: true}
+}
+
+func xsdFacet(name string, value any) string {
+	return fmt.Sprintf("<xs:%s value=%s/>", name, xsdQuote(fmt.Sprint(value)))
+}
+
+func xsdEnumFacets(enum Nodes) []string {
+	facets := make([]string, 0, len(enum))
+	for _, e := range enum {
+		facets = append(facets, xsdFacet("enumeration", e.Value))
+	}
+	return facets
+}
+
+func xsdLengthFacets(f LengthFacets) []string {
+	var facets []string
+	if f.MinLength != nil {
+		facets = append(facets, xsdFacet("minLength", *f.MinLength))
+	}
+	if f.MaxLength != nil {
+		facets = append(facets, xsdFacet("maxLength", *f.MaxLength))
+	}
+	return facets
+}
+
+func (c *XSDConverter) VisitStringShape(s *StringShape) xsdType {
+	facets := xsdEnumFacets(s.Enum)
+	if s.Pattern != nil {
+		pattern, problems := xsdPattern(s.Pattern.String())
+		if len(problems) > 0 {
+			c.warn(s.Base(), "pattern cannot be represented exactly and is approximated",
+				stacktrace.WithInfo("pattern", s.Pattern.String()), stacktrace.WithInfo("unsupported", strings.Join(problems, ", ")))
+		}
+		facets = append(facets, xsdFacet("pattern", pattern))
+	}
+	facets = append(facets, xsdLengthFacets(s.LengthFacets)...)
+	return xsdRestriction("xs:string", facets)
+}
+
+func (c *XSDConverter) VisitNumberShape(s *NumberShape) xsdType {
+	base := "xs:decimal"
+	if s.Format != nil {
+		switch *s.Format {
+		case "float":
+			base = "xs:float"
+		case "double":
+			base = "xs:double"
+		default:
+			base = xsdIntegerType(*s.Format)
+		}
+	}
+	facets := xsdEnumFacets(s.Enum)
+	if s.Minimum != nil {
+		facets = append(facets, xsdFacet("minInclusive", *s.Minimum))
+	}
+	if s.Maximum != nil {
+		facets = append(facets, xsdFacet("maxInclusive", *s.Maximum))
+	}
+	if s.MultipleOf != nil {
+		c.warn(s.Base(), "multipleOf cannot be represented and is dropped")
+	}
+	return xsdRestriction(base, facets)
+}
+
+func xsdIntegerType(format string) string {
+	switch format {
+	case "int8":
+		return "xs:byte"
+	case "int16":
+		return "xs:short"
+	case "int32", "int":
+		return "xs:int"
+	case "int64", "long":
+		return "xs:long"
+	}
+	return "xs:integer"
+}
+
+func (c *XSDConverter) VisitIntegerShape(s *IntegerShape) xsdType {
+	base := "xs:integer"
+	if s.Format != nil {
+		base = xsdIntegerType(*s.Format)
+	}
+	facets := xsdEnumFacets(s.Enum)
+	if s.Minimum != nil {
+		facets = append(facets, xsdFacet("minInclusive", s.Minimum.String()))
+	}
+	if s.Maximum != nil {
+		facets = append(facets, xsdFacet("maxInclusive", s.Maximum.String()))
+	}
+	if s.MultipleOf != nil {
+		c.warn(s.Base(), "multipleOf cannot be represented and is dropped")
+	}
+	return xsdRestriction(base, facets)
+}
+
+func (c *XSDConverter) VisitBooleanShape(s *BooleanShape) xsdType {
+	return xsdRestriction("xs:boolean", xsdEnumFacets(s.Enum))
+}
+
+// VisitFileShape returns base64 encoded binary restricted by length facets in bytes.
+func (c *XSDConverter) VisitFileShape(s *FileShape) xsdType {
+	return xsdRestriction("xs:base64Binary", xsdLengthFacets(s.LengthFacets))
+}
+
+// VisitUnionShape returns a union of simple member types. Unions with complex members
+// cannot be represented and are replaced with xs:anyType.
+func (c *XSDConverter) VisitUnionShape(s *UnionShape) xsdType {
+	if !isXSDSimple(s) {
+		c.warn(s.Base(), "union with complex members is replaced with xs:anyType")
+		return xsdAnyType
+	}
+	var refs []string
+	var inline strings.Builder
+	for _, item := range s.AnyOf {
+		t := c.Visit(*item)
+		if t.inline != "" {
+			inline.WriteString(xsdIndent(t.inline, "    "))
+		} else {
+			refs = append(refs, t.ref)
+		}
+	}
+	var b strings.Builder
+	b.WriteString("<xs:simpleType>\n  <xs:union")
+	if len(refs) > 0 {
+		fmt.Fprintf(&b, " memberTypes=%s", xsdQuote(strings.Join(refs, " ")))
+	}
+	if inline.Len() == 0 {
+		b.WriteString("/>\n")
+	} else {
+		b.WriteString(">\n")
+		b.WriteString(inline.String())
+		b.WriteString("  </xs:union>\n")
+	}
+	b.WriteString("</xs:simpleType>\n")
+	return xsdType{inline: b.String(), simple: true}
+}
+
+func (c *XSDConverter) VisitDateTimeShape(s *DateTimeShape) xsdType {
+	if s.Format != nil && *s.Format == "rfc2616" {
+		c.warn(s.Base(), "rfc2616 datetime is replaced with xs:string")
+		return xsdType{ref: "xs:string", simple: true}
+	}
+	return xsdType{ref: "xs:dateTime", simple: true}
+}
+
+func (c *XSDConverter) VisitDateTimeOnlyShape(s *DateTimeOnlyShape) xsdType {
+	return xsdType{ref: "xs:dateTime", simple: true}
+}
+
+func (c *XSDConverter) VisitDateOnlyShape(s *DateOnlyShape) xsdType {
+	return xsdType{ref: "xs:date", simple: true}
+}
+
+func (c *XSDConverter) VisitTimeOnlyShape(s *TimeOnlyShape) xsdType {
+	return xsdType{ref: "xs:time", simple: true}
+}
+
+func (c *XSDConverter) VisitRecursiveShape(s *RecursiveShape) xsdType {
+	if name, ok := c.names[(*s.Head).Base().Id]; ok {
+		return xsdType{ref: c.prefix + name}
+	}
+	c.warn(s.Base(), "recursion to anonymous type is replaced with xs:anyType")
+	return xsdAnyType
+}
+
+func (c *XSDConverter) VisitJSONShape(s *JSONShape) xsdType {
+	c.warn(s.Base(), "JSON schema is replaced with xs:string")
+	return xsdType{ref: "xs:string", simple: true}
+}
+
+func (c *XSDConverter) VisitXMLShape(s *XMLShape) xsdType {
+	c.warn(s.Base(), "embedded XML schema is not imported and is replaced with xs:anyType")
+	return xsdAnyType
+}
+
+func (c *XSDConverter) VisitAnyShape(s *AnyShape) xsdType {
+	return xsdAnyType
+}
+
+// VisitNilShape returns an empty string. Unions with nil are written as nillable elements instead.
+func (c *XSDConverter) VisitNilShape(s *NilShape) xsdType {
+	return xsdRestriction("xs:string", []string{xsdFacet("length", 0)})
+}
+
+func (c *XSDConverter) warn(base *BaseShape, message string, opts ...stacktrace.Option) {
+	key := base.Id + "\x00" + message
+	if _, ok := c.warned[key]; ok {
+		return
+	}
+	c.warned[key] = struct{}{}
+	opts = append(opts,
+		stacktrace.WithPosition(&base.Position),
+		stacktrace.WithSeverity(stacktrace.SeverityWarning),
+		stacktrace.WithType(stacktrace.TypeConverting),
+		stacktrace.WithInfo("shape", base.Name),
+	)
+	c.warnings = append(c.warnings, stacktrace.New(message, base.Location, opts...))
+}
+
+// xsdQuote returns the double-quoted and escaped attribute value.
+func xsdQuote(v string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	_ = xml.EscapeText(&b, []byte(v))
+	b.WriteByte('"')
+	return b.String()
+}
+
+// xsdIndent indents every line of the text.
+func xsdIndent(text string, indent string) string {
+	if text == "" {
+		return ""
+	}
+	lines := strings.SplitAfter(text, "\n")
+	var b strings.Builder
+	for _, l := range lines {
+		if l != "" {
+			b.WriteString(indent + l)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,70 @@
+package raml
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestXSDConverter_ConvertLibrary(t *testing.T) {
+	rml := parseLibrary(t, `#%RAML 1.0 Library
+types:
+  Code:
+    type: string
+    pattern: ^\d{3}(?=x)
+  Item:
+    type: object
+    properties:
+      code: Code
+      alt: Code
+      name:
+        type: string
+        pattern: ^[a-z]+$
+`, OptWithRegexpEngine(NewECMAScriptEngine()))
+	out, warnings := NewXSDConverter().ConvertLibrary(rml.EntryPoint().(*Library))
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "anchors are stripped", want: `<xs:pattern value="[a-z]+"/>`},
+		{name: "approximated pattern", want: `<xs:pattern value="[0-9]{3}(x)[\s\S]*"/>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Contains(t, string(out), tt.want)
+		})
+	}
+	// Code is visited for its declaration and both properties, but is reported once.
+	require.Len(t, warnings, 1)
+	require.Equal(t, "pattern cannot be represented exactly and is approximated", warnings[0].Message)
+}
+
+func TestParse_XSDInclude(t *testing.T) {
+	dir := t.TempDir()
+	xsd := `<?xml version="1.0"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+  <xs:element name="note" type="xs:string"/>
+</xs:schema>
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "note.xsd"), []byte(xsd), 0o600))
+	library := `#%RAML 1.0 Library
+types:
+  Note: !include note.xsd
+  Other: !include note.xsd
+`
+	rml, err := ParseFromString(library, "library.raml", dir, OptWithUnwrap(), OptWithValidate())
+	require.NoError(t, err)
+
+	note := libraryType(t, rml, "Note")
+	other := libraryType(t, rml, "Other")
+	xmlShape, ok := note.(*XMLShape)
+	require.True(t, ok, "got %T", note)
+	require.Equal(t, strings.TrimSpace(xsd), xmlShape.Raw)
+	// The schema is loaded once and shared through the fragment cache.
+	require.NotNil(t, rml.GetFragment(filepath.Join(dir, "note.xsd")))
+	require.Equal(t, xmlShape.Schema, other.(*XMLShape).Schema)
+}
@@ -0,0 +1,232 @@
+package raml
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// xsdMetaChars are characters that must be escaped in XML Schema regular expressions to match literally.
+const xsdMetaChars = `\|.?*+(){}-[]^`
+
+// xsdAnyChars matches any string including line terminators, which "." does not match.
+const xsdAnyChars = `[\s\S]*`
+
+var xsdQuantifierRegexp = regexp.MustCompile(`^\{\d+(,\d*)?\}`)
+
+// xsdPattern translates the ECMAScript pattern to an XML Schema regular expression.
+// XML Schema patterns are implicitly anchored, so leading "^" and trailing "$" are stripped
+// and unanchored ends are padded to match any characters.
+// Character class escapes are replaced with their ECMAScript meaning, e.g. "\d" becomes "[0-9]".
+// Constructs that cannot be expressed are approximated and reported as problems.
+func xsdPattern(expr string) (string, []string) {
+	var b strings.Builder
+	var problems []string
+	seen := make(map[string]struct{})
+	problem := func(p string) {
+		if _, ok := seen[p]; !ok {
+			seen[p] = struct{}{}
+			problems = append(problems, p)
+		}
+	}
+
+	anchoredStart := strings.HasPrefix(expr, "^")
+	anchoredEnd := strings.HasSuffix(expr, "$") && !isEscapedAt(expr, len(expr)-1)
+	body := expr
+	if anchoredStart {
+		body = body[1:]
+	}
+	if anchoredEnd && body != "" {
+		body = body[:len(body)-1]
+	}
+
+	inClass, quantified, alternation := false, false, false
+	depth := 0
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		wasQuantified := quantified
+		quantified = false
+		switch {
+		case c == '\\':
+			if i+1 == len(body) {
+				problem("trailing backslash")
+				break
+			}
+			i++
+			i += xsdEscape(&b, body, i, inClass, problem)
+		case inClass:
+			switch c {
+			case ']':
+				inClass = false
+				b.WriteByte(c)
+			case '[':
+				b.WriteString(`\[`)
+			default:
+				b.WriteByte(c)
+			}
+		case c == '[':
+			if strings.HasPrefix(body[i:], "[]") || strings.HasPrefix(body[i:], "[^]") {
+				problem("empty character classes")
+			}
+			inClass = true
+			b.WriteByte(c)
+			if strings.HasPrefix(body[i+1:], "^") {
+				b.WriteByte('^')
+				i++
+			}
+		case c == '(':
+			depth++
+			b.WriteByte('(')
+			switch {
+			case strings.HasPrefix(body[i:], "(?:"):
+				i += 2
+			case strings.HasPrefix(body[i:], "(?=") || strings.HasPrefix(body[i:], "(?!"):
+				problem("lookaheads")
+				i += 2
+			case strings.HasPrefix(body[i:], "(?<=") || strings.HasPrefix(body[i:], "(?<!"):
+				problem("lookbehinds")
+				i += 3
+			case strings.HasPrefix(body[i:], "(?<"):
+				// Named groups are plain groups since XML Schema has no captures.
+				if end := strings.IndexByte(body[i:], '>'); end != -1 {
+					i += end
+				}
+			case strings.HasPrefix(body[i:], "(?"):
+				problem("group modifiers")
+				i++
+			}
+		case c == ')':
+			depth--
+			b.WriteByte(c)
+		case c == '|':
+			if depth == 0 {
+				alternation = true
+			}
+			b.WriteByte(c)
+		case c == '^' || c == '$':
+			problem("anchors inside pattern")
+		case c == '*' || c == '+' || c == '?':
+			if c == '?' && wasQuantified {
+				problem("lazy quantifiers")
+				break
+			}
+			b.WriteByte(c)
+			quantified = true
+		case c == '{':
+			if q := xsdQuantifierRegexp.FindString(body[i:]); q != "" {
+				b.WriteString(q)
+				i += len(q) - 1
+				quantified = true
+			} else {
+				b.WriteString(`\{`)
+			}
+		case c == '}' || c == ']':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	if alternation && (anchoredStart || anchoredEnd) {
+		problem("anchors in alternatives")
+	}
+
+	result := b.String()
+	if alternation && (!anchoredStart || !anchoredEnd) {
+		result = "(" + result + ")"
+	}
+	if !anchoredStart {
+		result = xsdAnyChars + result
+	}
+	if !anchoredEnd {
+		result += xsdAnyChars
+	}
+	return result, problems
+}
+
+// xsdEscape writes the translation of the escape sequence starting after the backslash at i.
+// It returns the number of extra characters consumed.
+func xsdEscape(b *strings.Builder, expr string, i int, inClass bool, problem func(string)) int {
+	c := expr[i]
+	switch c {
+	case 'd':
+		b.WriteString(xsdClassEscape("0-9", inClass))
+	case 'w':
+		b.WriteString(xsdClassEscape("A-Za-z0-9_", inClass))
+	case 'D', 'W':
+		if inClass {
+			problem(`negated class escapes in character classes`)
+			b.WriteString(`\` + string(c))
+			break
+		}
+		if c == 'D' {
+			b.WriteString("[^0-9]")
+		} else {
+			b.WriteString("[^A-Za-z0-9_]")
+		}
+	case 's', 'S':
+		problem(`\s matches only space, tab, CR and LF`)
+		b.WriteString(`\` + string(c))
+	case 'n', 'r', 't', 'p', 'P':
+		b.WriteString(`\` + string(c))
+	case 'b', 'B':
+		problem("word boundaries")
+	case 'f', 'v', '0':
+		problem("characters not allowed in XML")
+	case 'k':
+		problem("backreferences")
+		if end := strings.IndexByte(expr[i:], '>'); end != -1 {
+			return end
+		}
+	case 'x', 'u':
+		size := 2
+		if c == 'u' {
+			size = 4
+		}
+		if i+size < len(expr) {
+			if r, err := strconv.ParseUint(expr[i+1:i+1+size], 16, 32); err == nil {
+				xsdLiteral(b, rune(r))
+				return size
+			}
+		}
+		problem("invalid escapes")
+	case 'c':
+		problem("control escapes")
+		return 1
+	default:
+		if c >= '1' && c <= '9' {
+			problem("backreferences")
+			break
+		}
+		// Identity escape, e.g. "\/" or "\$".
+		r, size := utf8.DecodeRuneInString(expr[i:])
+		xsdLiteral(b, r)
+		return size - 1
+	}
+	return 0
+}
+
+func xsdClassEscape(ranges string, inClass bool) string {
+	if inClass {
+		return ranges
+	}
+	return "[" + ranges + "]"
+}
+
+// xsdLiteral writes the character escaped if it is a metacharacter.
+func xsdLiteral(b *strings.Builder, r rune) {
+	if strings.ContainsRune(xsdMetaChars, r) {
+		b.WriteByte('\\')
+	}
+	b.WriteRune(r)
+}
+
+// isEscapedAt reports whether the character at i is preceded by an odd number of backslashes.
+func isEscapedAt(s string, i int) bool {
+	n := 0
+	for j := i - 1; j >= 0 && s[j] == '\\'; j-- {
+		n++
+	}
+	return n%2 == 1
+}
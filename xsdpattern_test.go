@@ -0,0 +1,43 @@
+package raml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_xsdPattern(t *testing.T) {
+	tests := []struct {
+		name         string
+		expr         string
+		want         string
+		wantProblems []string
+	}{
+		{name: "anchored", expr: `^[a-z]+$`, want: `[a-z]+`},
+		{name: "unanchored", expr: `abc`, want: `[\s\S]*abc[\s\S]*`},
+		{name: "anchored start", expr: `^abc`, want: `abc[\s\S]*`},
+		{name: "escaped dollar is not an anchor", expr: `^a\$`, want: `a$[\s\S]*`},
+		{name: "digit escapes", expr: `^\d{3}-[\d_]\D$`, want: `[0-9]{3}-[0-9_][^0-9]`},
+		{name: "word escapes", expr: `^\w+$`, want: `[A-Za-z0-9_]+`},
+		{name: "non-capturing group", expr: `^(?:ab)+$`, want: `(ab)+`},
+		{name: "named group", expr: `^(?<year>\d{4})$`, want: `([0-9]{4})`},
+		{name: "top-level alternation", expr: `cat|dog`, want: `[\s\S]*(cat|dog)[\s\S]*`},
+		{name: "grouped alternation", expr: `^(cat|dog)$`, want: `(cat|dog)`},
+		{name: "identity escapes", expr: `^a\/b\.c$`, want: `a/b\.c`},
+		{name: "literal braces", expr: `^{x}$`, want: `\{x\}`},
+		{name: "hex escapes", expr: `^\x41.$`, want: `A.`},
+		{name: "lookahead", expr: `^(?=.*\d).+$`, want: `(.*[0-9]).+`, wantProblems: []string{"lookaheads"}},
+		{name: "lazy quantifier", expr: `^a+?$`, want: `a+`, wantProblems: []string{"lazy quantifiers"}},
+		{name: "backreference", expr: `^(a)\1$`, want: `(a)`, wantProblems: []string{"backreferences"}},
+		{name: "word boundary", expr: `\bword\b`, want: `[\s\S]*word[\s\S]*`, wantProblems: []string{"word boundaries"}},
+		{name: "anchors in alternatives", expr: `^a|b$`, want: `a|b`, wantProblems: []string{"anchors in alternatives"}},
+		{name: "inner anchor", expr: `a^b`, want: `[\s\S]*ab[\s\S]*`, wantProblems: []string{"anchors inside pattern"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, problems := xsdPattern(tt.expr)
+			require.Equal(t, tt.want, got)
+			require.Equal(t, tt.wantProblems, problems)
+		})
+	}
+}